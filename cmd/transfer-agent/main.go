@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/mig-controller/pkg/transfer"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func main() {
+	logf.SetLogger(logf.ZapLogger(false))
+	log := logf.Log.WithName("transfer-agent")
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: transfer-agent server|client [options]")
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "server":
+		err = server(log, os.Args[2:])
+	case "client":
+		err = client(log, os.Args[2:])
+	default:
+		err = fmt.Errorf("command %s not supported", os.Args[1])
+	}
+	if err != nil {
+		log.Error(err, "transfer-agent failed.")
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// Add the TLS flags.
+func tlsFlags(flags *flag.FlagSet) *transfer.TLS {
+	settings := &transfer.TLS{}
+	flags.StringVar(&settings.CAFile, "ca", "/etc/transfer/certs/ca.crt", "CA certificates.")
+	flags.StringVar(&settings.CertFile, "cert", "/etc/transfer/certs/tls.crt", "Certificate.")
+	flags.StringVar(&settings.KeyFile, "key", "/etc/transfer/certs/tls.key", "Private key.")
+	flags.StringVar(&settings.MinVersion, "tls-min-version", "TLSv1.2", "Minimum TLS version.")
+	return settings
+}
+
+// Run the server.
+func server(log logr.Logger, args []string) error {
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	settings := tlsFlags(flags)
+	listen := flags.String("listen", ":2222", "Listen address.")
+	root := flags.String("root", "/mnt", "Directory containing the volumes.")
+	flags.BoolVar(&settings.VerifyClient, "verify-client", true, "Verify client certificates.")
+	_ = flags.Parse(args)
+	config, err := settings.ServerConfig()
+	if err != nil {
+		return err
+	}
+	listener, err := tls.Listen("tcp", *listen, config)
+	if err != nil {
+		return err
+	}
+	log.Info("server listening.", "address", *listen, "root", *root)
+	s := &transfer.Server{
+		Root:  *root,
+		Token: os.Getenv(transfer.TokenEnv),
+		Log:   log,
+	}
+	return s.Serve(listener)
+}
+
+// Run the client.
+func client(log logr.Logger, args []string) error {
+	flags := flag.NewFlagSet("client", flag.ExitOnError)
+	settings := tlsFlags(flags)
+	endpoint := flags.String("endpoint", "", "Server <host>:<port>.")
+	flags.StringVar(&settings.ServerName, "server-name", "", "TLS server name (SNI).")
+	source := flags.String("source", "", "Directory to transfer.")
+	module := flags.String("module", "", "Destination volume.")
+	deleteExtra := flags.Bool("delete", true, "Delete destination files not found on the source.")
+	checksum := flags.Bool("checksum", false, "Compare files by checksum.")
+	bwLimit := flags.Int("bwlimit", 0, "Bandwidth limit in KB/s, 0 for no limit.")
+	progress := flags.String("progress-address", fmt.Sprintf(":%d", transfer.ProgressPort), "Progress API listen address.")
+	timeout := flags.Duration("timeout", 30*time.Second, "Connection timeout.")
+	_ = flags.Parse(args)
+	config, err := settings.ClientConfig()
+	if err != nil {
+		return err
+	}
+	tracker := transfer.NewTracker()
+	mux := http.NewServeMux()
+	mux.Handle(transfer.ProgressPath, tracker)
+	go func() {
+		err := http.ListenAndServe(*progress, mux)
+		if err != nil {
+			log.Error(err, "progress API failed.")
+		}
+	}()
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: *timeout}, "tcp", *endpoint, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	c := &transfer.Client{
+		Source:   *source,
+		Module:   *module,
+		Token:    os.Getenv(transfer.TokenEnv),
+		Delete:   *deleteExtra,
+		Checksum: *checksum,
+		BwLimit:  *bwLimit,
+		Tracker:  tracker,
+		Log:      log,
+	}
+	err = c.Run(conn)
+	p := tracker.Progress()
+	log.Info(
+		"transfer ended.",
+		"files", p.TransferredFiles,
+		"bytes", p.TransferredBytes,
+		"sent", p.SentBytes,
+		"rate", p.Rate())
+	return err
+}
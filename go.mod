@@ -13,6 +13,7 @@ require (
 	github.com/dnaeon/go-vcr v1.1.0 // indirect
 	github.com/docker/spdystream v0.0.0-20181023171402-6480d4af844c // indirect
 	github.com/elazarl/goproxy v0.0.0-20201021153353-00ad82a08272 // indirect
+	github.com/ghodss/yaml v1.0.0
 	github.com/gin-contrib/cors v1.3.1
 	github.com/gin-gonic/gin v1.6.3
 	github.com/go-logr/logr v0.3.0
@@ -22,6 +23,8 @@ require (
 	github.com/konveyor/openshift-velero-plugin v0.0.0-20201023200114-f5883b430041
 	github.com/mattn/go-sqlite3 v1.14.4
 	github.com/onsi/gomega v1.7.0
+	github.com/opencontainers/go-digest v1.0.0
+	github.com/opencontainers/image-spec v1.0.2-0.20190823105129-775207bd45b6
 	github.com/openshift/api v0.0.0-20200210091934-a0e53e94816b
 	github.com/openshift/library-go v0.0.0-20200521120150-e4959e210d3a
 	github.com/pkg/errors v0.9.1
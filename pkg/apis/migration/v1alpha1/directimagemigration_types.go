@@ -34,6 +34,54 @@ type DirectImageMigrationSpec struct {
 
 	// Holds names of all namespaces to run DIM to get all the imagestreams in these namespaces.
 	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Holds settings for migrating images that imagestreams reference from external registries.
+	ExternalImages *ExternalImageMigration `json:"externalImages,omitempty"`
+
+	// Holds the registry images are migrated to when the destination cluster registry is not used.
+	DestImageRegistry *DestImageRegistry `json:"destImageRegistry,omitempty"`
+}
+
+// ExternalImageMigration defines how images stored outside of the source cluster
+// internal registry are mirrored to the destination cluster registry.
+type ExternalImageMigration struct {
+	// If set True, images that imagestreams reference from external registries are migrated.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Registries that external images may be migrated from. An entry matches either a registry
+	// host (e.g. quay.io) or a repository prefix (e.g. quay.io/org). All registries are allowed when empty.
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// Registries that external images must not be migrated from. Takes precedence over allowedRegistries.
+	DeniedRegistries []string `json:"deniedRegistries,omitempty"`
+
+	// Reference to a `kubernetes.io/dockerconfigjson` secret holding per-registry credentials.
+	CredentialsSecretRef *kapi.ObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// If set True, image signatures along with cosign signatures and attestations are migrated with the images.
+	CopySignatures bool `json:"copySignatures,omitempty"`
+}
+
+// DestImageRegistry defines a registry, other than the destination cluster registry,
+// that images are migrated to. Used when the destination cluster is not OpenShift.
+type DestImageRegistry struct {
+	// Registry host and optional port images are pushed to, e.g. harbor.example.com.
+	Registry string `json:"registry"`
+
+	// Project (or organization) images are pushed under. Defaults to the destination namespace.
+	Project string `json:"project,omitempty"`
+
+	// Reference to a `kubernetes.io/dockerconfigjson` secret holding credentials for the registry.
+	CredentialsSecretRef *kapi.ObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// If set True, TLS verification is skipped when pushing to the registry.
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// ImageMapping maps a migrated source image repository to its destination repository.
+type ImageMapping struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
 }
 
 // DirectImageMigrationStatus defines the observed state of DirectImageMigration
@@ -48,14 +96,16 @@ type DirectImageMigrationStatus struct {
 	SuccessfulISs  []*ImageStreamListItem `json:"successfulISs,omitempty"`
 	DeletedISs     []*ImageStreamListItem `json:"deletedISs,omitempty"`
 	FailedISs      []*ImageStreamListItem `json:"failedISs,omitempty"`
+	ImageMappings  []ImageMapping         `json:"imageMappings,omitempty"`
 }
 
 type ImageStreamListItem struct {
 	*kapi.ObjectReference `json:",inline"`
-	DestNamespace         string                `json:"destNamespace,omitempty"`
-	NotFound              bool                  `json:"notFound,omitempty"`
-	DirectMigration       *kapi.ObjectReference `json:"directMigration,omitempty"`
-	Errors                []string              `json:"errors,omitempty"`
+	DestNamespace         string                   `json:"destNamespace,omitempty"`
+	NotFound              bool                     `json:"notFound,omitempty"`
+	DirectMigration       *kapi.ObjectReference    `json:"directMigration,omitempty"`
+	Errors                []string                 `json:"errors,omitempty"`
+	TagProgress           []ImageStreamTagProgress `json:"tagProgress,omitempty"`
 }
 
 // +genclient
@@ -209,10 +259,76 @@ func (r *DirectImageMigration) getDISMProgress(items []*ImageStreamListItem, sta
 			isMsg = fmt.Sprintf("ImageStream %s (dism %s): %s ", path.Join(item.Namespace, item.Name), path.Join(item.DirectMigration.Namespace, item.DirectMigration.Name), state)
 		}
 		progress = append(progress, isMsg)
+		for _, tag := range item.TagProgress {
+			progress = append(progress,
+				fmt.Sprintf("ImageStream %s %s", path.Join(item.Namespace, item.Name), tag.GetProgressMessage()))
+		}
 	}
 	return progress
 }
 
+// GetProject gets the project images migrated to the destination namespace are pushed under.
+func (r *DestImageRegistry) GetProject(destNamespace string) string {
+	if r.Project != "" {
+		return r.Project
+	}
+	return destNamespace
+}
+
+// AddImageMappings adds (de-duplicated) image mappings.
+func (r *DirectImageMigration) AddImageMappings(mappings []ImageMapping) {
+	r.Status.ImageMappings = mergeImageMappings(r.Status.ImageMappings, mappings)
+}
+
+// Merge image mappings, the last mapping for a source wins.
+func mergeImageMappings(current []ImageMapping, mappings []ImageMapping) []ImageMapping {
+	for _, mapping := range mappings {
+		found := false
+		for i := range current {
+			if current[i].Source == mapping.Source {
+				current[i].Destination = mapping.Destination
+				found = true
+				break
+			}
+		}
+		if !found {
+			current = append(current, mapping)
+		}
+	}
+	return current
+}
+
+// IsImageAllowed gets whether an external image may be migrated based on the
+// allowed and denied registries.
+func (r *ExternalImageMigration) IsImageAllowed(image string) bool {
+	if r == nil || !r.Enabled {
+		return false
+	}
+	for _, registry := range r.DeniedRegistries {
+		if registryMatches(registry, image) {
+			return false
+		}
+	}
+	if len(r.AllowedRegistries) == 0 {
+		return true
+	}
+	for _, registry := range r.AllowedRegistries {
+		if registryMatches(registry, image) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get whether the image belongs to the registry (or repository prefix).
+func registryMatches(registry string, image string) bool {
+	registry = strings.TrimSuffix(strings.TrimSpace(registry), "/")
+	if registry == "" {
+		return false
+	}
+	return image == registry || strings.HasPrefix(image, registry+"/")
+}
+
 func init() {
 	SchemeBuilder.Register(&DirectImageMigration{}, &DirectImageMigrationList{})
 }
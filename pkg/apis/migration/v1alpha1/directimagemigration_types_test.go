@@ -56,3 +56,69 @@ func TestStorageDirectImageMigration(t *testing.T) {
 	g.Expect(c.Delete(context.TODO(), fetched)).NotTo(gomega.HaveOccurred())
 	g.Expect(c.Get(context.TODO(), key, fetched)).To(gomega.HaveOccurred())
 }
+
+func TestExternalImageMigration_IsImageAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		external *ExternalImageMigration
+		image    string
+		want     bool
+	}{
+		{
+			name:     "not enabled",
+			external: &ExternalImageMigration{},
+			image:    "quay.io/org/app",
+			want:     false,
+		},
+		{
+			name:     "enabled, no allow or deny list",
+			external: &ExternalImageMigration{Enabled: true},
+			image:    "quay.io/org/app",
+			want:     true,
+		},
+		{
+			name: "registry host allowed",
+			external: &ExternalImageMigration{
+				Enabled:           true,
+				AllowedRegistries: []string{"quay.io"},
+			},
+			image: "quay.io/org/app",
+			want:  true,
+		},
+		{
+			name: "registry host not allowed",
+			external: &ExternalImageMigration{
+				Enabled:           true,
+				AllowedRegistries: []string{"quay.io"},
+			},
+			image: "docker.io/library/busybox",
+			want:  false,
+		},
+		{
+			name: "host prefix does not match a different host",
+			external: &ExternalImageMigration{
+				Enabled:           true,
+				AllowedRegistries: []string{"quay.io"},
+			},
+			image: "quay.io.example.com/org/app",
+			want:  false,
+		},
+		{
+			name: "repository prefix denied over allowed host",
+			external: &ExternalImageMigration{
+				Enabled:           true,
+				AllowedRegistries: []string{"quay.io"},
+				DeniedRegistries:  []string{"quay.io/private/"},
+			},
+			image: "quay.io/private/app",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.external.IsImageAllowed(tt.image); got != tt.want {
+				t.Errorf("IsImageAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
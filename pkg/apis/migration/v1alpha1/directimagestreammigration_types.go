@@ -18,6 +18,8 @@ package v1alpha1
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	imagev1 "github.com/openshift/api/image/v1"
 	kapi "k8s.io/api/core/v1"
@@ -33,16 +35,61 @@ type DirectImageStreamMigrationSpec struct {
 
 	//  Holds the name of the namespace on destination cluster where imagestreams should be migrated.
 	DestNamespace string `json:"destNamespace,omitempty"`
+
+	// Holds settings for migrating images that the imagestream references from external registries.
+	ExternalImages *ExternalImageMigration `json:"externalImages,omitempty"`
+
+	// Holds the registry images are migrated to when the destination cluster registry is not used.
+	DestImageRegistry *DestImageRegistry `json:"destImageRegistry,omitempty"`
 }
 
 // DirectImageStreamMigrationStatus defines the observed state of DirectImageStreamMigration
 type DirectImageStreamMigrationStatus struct {
 	Conditions     `json:","`
-	ObservedDigest string       `json:"observedDigest,omitempty"`
-	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
-	Phase          string       `json:"phase,omitempty"`
-	Itinerary      string       `json:"itinerary,omitempty"`
-	Errors         []string     `json:"errors,omitempty"`
+	ObservedDigest string                   `json:"observedDigest,omitempty"`
+	StartTimestamp *metav1.Time             `json:"startTimestamp,omitempty"`
+	Phase          string                   `json:"phase,omitempty"`
+	Itinerary      string                   `json:"itinerary,omitempty"`
+	Errors         []string                 `json:"errors,omitempty"`
+	ImageMappings  []ImageMapping           `json:"imageMappings,omitempty"`
+	TagProgress    []ImageStreamTagProgress `json:"tagProgress,omitempty"`
+}
+
+// Tag progress phases.
+const (
+	TagProgressRunning   = "Running"
+	TagProgressCompleted = "Completed"
+	TagProgressFailed    = "Failed"
+)
+
+// ImageStreamTagProgress defines the progress of the migration of an ImageStream tag.
+type ImageStreamTagProgress struct {
+	// Holds the name of the tag.
+	Tag string `json:"tag"`
+
+	// Holds the digest of the most recent image of the tag.
+	Digest string `json:"digest,omitempty"`
+
+	// Holds the phase of the tag migration: Running, Completed or Failed.
+	Phase string `json:"phase,omitempty"`
+
+	// Holds the number of layers of the tag images.
+	LayersTotal int `json:"layersTotal,omitempty"`
+
+	// Holds the number of layers copied or found already present in the destination.
+	LayersCopied int `json:"layersCopied,omitempty"`
+
+	// Holds the size in bytes of the tag image layers.
+	BytesTotal int64 `json:"bytesTotal,omitempty"`
+
+	// Holds the number of bytes copied.
+	BytesCopied int64 `json:"bytesCopied,omitempty"`
+
+	StartTimestamp      *metav1.Time `json:"startTimestamp,omitempty"`
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+
+	// Holds the error that caused the tag migration to fail.
+	Error string `json:"error,omitempty"`
 }
 
 // +genclient
@@ -113,6 +160,78 @@ func (r *DirectImageStreamMigration) AddErrors(errors []string) {
 	}
 }
 
+// AddImageMappings adds (de-duplicated) image mappings.
+func (r *DirectImageStreamMigration) AddImageMappings(mappings []ImageMapping) {
+	r.Status.ImageMappings = mergeImageMappings(r.Status.ImageMappings, mappings)
+}
+
+// GetTagProgress gets the progress of a tag, adding it to the status when not found.
+func (r *DirectImageStreamMigration) GetTagProgress(tag string) *ImageStreamTagProgress {
+	for i := range r.Status.TagProgress {
+		if r.Status.TagProgress[i].Tag == tag {
+			return &r.Status.TagProgress[i]
+		}
+	}
+	r.Status.TagProgress = append(r.Status.TagProgress, ImageStreamTagProgress{Tag: tag})
+	return &r.Status.TagProgress[len(r.Status.TagProgress)-1]
+}
+
+// GetDuration gets how long the tag migration has been running, or took to complete.
+func (r *ImageStreamTagProgress) GetDuration() time.Duration {
+	if r.StartTimestamp == nil {
+		return 0
+	}
+	if r.CompletionTimestamp == nil {
+		return time.Now().Sub(r.StartTimestamp.Time).Round(time.Second)
+	}
+	return r.CompletionTimestamp.Sub(r.StartTimestamp.Time).Round(time.Second)
+}
+
+// GetProgressMessage gets a human readable progress message for the tag.
+func (r *ImageStreamTagProgress) GetProgressMessage() string {
+	duration := ""
+	if r.StartTimestamp != nil {
+		duration = fmt.Sprintf(" (%s)", r.GetDuration())
+	}
+	switch r.Phase {
+	case TagProgressRunning, TagProgressCompleted:
+		return fmt.Sprintf("tag %s: %d out of %d layers, %s out of %s copied%s",
+			r.Tag,
+			r.LayersCopied,
+			r.LayersTotal,
+			bytesToSI(r.BytesCopied),
+			bytesToSI(r.BytesTotal),
+			duration)
+	case TagProgressFailed:
+		return fmt.Sprintf("tag %s: Failed. %d out of %d layers, %s out of %s copied%s: %s",
+			r.Tag,
+			r.LayersCopied,
+			r.LayersTotal,
+			bytesToSI(r.BytesCopied),
+			bytesToSI(r.BytesTotal),
+			duration,
+			r.Error)
+	default:
+		return fmt.Sprintf("tag %s: Waiting for image copy to start", r.Tag)
+	}
+}
+
+// Format bytes using SI units.
+func bytesToSI(bytes int64) string {
+	const baseUnit = 1000
+	if bytes < baseUnit {
+		return fmt.Sprintf("%d bytes", bytes)
+	}
+	const siUnits = "kMGTPE"
+	div, exp := int64(baseUnit), 0
+	for n := bytes / baseUnit; n >= baseUnit; n /= baseUnit {
+		div *= baseUnit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB",
+		float64(bytes)/float64(div), siUnits[exp])
+}
+
 // HasErrors will notify about error presence on the DirectImageStreamMigration resource
 func (r *DirectImageStreamMigration) HasErrors() bool {
 	return len(r.Status.Errors) > 0
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
 	"golang.org/x/net/context"
@@ -56,3 +57,66 @@ func TestStorageDirectImageStreamMigration(t *testing.T) {
 	g.Expect(c.Delete(context.TODO(), fetched)).NotTo(gomega.HaveOccurred())
 	g.Expect(c.Get(context.TODO(), key, fetched)).To(gomega.HaveOccurred())
 }
+
+func TestDirectImageStreamMigration_GetTagProgress(t *testing.T) {
+	dism := &DirectImageStreamMigration{}
+	progress := dism.GetTagProgress("latest")
+	progress.LayersTotal = 3
+	dism.GetTagProgress("v1")
+	if len(dism.Status.TagProgress) != 2 {
+		t.Fatalf("GetTagProgress() added %d tags, want 2", len(dism.Status.TagProgress))
+	}
+	if got := dism.GetTagProgress("latest").LayersTotal; got != 3 {
+		t.Errorf("GetTagProgress() LayersTotal = %d, want 3", got)
+	}
+}
+
+func TestImageStreamTagProgress_GetProgressMessage(t *testing.T) {
+	start := metav1.NewTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(90 * time.Second))
+	tests := []struct {
+		name     string
+		progress ImageStreamTagProgress
+		want     string
+	}{
+		{
+			name:     "not started",
+			progress: ImageStreamTagProgress{Tag: "latest"},
+			want:     "tag latest: Waiting for image copy to start",
+		},
+		{
+			name: "completed",
+			progress: ImageStreamTagProgress{
+				Tag:                 "latest",
+				Phase:               TagProgressCompleted,
+				LayersTotal:         2,
+				LayersCopied:        2,
+				BytesTotal:          2500000,
+				BytesCopied:         2500000,
+				StartTimestamp:      &start,
+				CompletionTimestamp: &end,
+			},
+			want: "tag latest: 2 out of 2 layers, 2.50 MB out of 2.50 MB copied (1m30s)",
+		},
+		{
+			name: "failed",
+			progress: ImageStreamTagProgress{
+				Tag:          "v1",
+				Phase:        TagProgressFailed,
+				LayersTotal:  2,
+				LayersCopied: 1,
+				BytesTotal:   2000,
+				BytesCopied:  500,
+				Error:        "unauthorized",
+			},
+			want: "tag v1: Failed. 1 out of 2 layers, 500 bytes out of 2.00 kB copied: unauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.progress.GetProgressMessage(); got != tt.want {
+				t.Errorf("GetProgressMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
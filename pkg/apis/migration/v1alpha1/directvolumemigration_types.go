@@ -17,9 +17,17 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
 	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	migref "github.com/konveyor/mig-controller/pkg/reference"
 )
 
 type PVCToMigrate struct {
@@ -27,6 +35,621 @@ type PVCToMigrate struct {
 	TargetStorageClass    string                            `json:"targetStorageClass"`
 	TargetAccessModes     []kapi.PersistentVolumeAccessMode `json:"targetAccessModes,omitEmpty"`
 	Verify                bool                              `json:"verify,omitEmpty"`
+	// The destination namespace, defaults to the source namespace.
+	// Supported when the source and destination clusters are the same.
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// The destination PVC name, defaults to the source name.
+	// Supported when the source and destination clusters are the same.
+	TargetName string `json:"targetName,omitempty"`
+}
+
+// Get the destination namespace.
+func (r *PVCToMigrate) GetTargetNamespace() string {
+	if r.TargetNamespace != "" {
+		return r.TargetNamespace
+	}
+	return r.Namespace
+}
+
+// Get the destination PVC name.
+func (r *PVCToMigrate) GetTargetName() string {
+	if r.TargetName != "" {
+		return r.TargetName
+	}
+	return r.Name
+}
+
+// Get whether the destination PVC is renamed or moved to another namespace.
+func (r *PVCToMigrate) IsRenamed() bool {
+	return r.GetTargetNamespace() != r.Namespace || r.GetTargetName() != r.Name
+}
+
+// Rsync options allowed in the transfer spec.
+// Extra options are validated against the same allow-list as the
+// options set on the controller.
+var (
+	RsyncExtraOptionRegexp = regexp.MustCompile(`^\-{1,2}[\w-]+?\w$`)
+	RsyncInfoRegexp        = regexp.MustCompile(`^\w[\w,]*?\w$`)
+)
+
+// TransferSpec tunes the volume transfer.
+// Unset fields default to the controller settings.
+type TransferSpec struct {
+	// The transfer engine, rsync (default) or agent.
+	// The agent engine replaces the rsync and stunnel pods with the transfer agent.
+	// Intra-cluster volumes are always transferred by rsync.
+	Engine string `json:"engine,omitempty"`
+
+	// The rsync options.
+	Rsync *RsyncOptions `json:"rsync,omitempty"`
+
+	// Resources of the rsync daemon (transfer) pods on the destination cluster.
+	TransferPodResources *kapi.ResourceRequirements `json:"transferPodResources,omitempty"`
+
+	// Resources of the rsync client pods on the source cluster.
+	ClientPodResources *kapi.ResourceRequirements `json:"clientPodResources,omitempty"`
+
+	// Resources of the stunnel pods on the source cluster.
+	StunnelPodResources *kapi.ResourceRequirements `json:"stunnelPodResources,omitempty"`
+
+	// Scheduling of the pods on the source cluster. The node selector is not applied
+	// to rsync client pods scheduled on the node running the pod using the PVC.
+	Source *TransferScheduling `json:"source,omitempty"`
+
+	// Scheduling of the pods on the destination cluster.
+	Destination *TransferScheduling `json:"destination,omitempty"`
+
+	// Exposes the rsync transfer pods to the source cluster, defaults to a Route.
+	Transport *TransferTransport `json:"transport,omitempty"`
+
+	// Secures the stunnel tunnel between the clusters.
+	TLS *TransferTLS `json:"tls,omitempty"`
+
+	// Limits the volumes transferred at the same time.
+	// The other volumes are queued and transferred as transfers complete.
+	Concurrency *TransferConcurrency `json:"concurrency,omitempty"`
+
+	// Limits the bandwidth of the transfers by time of day.
+	Bandwidth *TransferBandwidth `json:"bandwidth,omitempty"`
+
+	// Retries the transfers failed with a transient error.
+	Retry *TransferRetry `json:"retry,omitempty"`
+
+	// Transfers the volumes from CSI snapshot clones of the source PVCs.
+	// The applications are not quiesced, the data is crash-consistent.
+	Snapshot *TransferSnapshot `json:"snapshot,omitempty"`
+}
+
+// Days of the week of the bandwidth windows.
+var WeekDays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// TransferBandwidth limits the bandwidth of the transfers by time of day.
+// The transfers are restarted with the new limit at the window boundaries
+// and resume from the partially transferred files.
+type TransferBandwidth struct {
+	// The time zone (IANA name) of the windows, defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// The windows. The first window including the time applies,
+	// the rsync bwLimit applies outside the windows.
+	Windows []BandwidthWindow `json:"windows,omitempty"`
+}
+
+// BandwidthWindow limits the bandwidth during a time of day.
+type BandwidthWindow struct {
+	// The days (Mon, Tue, Wed, Thu, Fri, Sat, Sun) the window starts,
+	// defaults to every day.
+	Days []string `json:"days,omitempty"`
+	// The start time (HH:MM).
+	Start string `json:"start"`
+	// The end time (HH:MM), excluded. A window ending before its start
+	// ends the next day.
+	End string `json:"end"`
+	// The bandwidth limit in KB/s, -1 for no limit.
+	BwLimit int `json:"bwLimit"`
+}
+
+// Get the start and end of the window in minutes since midnight.
+func (r BandwidthWindow) Minutes() (int, int, error) {
+	minutes := []int{}
+	for _, hhmm := range []string{r.Start, r.End} {
+		parsed, err := time.Parse("15:04", hhmm)
+		if err != nil {
+			return 0, 0, err
+		}
+		minutes = append(minutes, parsed.Hour()*60+parsed.Minute())
+	}
+	return minutes[0], minutes[1], nil
+}
+
+// Get whether the window starts on a day, every day when no days are set.
+func (r BandwidthWindow) StartsOn(day time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, name := range r.Days {
+		if d, found := WeekDays[name]; found && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the window in a readable format, e.g. "Mon,Fri 08:00-18:00".
+func (r BandwidthWindow) String() string {
+	if len(r.Days) == 0 {
+		return fmt.Sprintf("%s-%s", r.Start, r.End)
+	}
+	return fmt.Sprintf("%s %s-%s", strings.Join(r.Days, ","), r.Start, r.End)
+}
+
+// Get whether bandwidth windows are set.
+func (r *TransferSpec) HasBandwidthSchedule() bool {
+	return r != nil && r.Bandwidth != nil && len(r.Bandwidth.Windows) > 0
+}
+
+// Get the problems of the bandwidth schedule.
+func (r *TransferSpec) InvalidBandwidth() []string {
+	invalid := []string{}
+	if !r.HasBandwidthSchedule() {
+		return invalid
+	}
+	if _, err := time.LoadLocation(r.Bandwidth.TimeZone); err != nil {
+		invalid = append(invalid, fmt.Sprintf("timeZone: %s not valid", r.Bandwidth.TimeZone))
+	}
+	for i, window := range r.Bandwidth.Windows {
+		start, end, err := window.Minutes()
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("windows[%d]: %s-%s not HH:MM", i, window.Start, window.End))
+		} else if start == end {
+			invalid = append(invalid, fmt.Sprintf("windows[%d]: %s-%s empty", i, window.Start, window.End))
+		}
+		for _, day := range window.Days {
+			if _, found := WeekDays[day]; !found {
+				invalid = append(invalid, fmt.Sprintf("windows[%d]: day %s not valid", i, day))
+			}
+		}
+		if window.BwLimit < -1 {
+			invalid = append(invalid, fmt.Sprintf("windows[%d]: bwLimit %d not valid", i, window.BwLimit))
+		}
+	}
+	return invalid
+}
+
+// BandwidthStatus reports the active bandwidth limit of the transfers.
+type BandwidthStatus struct {
+	// The limit in KB/s, -1 for no limit.
+	BwLimit int `json:"bwLimit"`
+	// The active window, empty outside the windows.
+	Window string `json:"window,omitempty"`
+	// The next window boundary, when the limit changes.
+	Until *metav1.Time `json:"until,omitempty"`
+}
+
+// Transfer retry defaults.
+const (
+	DefaultTransferMaxAttempts = 3
+	DefaultTransferBackoff     = 30 * time.Second
+	DefaultTransferMaxBackoff  = 10 * time.Minute
+)
+
+// TransferRetry retries the transfers failed with a transient error
+// (partial transfer, vanished files, timeout, connection reset).
+// The delay doubles after each failed attempt.
+type TransferRetry struct {
+	// The attempts of each volume transfer, 1 disables the retries.
+	// Defaults to 3.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// The delay before the first retry. Defaults to 30s.
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// The maximum delay between retries. Defaults to 10m.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// Get the retry spec with defaults.
+func (r *TransferSpec) GetRetry() TransferRetry {
+	retry := TransferRetry{}
+	if r != nil && r.Retry != nil {
+		retry = *r.Retry
+	}
+	if retry.MaxAttempts == 0 {
+		retry.MaxAttempts = DefaultTransferMaxAttempts
+	}
+	if retry.Backoff == nil {
+		retry.Backoff = &metav1.Duration{Duration: DefaultTransferBackoff}
+	}
+	if retry.MaxBackoff == nil {
+		retry.MaxBackoff = &metav1.Duration{Duration: DefaultTransferMaxBackoff}
+	}
+	return retry
+}
+
+// Get the delay before retrying a transfer failed a number of times.
+func (r TransferRetry) Delay(failures int) time.Duration {
+	delay := r.Backoff.Duration
+	for i := 1; i < failures && delay < r.MaxBackoff.Duration; i++ {
+		delay *= 2
+	}
+	if delay > r.MaxBackoff.Duration {
+		delay = r.MaxBackoff.Duration
+	}
+	return delay
+}
+
+// Get the problems of the retry spec.
+func (r *TransferSpec) InvalidRetry() []string {
+	invalid := []string{}
+	retry := r.GetRetry()
+	if retry.MaxAttempts < 0 {
+		invalid = append(invalid, fmt.Sprintf("maxAttempts: %d not valid", retry.MaxAttempts))
+	}
+	if retry.Backoff.Duration < 0 {
+		invalid = append(invalid, fmt.Sprintf("backoff: %s not valid", retry.Backoff.Duration))
+	}
+	if retry.MaxBackoff.Duration < retry.Backoff.Duration {
+		invalid = append(invalid, fmt.Sprintf("maxBackoff: %s less than backoff", retry.MaxBackoff.Duration))
+	}
+	return invalid
+}
+
+// TransferSnapshot transfers the volumes from snapshot clones.
+// A CSI VolumeSnapshot of each source PVC is taken and restored to a
+// temporary PVC mounted by the client pod. The snapshots and the clones
+// are deleted with the transfer resources. The source cluster must serve
+// the snapshot.storage.k8s.io/v1 API.
+type TransferSnapshot struct {
+	// The VolumeSnapshotClass of the snapshots.
+	// Defaults to the default class of the CSI driver.
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// Get whether the volumes are transferred from snapshot clones.
+func (r *TransferSpec) HasSnapshot() bool {
+	return r != nil && r.Snapshot != nil
+}
+
+// VolumeAttempts reports the failed attempts of a volume transfer.
+type VolumeAttempts struct {
+	// The PVC.
+	*kapi.ObjectReference `json:",inline"`
+	// The failed attempts, oldest first.
+	Attempts []TransferAttempt `json:"attempts,omitempty"`
+	// When the transfer is retried.
+	RetryAt *metav1.Time `json:"retryAt,omitempty"`
+}
+
+// TransferAttempt reports a failed attempt of a volume transfer.
+type TransferAttempt struct {
+	// When the client pod was created.
+	Started metav1.Time `json:"started"`
+	// When the failure was observed.
+	Failed metav1.Time `json:"failed"`
+	// The exit code of the client container.
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// The failure class, e.g. Timeout or ConnectionReset.
+	Reason string `json:"reason"`
+	// Whether the failure is transient.
+	Retryable bool `json:"retryable"`
+	// The termination message of the client container.
+	Message string `json:"message,omitempty"`
+}
+
+// Transfer orders.
+// Spec - The order of the PVCs in the spec.
+// SmallestFirst - The smallest PVCs first.
+// LargestFirst - The largest PVCs first.
+// Priority - The PVCs with the highest priority annotation first.
+const (
+	TransferOrderSpec          = "Spec"
+	TransferOrderSmallestFirst = "SmallestFirst"
+	TransferOrderLargestFirst  = "LargestFirst"
+	TransferOrderPriority      = "Priority"
+)
+
+// Annotation on source PVCs with the (integer) transfer priority, higher first.
+const TransferPriorityAnnotation = "migration.openshift.io/transfer-priority"
+
+// TransferConcurrency limits the volumes transferred at the same time.
+// Unset (0) limits are unlimited.
+type TransferConcurrency struct {
+	// The maximum number of volumes transferred at the same time.
+	MaxTransfers int `json:"maxTransfers,omitempty"`
+	// The maximum number of volumes transferred at the same time from a source node.
+	MaxTransfersPerNode int `json:"maxTransfersPerNode,omitempty"`
+	// The maximum number of volumes transferred at the same time from a namespace.
+	MaxTransfersPerNamespace int `json:"maxTransfersPerNamespace,omitempty"`
+	// The order the volumes are transferred, Spec (default), SmallestFirst,
+	// LargestFirst or Priority.
+	Order string `json:"order,omitempty"`
+}
+
+// Get the concurrency spec with defaults.
+func (r *TransferSpec) GetConcurrency() TransferConcurrency {
+	concurrency := TransferConcurrency{}
+	if r != nil && r.Concurrency != nil {
+		concurrency = *r.Concurrency
+	}
+	if concurrency.Order == "" {
+		concurrency.Order = TransferOrderSpec
+	}
+	return concurrency
+}
+
+// Get whether any transfer limit is set.
+func (r TransferConcurrency) Limited() bool {
+	return r.MaxTransfers > 0 || r.MaxTransfersPerNode > 0 || r.MaxTransfersPerNamespace > 0
+}
+
+// Get the problems of the concurrency spec.
+func (r *TransferSpec) InvalidConcurrency() []string {
+	invalid := []string{}
+	concurrency := r.GetConcurrency()
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"maxTransfers", concurrency.MaxTransfers},
+		{"maxTransfersPerNode", concurrency.MaxTransfersPerNode},
+		{"maxTransfersPerNamespace", concurrency.MaxTransfersPerNamespace},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			invalid = append(invalid, fmt.Sprintf("%s: %d not valid", limit.name, limit.value))
+		}
+	}
+	switch concurrency.Order {
+	case TransferOrderSpec, TransferOrderSmallestFirst, TransferOrderLargestFirst, TransferOrderPriority:
+	default:
+		invalid = append(invalid, fmt.Sprintf("order: %s not supported", concurrency.Order))
+	}
+	return invalid
+}
+
+// Transfer engines.
+// rsync - rsync tunneled through stunnel.
+// agent - The transfer agent with built-in TLS, resume and progress reporting.
+const (
+	TransferEngineRsync = "rsync"
+	TransferEngineAgent = "agent"
+)
+
+// Get the transfer engine, defaults to rsync.
+func (r *TransferSpec) GetEngine() string {
+	if r == nil || r.Engine == "" {
+		return TransferEngineRsync
+	}
+	return r.Engine
+}
+
+// Get the problems of the transfer engine.
+func (r *TransferSpec) InvalidEngine() []string {
+	invalid := []string{}
+	switch engine := r.GetEngine(); engine {
+	case TransferEngineRsync, TransferEngineAgent:
+	default:
+		invalid = append(invalid, fmt.Sprintf("engine: %s not supported", engine))
+	}
+	return invalid
+}
+
+// TLS versions.
+const (
+	TLSv12 = "TLSv1.2"
+	TLSv13 = "TLSv1.3"
+)
+
+// Ciphers allowed in the TLS spec (OpenSSL cipher list format).
+var TLSCiphersRegexp = regexp.MustCompile(`^[\w!+@=.:-]+$`)
+
+// TransferTLS secures the stunnel tunnel between the clusters.
+type TransferTLS struct {
+	// The minimum TLS version, TLSv1.2 (default) or TLSv1.3.
+	// The highest version supported by both ends is negotiated.
+	MinVersion string `json:"minVersion,omitempty"`
+	// The TLSv1.2 cipher list in OpenSSL format.
+	Ciphers string `json:"ciphers,omitempty"`
+	// The TLSv1.3 cipher suites in OpenSSL format.
+	CipherSuites string `json:"cipherSuites,omitempty"`
+	// Whether the destination verifies the client certificates (mTLS), defaults to true.
+	VerifyClient *bool `json:"verifyClient,omitempty"`
+	// A secret on the host cluster with the CA (tls.crt and tls.key) issuing the
+	// certificates, such as the secret of a cert-manager CA Issuer.
+	// Defaults to a self-signed CA generated for each migration.
+	CASecretRef *kapi.ObjectReference `json:"caSecretRef,omitempty"`
+	// The validity of the issued certificates, defaults to 1 year.
+	// The certificates do not outlive the CA.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// Get the TLS spec with defaults.
+func (r *TransferSpec) GetTLS() TransferTLS {
+	tls := TransferTLS{}
+	if r != nil && r.TLS != nil {
+		tls = *r.TLS
+	}
+	if tls.MinVersion == "" {
+		tls.MinVersion = TLSv12
+	}
+	if tls.VerifyClient == nil {
+		verify := true
+		tls.VerifyClient = &verify
+	}
+	if tls.Duration == nil {
+		tls.Duration = &metav1.Duration{Duration: 365 * 24 * time.Hour}
+	}
+	return tls
+}
+
+// Get the problems of the TLS spec.
+func (r *TransferSpec) InvalidTLS() []string {
+	invalid := []string{}
+	tls := r.GetTLS()
+	switch tls.MinVersion {
+	case TLSv12, TLSv13:
+	default:
+		invalid = append(invalid, fmt.Sprintf("minVersion: %s not supported", tls.MinVersion))
+	}
+	if tls.Ciphers != "" && !TLSCiphersRegexp.MatchString(tls.Ciphers) {
+		invalid = append(invalid, fmt.Sprintf("ciphers: %s not valid", tls.Ciphers))
+	}
+	if tls.CipherSuites != "" && !TLSCiphersRegexp.MatchString(tls.CipherSuites) {
+		invalid = append(invalid, fmt.Sprintf("cipherSuites: %s not valid", tls.CipherSuites))
+	}
+	if tls.Duration.Duration <= 0 {
+		invalid = append(invalid, fmt.Sprintf("duration: %s not valid", tls.Duration.Duration))
+	}
+	return invalid
+}
+
+// Get the problems of the CA secret referenced by the TLS spec.
+func (r *TransferSpec) InvalidCASecret(client k8sclient.Client) ([]string, error) {
+	invalid := []string{}
+	ref := r.GetTLS().CASecretRef
+	if ref == nil {
+		return invalid, nil
+	}
+	secret, err := GetSecret(client, ref)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		invalid = append(invalid, fmt.Sprintf("caSecretRef: %s/%s not found", ref.Namespace, ref.Name))
+		return invalid, nil
+	}
+	for _, key := range []string{kapi.TLSCertKey, kapi.TLSPrivateKeyKey} {
+		if len(secret.Data[key]) == 0 {
+			invalid = append(invalid, fmt.Sprintf("caSecretRef: %s not found", key))
+		}
+	}
+	return invalid, nil
+}
+
+// Transport types.
+const (
+	TransportRoute        = "Route"
+	TransportLoadBalancer = "LoadBalancer"
+	TransportNodePort     = "NodePort"
+	TransportIngress      = "Ingress"
+	TransportEndpoint     = "Endpoint"
+)
+
+// TransferTransport exposes the rsync transfer pods on the destination cluster.
+// Route - An OpenShift Route with TLS passthrough.
+// LoadBalancer - A LoadBalancer Service.
+// NodePort - A NodePort Service reached through a node address.
+// Ingress - An Ingress with TLS (SNI) passthrough.
+// Endpoint - An endpoint exposed by the user for each namespace.
+type TransferTransport struct {
+	// The transport type, defaults to Route.
+	Type string `json:"type,omitempty"`
+	// Annotations added to the Service (LoadBalancer) or Ingress.
+	// Overrides the Ingress annotations of the controller settings.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// The address of the destination node reached by NodePort transports.
+	// Defaults to the external (or internal) address of a destination node.
+	NodeAddress string `json:"nodeAddress,omitempty"`
+	// The class of the Ingress, defaults to the class of the controller settings.
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// The domain of the Ingress hosts, required by Ingress transports.
+	IngressDomain string `json:"ingressDomain,omitempty"`
+	// The address of the Ingress controller, defaults to the Ingress host.
+	IngressAddress string `json:"ingressAddress,omitempty"`
+	// The <host>:<port> endpoints keyed by migrated namespace, required
+	// by Endpoint transports.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+}
+
+// Get the transport, defaults to a Route.
+func (r *TransferSpec) GetTransport() TransferTransport {
+	transport := TransferTransport{}
+	if r != nil && r.Transport != nil {
+		transport = *r.Transport
+	}
+	if transport.Type == "" {
+		transport.Type = TransportRoute
+	}
+	return transport
+}
+
+// Get the problems of the transport.
+// The namespaces are the migrated namespaces.
+func (r *TransferSpec) InvalidTransport(namespaces []string) []string {
+	invalid := []string{}
+	transport := r.GetTransport()
+	switch transport.Type {
+	case TransportRoute, TransportLoadBalancer, TransportNodePort:
+	case TransportIngress:
+		if transport.IngressDomain == "" {
+			invalid = append(invalid, "ingressDomain: not set")
+		}
+	case TransportEndpoint:
+		for _, ns := range namespaces {
+			endpoint, found := transport.Endpoints[ns]
+			if !found {
+				invalid = append(invalid, fmt.Sprintf("endpoints: %s not set", ns))
+				continue
+			}
+			host, port, err := net.SplitHostPort(endpoint)
+			if err != nil || host == "" || port == "" {
+				invalid = append(invalid, fmt.Sprintf("endpoints: %s not <host>:<port>", endpoint))
+			}
+		}
+	default:
+		invalid = append(invalid, fmt.Sprintf("type: %s not supported", transport.Type))
+	}
+	return invalid
+}
+
+// RsyncOptions overrides the rsync options set on the controller.
+type RsyncOptions struct {
+	// Equivalent to --bwlimit=<KiB/s>, no limit when -1.
+	BwLimit *int `json:"bwLimit,omitempty"`
+	// Whether to set the --archive option.
+	Archive *bool `json:"archive,omitempty"`
+	// Whether to set the --partial option.
+	Partial *bool `json:"partial,omitempty"`
+	// Whether to set the --delete option.
+	Delete *bool `json:"delete,omitempty"`
+	// Whether to set the --hard-links option.
+	HardLinks *bool `json:"hardLinks,omitempty"`
+	// Equivalent to --info=<flags>.
+	Info string `json:"info,omitempty"`
+	// Extra rsync options.
+	Extras []string `json:"extras,omitempty"`
+}
+
+// TransferScheduling selects the nodes running the transfer pods.
+type TransferScheduling struct {
+	NodeSelector      map[string]string `json:"nodeSelector,omitempty"`
+	Tolerations       []kapi.Toleration `json:"tolerations,omitempty"`
+	PriorityClassName string            `json:"priorityClassName,omitempty"`
+}
+
+// Get the invalid rsync options.
+func (r *TransferSpec) InvalidRsyncOptions() []string {
+	invalid := []string{}
+	if r == nil || r.Rsync == nil {
+		return invalid
+	}
+	if r.Rsync.BwLimit != nil && *r.Rsync.BwLimit < -1 {
+		invalid = append(invalid, fmt.Sprintf("--bwlimit=%d", *r.Rsync.BwLimit))
+	}
+	if r.Rsync.Info != "" && !RsyncInfoRegexp.MatchString(r.Rsync.Info) {
+		invalid = append(invalid, fmt.Sprintf("--info=%s", r.Rsync.Info))
+	}
+	for _, option := range r.Rsync.Extras {
+		if !RsyncExtraOptionRegexp.MatchString(option) {
+			invalid = append(invalid, option)
+		}
+	}
+	return invalid
 }
 
 // DirectVolumeMigrationSpec defines the desired state of DirectVolumeMigration
@@ -42,6 +665,9 @@ type DirectVolumeMigrationSpec struct {
 
 	// Specifies if progress reporting CRs needs to be deleted or not
 	DeleteProgressReportingCRs bool `json:"deleteProgressReportingCRs,omitempty"`
+
+	// Tunes the volume transfer, defaults to the controller settings.
+	Transfer *TransferSpec `json:"transfer,omitempty"`
 }
 
 // DirectVolumeMigrationStatus defines the observed state of DirectVolumeMigration
@@ -57,6 +683,12 @@ type DirectVolumeMigrationStatus struct {
 	FailedPods       []*PodProgress `json:"failedPods,omitempty"`
 	RunningPods      []*PodProgress `json:"runningPods,omitempty"`
 	PendingPods      []*PodProgress `json:"pendingPods,omitempty"`
+	// The expiry of the stunnel certificates.
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+	// The active bandwidth limit of the transfers.
+	Bandwidth *BandwidthStatus `json:"bandwidth,omitempty"`
+	// The failed attempts of the volume transfers.
+	VolumeAttempts []*VolumeAttempts `json:"volumeAttempts,omitempty"`
 }
 
 // TODO: Explore how to reliably get stunnel+rsync logs/status reported back to
@@ -99,6 +731,42 @@ func (r *DirectVolumeMigration) GetDestinationCluster(client k8sclient.Client) (
 	return GetCluster(client, r.Spec.DestMigClusterRef)
 }
 
+// Get whether the source and destination clusters are the same.
+// The volumes are transferred within the cluster, without routes and tunnels.
+func (r *DirectVolumeMigration) IsIntraCluster() bool {
+	src, dest := r.Spec.SrcMigClusterRef, r.Spec.DestMigClusterRef
+	return migref.RefSet(src) && migref.RefSet(dest) &&
+		src.Namespace == dest.Namespace &&
+		src.Name == dest.Name
+}
+
+// Get the invalid destination PVCs.
+// Within a cluster, the destination must differ from the source and be
+// distinct. Across clusters, the PVCs cannot be renamed.
+func (r *DirectVolumeMigration) InvalidTargets() []string {
+	invalid := []string{}
+	intraCluster := r.IsIntraCluster()
+	targets := map[string]bool{}
+	for i := range r.Spec.PersistentVolumeClaims {
+		pvc := &r.Spec.PersistentVolumeClaims[i]
+		if pvc.ObjectReference == nil {
+			continue
+		}
+		source := pvc.Namespace + "/" + pvc.Name
+		target := pvc.GetTargetNamespace() + "/" + pvc.GetTargetName()
+		switch {
+		case !intraCluster && pvc.IsRenamed():
+			invalid = append(invalid, source+": renamed across clusters")
+		case intraCluster && !pvc.IsRenamed():
+			invalid = append(invalid, source+": same as the source")
+		case targets[target]:
+			invalid = append(invalid, source+": "+target+" not distinct")
+		}
+		targets[target] = true
+	}
+	return invalid
+}
+
 func (r *DirectVolumeMigration) GetMigrationForDVM(client k8sclient.Client) (*MigMigration, error) {
 	return GetMigrationForDVM(client, r.OwnerReferences)
 }
@@ -29,6 +29,8 @@ import (
 type DirectVolumeMigrationProgressSpec struct {
 	ClusterRef *kapi.ObjectReference `json:"clusterRef,omitempty"`
 	PodRef     *kapi.ObjectReference `json:"podRef,omitempty"`
+	// The transfer engine running in the pod, defaults to rsync.
+	Engine string `json:"engine,omitempty"`
 }
 
 // DirectVolumeMigrationProgressStatus defines the observed state of DirectVolumeMigrationProgress
@@ -41,6 +43,10 @@ type DirectVolumeMigrationProgressStatus struct {
 	ObservedDigest              string           `json:"observedDigest,omitempty"`
 	LastObservedProgressPercent string           `json:"lastObservedProgressPercent,omitempty"`
 	LastObservedTransferRate    string           `json:"lastObservedTransferRate,omitempty"`
+	TotalBytes                  *int64           `json:"totalBytes,omitempty"`
+	TransferredBytes            *int64           `json:"transferredBytes,omitempty"`
+	// The position of the transfer in the DVM queue, 0 when not queued.
+	QueuePosition int `json:"queuePosition,omitempty"`
 }
 
 // +genclient
@@ -53,6 +59,7 @@ type DirectVolumeMigrationProgressStatus struct {
 // +kubebuilder:printcolumn:name="Pod Namespace",type=string,JSONPath=".spec.podRef.namespace"
 // +kubebuilder:printcolumn:name="Progress Percent",type=string,JSONPath=".status.lastObservedProgressPercent"
 // +kubebuilder:printcolumn:name="Transfer Rate",type=string,JSONPath=".status.lastObservedTransferRate"
+// +kubebuilder:printcolumn:name="Queue Position",type=integer,JSONPath=".status.queuePosition"
 // +kubebuilder:printcolumn:name="age",type=date,JSONPath=".metadata.creationTimestamp"
 // +k8s:openapi-gen=true
 type DirectVolumeMigrationProgress struct {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ import (
 	liberr "github.com/konveyor/controller/pkg/error"
 	pvdr "github.com/konveyor/mig-controller/pkg/cloudprovider"
 	"github.com/konveyor/mig-controller/pkg/compat"
+	"github.com/konveyor/mig-controller/pkg/proxy"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -40,9 +42,12 @@ import (
 	kapi "k8s.io/api/core/v1"
 	storageapi "k8s.io/api/storage/v1"
 	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -51,12 +56,27 @@ const (
 	SaToken = "saToken"
 )
 
+// Kubeconfig secret keys.
+const (
+	KubeconfigKey = "kubeconfig"
+)
+
+// Health condition types.
+const (
+	VeleroNotReady     = "VeleroNotReady"
+	ResticNotReady     = "ResticNotReady"
+	NoSchedulableNodes = "NoSchedulableNodes"
+	RegistryNotHealthy = "RegistryNotHealthy"
+	HighAPILatency     = "HighAPILatency"
+)
+
 // migration-cluster-config configmap
 const (
 	ClusterConfigMapName  = "migration-cluster-config"
 	RegistryImageKey      = "REGISTRY_IMAGE"
 	StagePodImageKey      = "STAGE_IMAGE"
 	RsyncTransferImageKey = "RSYNC_TRANSFER_IMAGE"
+	TransferAgentImageKey = "TRANSFER_AGENT_IMAGE"
 	ClusterSubdomainKey   = "CLUSTER_SUBDOMAIN"
 	OperatorVersionKey    = "OPERATOR_VERSION"
 )
@@ -88,14 +108,70 @@ type MigClusterSpec struct {
 
 	// Stores the path of registry route when using direct migration.
 	ExposedRegistryPath string `json:"exposedRegistryPath,omitempty"`
+
+	// References a secret holding a kubeconfig (key: `kubeconfig`) used to connect to the cluster instead of the
+	// `url`, `serviceAccountSecretRef`, `caBundle` and `insecure` settings. Client certificates, tokens and
+	// exec or auth provider plugins must be embedded in the kubeconfig, file references are not supported.
+	KubeconfigSecretRef *kapi.ObjectReference `json:"kubeconfigSecretRef,omitempty"`
+
+	// Specifies the kubeconfig context used to connect to the cluster. The current context is used when not set.
+	KubeconfigContext string `json:"kubeconfigContext,omitempty"`
+
+	// Specifies the URL of an HTTP proxy used to connect to the cluster.
+	ProxyURL string `json:"proxyURL,omitempty"`
 }
 
 // MigClusterStatus defines the observed state of MigCluster
 type MigClusterStatus struct {
 	Conditions      `json:","`
-	ObservedDigest  string `json:"observedDigest,omitempty"`
-	RegistryPath    string `json:"registryPath,omitempty"`
-	OperatorVersion string `json:"operatorVersion,omitempty"`
+	ObservedDigest  string         `json:"observedDigest,omitempty"`
+	RegistryPath    string         `json:"registryPath,omitempty"`
+	OperatorVersion string         `json:"operatorVersion,omitempty"`
+	Health          *ClusterHealth `json:"health,omitempty"`
+}
+
+// ClusterHealth defines the observed health of the cluster.
+type ClusterHealth struct {
+	// Holds the time the health has been last checked.
+	LastCheckTimestamp *metav1.Time `json:"lastCheckTimestamp,omitempty"`
+
+	// Holds the readiness of the velero pods.
+	Velero ComponentHealth `json:"velero,omitempty"`
+
+	// Holds the readiness of the restic pods.
+	Restic ComponentHealth `json:"restic,omitempty"`
+
+	// Holds the number of nodes.
+	Nodes int `json:"nodes,omitempty"`
+
+	// Holds the number of ready nodes pods can be scheduled on.
+	SchedulableNodes int `json:"schedulableNodes,omitempty"`
+
+	// Holds the CPU allocatable on the schedulable nodes.
+	AllocatableCPU *resource.Quantity `json:"allocatableCPU,omitempty"`
+
+	// Holds the memory allocatable on the schedulable nodes.
+	AllocatableMemory *resource.Quantity `json:"allocatableMemory,omitempty"`
+
+	// Holds the names of the storage classes.
+	StorageClasses []string `json:"storageClasses,omitempty"`
+
+	// Holds whether the registry is reachable. Not set when the cluster has no registry path.
+	RegistryReachable *bool `json:"registryReachable,omitempty"`
+
+	// Holds the latency of a request to the cluster API.
+	APILatency *metav1.Duration `json:"apiLatency,omitempty"`
+}
+
+// ComponentHealth defines the readiness of the pods of a component.
+type ComponentHealth struct {
+	Desired int `json:"desired"`
+	Ready   int `json:"ready"`
+}
+
+// IsReady gets whether the desired pods are ready.
+func (r ComponentHealth) IsReady() bool {
+	return r.Desired > 0 && r.Ready >= r.Desired
 }
 
 // +genclient
@@ -203,6 +279,23 @@ func (m *MigCluster) GetRsyncTransferImage(c k8sclient.Client) (string, error) {
 	return rsyncImage, nil
 }
 
+// GetTransferAgentImage gets a MigCluster specific transfer agent image from ConfigMap
+func (m *MigCluster) GetTransferAgentImage(c k8sclient.Client) (string, error) {
+	client, err := m.GetClient(c)
+	if err != nil {
+		return "", err
+	}
+	clusterConfig, err := m.GetClusterConfigMap(client)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	agentImage, ok := clusterConfig.Data[TransferAgentImageKey]
+	if !ok {
+		return "", liberr.Wrap(errors.Errorf("configmap key not found: %v", TransferAgentImageKey))
+	}
+	return agentImage, nil
+}
+
 // GetClusterSubdomain gets a MigCluster specific subdomain value to be used for DVM routes
 func (m *MigCluster) GetClusterSubdomain(c k8sclient.Client) (string, error) {
 	client, err := m.GetClient(c)
@@ -259,6 +352,34 @@ func (m *MigCluster) BuildRestConfig(c k8sclient.Client) (*rest.Config, error) {
 	if m.Spec.IsHostCluster {
 		return config.GetConfig()
 	}
+	var restConfig *rest.Config
+	var err error
+	if m.UsesKubeconfig() {
+		restConfig, err = m.buildKubeconfigRestConfig(c)
+	} else {
+		restConfig, err = m.buildSaTokenRestConfig(c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Burst = 1000
+	restConfig.QPS = 100
+	if m.Spec.ProxyURL != "" {
+		proxyURL, err := url.Parse(m.Spec.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		restConfig.Dial, err = proxy.NewDialer(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return restConfig, nil
+}
+
+// Build a REST configuration using the service account token.
+func (m *MigCluster) buildSaTokenRestConfig(c k8sclient.Client) (*rest.Config, error) {
 	secret, err := GetSecret(c, m.Spec.ServiceAccountSecretRef)
 	if err != nil {
 		return nil, err
@@ -276,13 +397,58 @@ func (m *MigCluster) BuildRestConfig(c k8sclient.Client) (*rest.Config, error) {
 		Host:            m.Spec.URL,
 		BearerToken:     string(secret.Data[SaToken]),
 		TLSClientConfig: tlsClientConfig,
-		Burst:           1000,
-		QPS:             100,
 	}
 
 	return restConfig, nil
 }
 
+// Build a REST configuration using the selected kubeconfig context.
+func (m *MigCluster) buildKubeconfigRestConfig(c k8sclient.Client) (*rest.Config, error) {
+	kubeconfig, err := m.GetKubeconfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if kubeconfig == nil {
+		return nil, errors.Errorf("Kubeconfig Secret not found for %v", m.Name)
+	}
+	return clientcmd.NewNonInteractiveClientConfig(
+		*kubeconfig,
+		m.Spec.KubeconfigContext,
+		&clientcmd.ConfigOverrides{},
+		nil).ClientConfig()
+}
+
+// UsesKubeconfig gets whether the cluster is connected to using a kubeconfig.
+func (m *MigCluster) UsesKubeconfig() bool {
+	return !m.Spec.IsHostCluster && m.Spec.KubeconfigSecretRef != nil
+}
+
+// GetKubeconfig gets the kubeconfig stored in the referenced secret.
+// Returns `nil` when the secret cannot be found.
+func (m *MigCluster) GetKubeconfig(client k8sclient.Client) (*clientcmdapi.Config, error) {
+	secret, err := GetSecret(client, m.Spec.KubeconfigSecretRef)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, nil
+	}
+	content, found := secret.Data[KubeconfigKey]
+	if !found {
+		return nil, errors.Errorf("key `%s` not found in kubeconfig secret", KubeconfigKey)
+	}
+	return clientcmd.Load(content)
+}
+
+// GetCredentialsSecretRef gets the reference to the secret holding the
+// credentials used to connect to the cluster.
+func (m *MigCluster) GetCredentialsSecretRef() *kapi.ObjectReference {
+	if m.UsesKubeconfig() {
+		return m.Spec.KubeconfigSecretRef
+	}
+	return m.Spec.ServiceAccountSecretRef
+}
+
 // Test whether OPERATOR_VERSION in configmap on MigCluster matches status.OperatorVersion
 func (m *MigCluster) OperatorVersionMatchesConfigmap(c k8sclient.Client) (bool, error) {
 	clusterClient, err := m.GetClient(c)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/onsi/gomega"
 	"golang.org/x/net/context"
+	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -56,3 +57,65 @@ func TestStorageMigCluster(t *testing.T) {
 	g.Expect(c.Delete(context.TODO(), fetched)).NotTo(gomega.HaveOccurred())
 	g.Expect(c.Get(context.TODO(), key, fetched)).To(gomega.HaveOccurred())
 }
+
+func TestMigCluster_BuildRestConfig_Kubeconfig(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: admin
+contexts:
+- name: admin
+  context:
+    cluster: remote
+    user: admin
+- name: oidc
+  context:
+    cluster: remote
+    user: oidc
+clusters:
+- name: remote
+  cluster:
+    server: https://api.remote.example.com:6443
+users:
+- name: admin
+  user:
+    client-certificate-data: Y2VydA==
+    client-key-data: a2V5
+- name: oidc
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: get-token
+`
+	secret := &kapi.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubeconfig",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			KubeconfigKey: []byte(kubeconfig),
+		},
+	}
+	g.Expect(c.Create(context.TODO(), secret)).NotTo(gomega.HaveOccurred())
+	defer c.Delete(context.TODO(), secret)
+
+	cluster := &MigCluster{
+		Spec: MigClusterSpec{
+			KubeconfigSecretRef: &kapi.ObjectReference{Name: "kubeconfig", Namespace: "default"},
+			ProxyURL:            "http://proxy.example.com:3128",
+		},
+	}
+	restConfig, err := cluster.BuildRestConfig(c)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(restConfig.Host).To(gomega.Equal("https://api.remote.example.com:6443"))
+	g.Expect(restConfig.CertData).To(gomega.Equal([]byte("cert")))
+	g.Expect(restConfig.KeyData).To(gomega.Equal([]byte("key")))
+	g.Expect(restConfig.Dial).NotTo(gomega.BeNil())
+
+	cluster.Spec.KubeconfigContext = "oidc"
+	restConfig, err = cluster.BuildRestConfig(c)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(restConfig.ExecProvider).NotTo(gomega.BeNil())
+	g.Expect(restConfig.ExecProvider.Command).To(gomega.Equal("get-token"))
+	g.Expect(cluster.GetCredentialsSecretRef()).To(gomega.Equal(cluster.Spec.KubeconfigSecretRef))
+}
@@ -26,12 +26,19 @@ import (
 )
 
 const (
-	HookPhaseLabel       = "phase"
-	HookOwnerLabel       = "owner"
-	PreBackupHookPhase   = "PreBackup"
-	PostBackupHookPhase  = "PostBackup"
-	PreRestoreHookPhase  = "PreRestore"
-	PostRestoreHookPhase = "PostRestore"
+	HookPhaseLabel          = "phase"
+	HookOwnerLabel          = "owner"
+	PreBackupHookPhase      = "PreBackup"
+	PostBackupHookPhase     = "PostBackup"
+	PreRestoreHookPhase     = "PreRestore"
+	PostRestoreHookPhase    = "PostRestore"
+	PreQuiesceHookPhase     = "PreQuiesce"
+	PostQuiesceHookPhase    = "PostQuiesce"
+	PreVolumeCopyHookPhase  = "PreVolumeCopy"
+	PostVolumeCopyHookPhase = "PostVolumeCopy"
+	PostVerifyHookPhase     = "PostVerify"
+	// Prefix of the phase of quiesce group drain hooks.
+	QuiesceGroupHookPhase = "QuiesceGroup"
 )
 
 // MigHookSpec defines the desired state of MigHook
@@ -50,12 +57,62 @@ type MigHookSpec struct {
 
 	// Specifies the highest amount of time for which the hook will run.
 	ActiveDeadlineSeconds int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Specifies where the Ansible playbook (or the script run by the custom image) is fetched from.
+	// Used instead of the inline playbook.
+	Source *HookSource `json:"source,omitempty"`
+}
+
+// HookSource the location of the playbook or script run by a hook.
+// Exactly one of Git and OCI must be specified.
+type HookSource struct {
+	// A file in a Git repository.
+	Git *GitHookSource `json:"git,omitempty"`
+
+	// A file in an OCI artifact.
+	OCI *OCIHookSource `json:"oci,omitempty"`
+
+	// A secret with the `username` and `password` used to fetch the source.
+	CredentialsSecretRef *corev1.ObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// GitHookSource a file in a Git repository.
+type GitHookSource struct {
+	// The repository HTTP(S) URL.
+	URL string `json:"url"`
+
+	// The branch, tag or commit. Defaults to the remote HEAD.
+	Ref string `json:"ref,omitempty"`
+
+	// The path of the file in the repository.
+	Path string `json:"path"`
+}
+
+// OCIHookSource a file in an OCI artifact.
+type OCIHookSource struct {
+	// The artifact reference, i.e. quay.io/org/hooks:v1.
+	Reference string `json:"reference"`
+
+	// The file name, either the title of an artifact layer or a path within a tar layer.
+	Path string `json:"path"`
+}
+
+// ResolvedHookSource the revision the hook source is pinned to.
+type ResolvedHookSource struct {
+	// The Git commit or the OCI manifest digest.
+	Revision string `json:"revision"`
+
+	// The MigHook generation resolved.
+	Generation int64 `json:"generation"`
+
+	ResolvedTimestamp *metav1.Time `json:"resolvedTimestamp,omitempty"`
 }
 
 // MigHookStatus defines the observed state of MigHook
 type MigHookStatus struct {
 	Conditions         `json:","`
-	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	ObservedGeneration int64               `json:"observedGeneration,omitempty"`
+	Source             *ResolvedHookSource `json:"source,omitempty"`
 }
 
 // +genclient
@@ -125,3 +182,29 @@ func (r *MigHook) GetPhaseConfigMap(client k8sclient.Client, phase string, owner
 	}
 	return nil, nil
 }
+
+// Get an existing hook source credentials secret.
+func (r *MigHook) GetPhaseSecret(client k8sclient.Client, phase string, owner string) (*corev1.Secret, error) {
+	list := corev1.SecretList{}
+	labels := r.GetCorrelationLabels()
+	labels[HookPhaseLabel] = phase
+	labels[HookOwnerLabel] = owner
+	err := client.List(
+		context.TODO(),
+		k8sclient.MatchingLabels(labels),
+		&list)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) > 0 {
+		return &list.Items[0], nil
+	}
+	return nil, nil
+}
+
+// Get whether the hook source is pinned to a revision for the current generation.
+func (r *MigHook) SourceResolved() bool {
+	return r.Status.Source != nil &&
+		r.Status.Source.Revision != "" &&
+		r.Status.Source.Generation == r.Generation
+}
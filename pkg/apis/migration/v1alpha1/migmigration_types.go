@@ -43,6 +43,10 @@ type MigMigrationSpec struct {
 	// Specifies whether to verify the health of the migrated pods or not.
 	Verify bool `json:"verify,omitempty"`
 
+	// Specifies whether to compare the migrated resources on the source and destination clusters.
+	// The differences are reported in a ConfigMap referenced in the status.
+	CompareResources bool `json:"compareResources,omitempty"`
+
 	// Invokes the cancel migration operation, when set to true the migration controller switches to cancel itinerary. This field can be used on-demand to cancel the running migration.
 	Canceled bool `json:"canceled,omitempty"`
 
@@ -54,12 +58,129 @@ type MigMigrationSpec struct {
 type MigMigrationStatus struct {
 	Conditions         `json:",inline"`
 	UnhealthyResources `json:",inline"`
-	ObservedDigest     string       `json:"observedDigest,omitempty"`
-	StartTimestamp     *metav1.Time `json:"startTimestamp,omitempty"`
-	Phase              string       `json:"phase,omitempty"`
-	Pipeline           []*Step      `json:"pipeline,omitempty"`
-	Itinerary          string       `json:"itinerary,omitempty"`
-	Errors             []string     `json:"errors,omitempty"`
+	ObservedDigest     string              `json:"observedDigest,omitempty"`
+	StartTimestamp     *metav1.Time        `json:"startTimestamp,omitempty"`
+	Phase              string              `json:"phase,omitempty"`
+	Pipeline           []*Step             `json:"pipeline,omitempty"`
+	Itinerary          string              `json:"itinerary,omitempty"`
+	Errors             []string            `json:"errors,omitempty"`
+	Verification       *VerificationStatus `json:"verification,omitempty"`
+	ResourceDiff       *ResourceDiff       `json:"resourceDiff,omitempty"`
+	Hooks              []HookStatus        `json:"hooks,omitempty"`
+}
+
+// Hook states.
+const (
+	HookPending   = "Pending"
+	HookRunning   = "Running"
+	HookRetrying  = "Retrying"
+	HookSucceeded = "Succeeded"
+	HookFailed    = "Failed"
+	HookSkipped   = "Skipped"
+)
+
+// HookStatus defines the outcome of a hook run by the migration.
+// The result is the document written by the hook to the path in the MIGRATION_RESULT environment variable.
+type HookStatus struct {
+	Name                 string                `json:"name"`
+	Phase                string                `json:"phase"`
+	State                string                `json:"state"`
+	Job                  *kapi.ObjectReference `json:"job,omitempty"`
+	Attempts             int                   `json:"attempts,omitempty"`
+	Ignored              bool                  `json:"ignored,omitempty"`
+	Message              string                `json:"message,omitempty"`
+	LogsTail             string                `json:"logsTail,omitempty"`
+	Result               string                `json:"result,omitempty"`
+	StartTimestamp       *metav1.Time          `json:"startTimestamp,omitempty"`
+	CompletionTimestamp  *metav1.Time          `json:"completionTimestamp,omitempty"`
+	LastFailureTimestamp *metav1.Time          `json:"lastFailureTimestamp,omitempty"`
+	Duration             *metav1.Duration      `json:"duration,omitempty"`
+}
+
+// Get whether the hook has completed.
+func (r *HookStatus) Completed() bool {
+	return r.State == HookSucceeded || r.State == HookFailed || r.State == HookSkipped
+}
+
+// Mark the hook completed in the specified state.
+func (r *HookStatus) MarkCompleted(state string) {
+	now := metav1.Now()
+	r.State = state
+	r.CompletionTimestamp = &now
+	if r.StartTimestamp != nil {
+		r.Duration = &metav1.Duration{Duration: now.Sub(r.StartTimestamp.Time)}
+	}
+}
+
+// Find the status of a hook.
+func (r *MigMigrationStatus) FindHook(name, phase string) *HookStatus {
+	for i := range r.Hooks {
+		if r.Hooks[i].Name == name && r.Hooks[i].Phase == phase {
+			return &r.Hooks[i]
+		}
+	}
+	return nil
+}
+
+// Get the status of a hook, added as Pending when not found.
+func (r *MigMigrationStatus) GetHook(name, phase string) *HookStatus {
+	hook := r.FindHook(name, phase)
+	if hook == nil {
+		r.Hooks = append(r.Hooks, HookStatus{
+			Name:  name,
+			Phase: phase,
+			State: HookPending,
+		})
+		hook = &r.Hooks[len(r.Hooks)-1]
+	}
+	return hook
+}
+
+// ResourceDiff summarizes the differences between the source and migrated resources.
+// The differences are listed in the referenced report ConfigMap.
+type ResourceDiff struct {
+	ReportRef *kapi.ObjectReference `json:"reportRef,omitempty"`
+	Compared  int                   `json:"compared"`
+	Missing   int                   `json:"missing"`
+	Extra     int                   `json:"extra"`
+	Changed   int                   `json:"changed"`
+	Truncated bool                  `json:"truncated,omitempty"`
+}
+
+// VerificationStatus defines the results of the verification checks.
+type VerificationStatus struct {
+	StartTimestamp *metav1.Time              `json:"startTimestamp,omitempty"`
+	Results        []VerificationCheckResult `json:"results,omitempty"`
+}
+
+// VerificationCheckResult defines the result of a verification check.
+type VerificationCheckResult struct {
+	Name                 string       `json:"name"`
+	Passed               bool         `json:"passed"`
+	Message              string       `json:"message,omitempty"`
+	Attempts             int          `json:"attempts,omitempty"`
+	LastAttemptTimestamp *metav1.Time `json:"lastAttemptTimestamp,omitempty"`
+}
+
+// Find the result of a verification check, added when not found.
+func (r *VerificationStatus) GetResult(name string) *VerificationCheckResult {
+	for i := range r.Results {
+		if r.Results[i].Name == name {
+			return &r.Results[i]
+		}
+	}
+	r.Results = append(r.Results, VerificationCheckResult{Name: name})
+	return &r.Results[len(r.Results)-1]
+}
+
+// Get whether all checks have passed.
+func (r *VerificationStatus) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
 }
 
 // FindStep find step by name
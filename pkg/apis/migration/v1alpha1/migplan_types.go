@@ -20,10 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	liberr "github.com/konveyor/controller/pkg/error"
 	velero "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
@@ -52,7 +55,9 @@ const (
 type MigPlanHook struct {
 	Reference *kapi.ObjectReference `json:"reference"`
 
-	// Indicates the phase when the hooks will be executed. Acceptable values are: PreBackup, PostBackup, PreRestore, and PostRestore.
+	// Indicates the phase when the hooks will be executed. Acceptable values are: PreBackup, PostBackup, PreRestore, PostRestore,
+	// PreQuiesce, PostQuiesce, PreVolumeCopy, PostVolumeCopy and PostVerify.
+	// PostVerify hooks run after the verification, or after the restore when verification is not enabled.
 	Phase string `json:"phase"`
 
 	// Holds the name of the namespace where hooks should be implemented.
@@ -60,6 +65,467 @@ type MigPlanHook struct {
 
 	// Holds the name of the service account to be used for running hooks.
 	ServiceAccount string `json:"serviceAccount"`
+
+	// The hook name, unique within the phase. Defaults to the name of the referenced MigHook.
+	Name string `json:"name,omitempty"`
+
+	// Specifies what is done when the hook fails. Acceptable values are: Fail (default), Ignore and Rollback.
+	// Fail fails the migration, Ignore continues the migration with a warning and Rollback fails the
+	// migration and creates a rollback migration for the plan.
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// The number of times the hook job is created again after it failed.
+	Retries int `json:"retries,omitempty"`
+
+	// The delay before the hook job is created again, doubled after each failed attempt. Defaults to 30s.
+	RetryBackoff *metav1.Duration `json:"retryBackoff,omitempty"`
+
+	// Overrides the activeDeadlineSeconds of the referenced MigHook.
+	// At least 1s, rounded up to whole seconds.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Hooks in the same phase are run by ascending order, hooks with the same order are run concurrently.
+	Order int `json:"order,omitempty"`
+
+	// The names of the hooks in the same phase which must succeed before the hook is run.
+	// The hook is skipped when a dependency did not succeed.
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// HookFailurePolicy specifies what is done when a hook fails.
+type HookFailurePolicy string
+
+// Hook failure policies.
+const (
+	HookFailurePolicyFail     HookFailurePolicy = "Fail"
+	HookFailurePolicyIgnore   HookFailurePolicy = "Ignore"
+	HookFailurePolicyRollback HookFailurePolicy = "Rollback"
+)
+
+// Default delay before a failed hook job is created again.
+const DefaultHookRetryBackoff = time.Second * 30
+
+// Get the hook name.
+func (r *MigPlanHook) GetName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	if r.Reference != nil {
+		return r.Reference.Name
+	}
+	return ""
+}
+
+// Get the failure policy, defaulted to Fail.
+func (r *MigPlanHook) GetFailurePolicy() HookFailurePolicy {
+	if r.FailurePolicy == "" {
+		return HookFailurePolicyFail
+	}
+	return r.FailurePolicy
+}
+
+// Get the delay before the hook job is created again after the attempts failed.
+func (r *MigPlanHook) GetRetryBackoff(attempts int) time.Duration {
+	backoff := DefaultHookRetryBackoff
+	if r.RetryBackoff != nil {
+		backoff = r.RetryBackoff.Duration
+	}
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
+
+// QuiesceGroup a group of workloads quiesced together.
+// Groups are quiesced in order, waiting for the pods of a group to be terminated
+// before the next group is quiesced. Workloads not selected by any group are
+// quiesced after the groups. Groups are unquiesced in the reverse order, waiting
+// for the workloads of a group to be ready on the destination cluster before the
+// next group is unquiesced.
+type QuiesceGroup struct {
+	// The group name, used to report the group progress.
+	Name string `json:"name"`
+
+	// Selects the workloads of the group by labels.
+	// The selector is also matched against pods when waiting for the group to be terminated.
+	Selector *metav1.LabelSelector `json:"selector"`
+
+	// Holds a reference to a MigHook run before the group is quiesced, used to gracefully drain the workloads.
+	DrainHook *QuiesceGroupHook `json:"drainHook,omitempty"`
+}
+
+// QuiesceGroupHook hold a reference to a MigHook run for a quiesce group.
+type QuiesceGroupHook struct {
+	Reference *kapi.ObjectReference `json:"reference"`
+
+	// Holds the name of the namespace where hooks should be implemented.
+	ExecutionNamespace string `json:"executionNamespace"`
+
+	// Holds the name of the service account to be used for running hooks.
+	ServiceAccount string `json:"serviceAccount"`
+}
+
+// Get the drain hook as a plan hook.
+// The hook phase is unique to the group.
+func (r *QuiesceGroup) GetDrainHook() *MigPlanHook {
+	if r.DrainHook == nil {
+		return nil
+	}
+	return &MigPlanHook{
+		Reference:          r.DrainHook.Reference,
+		Phase:              QuiesceGroupHookPhase + "-" + r.Name,
+		ExecutionNamespace: r.DrainHook.ExecutionNamespace,
+		ServiceAccount:     r.DrainHook.ServiceAccount,
+	}
+}
+
+// Verification defines the checks run on the destination cluster after the final restore.
+// Failed checks are retried until the timeout and fail the migration.
+type Verification struct {
+	// The checks to run.
+	Checks []VerificationCheck `json:"checks,omitempty"`
+
+	// How long failed checks are retried, defaults to 10 minutes.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// VerificationCheck a check run on the destination cluster.
+// Exactly one of the check kinds must be set. The services, routes and pods
+// checked must be in the destination namespaces of the plan.
+type VerificationCheck struct {
+	// The check name, used to report the check result.
+	Name string `json:"name"`
+
+	// Sends an HTTP GET request to a service or a route.
+	HTTPGet *HTTPGetCheck `json:"httpGet,omitempty"`
+
+	// Runs a command in a pod.
+	Exec *ExecCheck `json:"exec,omitempty"`
+
+	// Compares the number of objects of a kind in the source and destination namespaces.
+	ObjectCount *ObjectCountCheck `json:"objectCount,omitempty"`
+}
+
+// Get the namespaces of the services, routes and pods checked.
+func (r *VerificationCheck) Namespaces() []string {
+	namespaces := []string{}
+	if r.HTTPGet != nil {
+		namespaces = append(namespaces, r.HTTPGet.Namespace)
+	}
+	if r.Exec != nil {
+		namespaces = append(namespaces, r.Exec.Namespace)
+	}
+	return namespaces
+}
+
+// HTTPGetCheck sends an HTTP GET request and checks the response status.
+// Requests to a service are sent through the API server proxy, requests to
+// a route are sent to the route host. Exactly one of service or route must be set.
+type HTTPGetCheck struct {
+	// The destination namespace of the service or route.
+	Namespace string `json:"namespace"`
+
+	// The service name.
+	Service string `json:"service,omitempty"`
+
+	// The service port name or number.
+	Port intstr.IntOrString `json:"port,omitempty"`
+
+	// The route name.
+	Route string `json:"route,omitempty"`
+
+	// The request path.
+	Path string `json:"path,omitempty"`
+
+	// The scheme used to connect to the service, HTTP or HTTPS. Defaults to HTTP.
+	// The scheme of a route is HTTPS when the route is secured.
+	Scheme kapi.URIScheme `json:"scheme,omitempty"`
+
+	// The expected response status, defaults to 200.
+	ExpectedStatus int `json:"expectedStatus,omitempty"`
+
+	// If set True, the TLS certificate of the route is not verified.
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// ExecCheck runs a command in a running pod matched by the selector.
+// The check passes when the command exits successfully.
+type ExecCheck struct {
+	// The destination namespace of the pod.
+	Namespace string `json:"namespace"`
+
+	// Selects the pod by labels.
+	Selector *metav1.LabelSelector `json:"selector"`
+
+	// The container name, defaults to the first container.
+	Container string `json:"container,omitempty"`
+
+	// The command (and args) to run.
+	Command []string `json:"command"`
+}
+
+// ObjectCountCheck compares the number of objects of a kind in the source
+// namespaces and the destination namespaces.
+type ObjectCountCheck struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// ResourceFilters select the objects migrated in the plan namespaces.
+// The filters are applied to the initial velero backup. An object is migrated
+// when its kind matches an include rule (or no include rules are defined),
+// matches no exclude rule and the object matches the label selector.
+type ResourceFilters struct {
+	// The kinds included. All kinds are included when empty.
+	Include []ResourceFilterRule `json:"include,omitempty"`
+
+	// The kinds excluded, takes precedence over the include rules.
+	Exclude []ResourceFilterRule `json:"exclude,omitempty"`
+
+	// Selects the objects migrated by labels.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// ResourceFilterRule matches objects by group and kind.
+type ResourceFilterRule struct {
+	// The API group, matches all groups when empty.
+	Group string `json:"group,omitempty"`
+
+	// The kind.
+	Kind string `json:"kind"`
+}
+
+// Get whether the rule matches a kind.
+func (r *ResourceFilterRule) MatchesKind(group, kind string) bool {
+	if r.Group != "" && !strings.EqualFold(r.Group, group) {
+		return false
+	}
+	return strings.EqualFold(r.Kind, kind)
+}
+
+// Get whether the resource filters have been resolved
+// in the included and excluded resources.
+func (r *MigPlan) ResourceFiltersResolved() bool {
+	return r.Status.ResourceFiltersDigest == digest(r.Spec.ResourceFilters)
+}
+
+// Mark the resource filters resolved.
+func (r *MigPlan) MarkResourceFiltersResolved() {
+	r.Status.ResourceFiltersDigest = digest(r.Spec.ResourceFilters)
+}
+
+// ClusterResources selects the cluster-scoped dependencies of the plan namespaces
+// migrated with the plan. The dependencies are discovered on the source cluster.
+// Resources found on the destination cluster with a different content are
+// reported as conflicts and are not migrated.
+type ClusterResources struct {
+	// Migrate the CustomResourceDefinitions of the custom resources found in the namespaces.
+	CRDs bool `json:"crds,omitempty"`
+
+	// Migrate the ClusterRoleBindings bound to the service accounts of the namespaces
+	// and the ClusterRoles referenced by the bindings of the namespaces.
+	RBAC bool `json:"rbac,omitempty"`
+
+	// Migrate the SecurityContextConstraints used by the pods of the namespaces.
+	// The users and groups of the namespaces are added to SCCs found on the destination cluster.
+	SCCs bool `json:"sccs,omitempty"`
+
+	// Migrate the StorageClasses of the PVCs found in the namespaces.
+	StorageClasses bool `json:"storageClasses,omitempty"`
+}
+
+// Get whether any cluster-scoped resources are migrated.
+func (r *ClusterResources) Enabled() bool {
+	return r != nil && (r.CRDs || r.RBAC || r.SCCs || r.StorageClasses)
+}
+
+// ClusterResource a cluster-scoped resource migrated with the plan.
+type ClusterResource struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+
+	// The namespaced object depending on the resource.
+	Reason string `json:"reason,omitempty"`
+
+	// The resource exists on the destination cluster.
+	Exists bool `json:"exists,omitempty"`
+
+	// The resource exists on the destination cluster with a different content.
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// Get a description of the resource.
+func (r *ClusterResource) String() string {
+	if r.Group == "" {
+		return r.Kind + "/" + r.Name
+	}
+	return r.Kind + "." + r.Group + "/" + r.Name
+}
+
+// PVCTransformRule transforms the destination PVCs matching the source
+// storage class and namespaces.
+type PVCTransformRule struct {
+	// The source storage class, matches all classes when empty.
+	SourceStorageClass string `json:"sourceStorageClass,omitempty"`
+
+	// The source namespaces, matches all namespaces when empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// The destination storage class, overrides the storage class selected for the PV.
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// The destination access modes, overrides the access mode selected for the PV.
+	AccessModes []kapi.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// The destination volume mode, only Filesystem is supported.
+	VolumeMode *kapi.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// Multiplies the requested size (e.g. "1.5"), rounded up to the MiB.
+	SizeMultiplier string `json:"sizeMultiplier,omitempty"`
+
+	// The minimum requested size, applied after the multiplier.
+	MinSize *resource.Quantity `json:"minSize,omitempty"`
+
+	// The labels set on the destination PVCs.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// The labels of the source PVCs not set on the destination PVCs.
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+
+	// The annotations set on the destination PVCs.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Get whether the rule matches a source PVC.
+func (r *PVCTransformRule) Matches(namespace, storageClass string) bool {
+	if r.SourceStorageClass != "" && r.SourceStorageClass != storageClass {
+		return false
+	}
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range r.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the problems of the rule.
+func (r *PVCTransformRule) Invalid() []string {
+	invalid := []string{}
+	if r.SizeMultiplier != "" {
+		multiplier, err := strconv.ParseFloat(r.SizeMultiplier, 64)
+		if err != nil || multiplier <= 0 {
+			invalid = append(invalid, fmt.Sprintf("sizeMultiplier %s not valid", r.SizeMultiplier))
+		}
+	}
+	if r.MinSize != nil && r.MinSize.Sign() <= 0 {
+		invalid = append(invalid, fmt.Sprintf("minSize %s not valid", r.MinSize.String()))
+	}
+	if r.VolumeMode != nil {
+		switch *r.VolumeMode {
+		case kapi.PersistentVolumeFilesystem:
+		case kapi.PersistentVolumeBlock:
+			// The transfer pods mount the volumes as filesystems.
+			invalid = append(invalid, fmt.Sprintf("volumeMode %s not supported", *r.VolumeMode))
+		default:
+			invalid = append(invalid, fmt.Sprintf("volumeMode %s not valid", *r.VolumeMode))
+		}
+	}
+	return invalid
+}
+
+// Transform a destination PVC.
+func (r *PVCTransformRule) Apply(pvc *kapi.PersistentVolumeClaim) error {
+	if r.StorageClass != "" {
+		storageClass := r.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	if len(r.AccessModes) > 0 {
+		pvc.Spec.AccessModes = append([]kapi.PersistentVolumeAccessMode{}, r.AccessModes...)
+	}
+	if r.VolumeMode != nil {
+		volumeMode := *r.VolumeMode
+		pvc.Spec.VolumeMode = &volumeMode
+	}
+	size := pvc.Spec.Resources.Requests[kapi.ResourceStorage]
+	if r.SizeMultiplier != "" {
+		multiplier, err := strconv.ParseFloat(r.SizeMultiplier, 64)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		mib := float64(1024 * 1024)
+		bytes := int64(math.Ceil(float64(size.Value())*multiplier/mib) * mib)
+		size = *resource.NewQuantity(bytes, resource.BinarySI)
+	}
+	if r.MinSize != nil && r.MinSize.Cmp(size) > 0 {
+		size = r.MinSize.DeepCopy()
+	}
+	if !size.IsZero() {
+		requests := kapi.ResourceList{}
+		for name, quantity := range pvc.Spec.Resources.Requests {
+			requests[name] = quantity
+		}
+		requests[kapi.ResourceStorage] = size
+		pvc.Spec.Resources.Requests = requests
+	}
+	if len(r.Labels) > 0 || len(r.RemoveLabels) > 0 {
+		labels := map[string]string{}
+		for k, v := range pvc.Labels {
+			labels[k] = v
+		}
+		for _, k := range r.RemoveLabels {
+			delete(labels, k)
+		}
+		for k, v := range r.Labels {
+			labels[k] = v
+		}
+		pvc.Labels = labels
+	}
+	if len(r.Annotations) > 0 {
+		annotations := map[string]string{}
+		for k, v := range pvc.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range r.Annotations {
+			annotations[k] = v
+		}
+		pvc.Annotations = annotations
+	}
+	return nil
+}
+
+// Find the first PVC transform rule matching a source PVC.
+// Returns the index of the rule, -1 when not found.
+func (r *MigPlan) FindPVCTransform(namespace, storageClass string) (int, *PVCTransformRule) {
+	for i := range r.Spec.PVCTransforms {
+		rule := &r.Spec.PVCTransforms[i]
+		if rule.Matches(namespace, storageClass) {
+			return i, rule
+		}
+	}
+	return -1, nil
+}
+
+// PVCPreview the destination PVC created by direct volume migrations.
+type PVCPreview struct {
+	// The source PVC.
+	*kapi.ObjectReference `json:",inline"`
+
+	// The index of the transform rule applied.
+	Rule *int `json:"rule,omitempty"`
+
+	StorageClass string                            `json:"storageClass,omitempty"`
+	AccessModes  []kapi.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	VolumeMode   *kapi.PersistentVolumeMode        `json:"volumeMode,omitempty"`
+	Capacity     resource.Quantity                 `json:"capacity,omitempty"`
+	Labels       map[string]string                 `json:"labels,omitempty"`
+	Annotations  map[string]string                 `json:"annotations,omitempty"`
 }
 
 // MigPlanSpec defines the desired state of MigPlan
@@ -91,6 +557,29 @@ type MigPlanSpec struct {
 
 	// If set True, disables direct volume migrations.
 	IndirectVolumeMigration bool `json:"indirectVolumeMigration,omitempty"`
+
+	// Holds the registry images are migrated to when the destination cluster is not OpenShift.
+	// When set, image references in migrated workloads are rewritten to the registry.
+	DestImageRegistry *DestImageRegistry `json:"destImageRegistry,omitempty"`
+
+	// Holds the groups of workloads quiesced in order before the workloads not selected by any group.
+	QuiesceGroups []QuiesceGroup `json:"quiesceGroups,omitempty"`
+
+	// Holds the checks run on the destination cluster after the final restore.
+	Verification *Verification `json:"verification,omitempty"`
+
+	// Holds the rules selecting the objects migrated in the namespaces.
+	ResourceFilters *ResourceFilters `json:"resourceFilters,omitempty"`
+
+	// Holds the cluster-scoped dependencies of the namespaces migrated with the plan.
+	ClusterResources *ClusterResources `json:"clusterResources,omitempty"`
+
+	// Tunes the direct volume migration transfer, defaults to the controller settings.
+	Transfer *TransferSpec `json:"transfer,omitempty"`
+
+	// Holds the rules transforming the destination PVCs created by direct volume migrations.
+	// The first rule matching a PVC applies.
+	PVCTransforms []PVCTransformRule `json:"pvcTransforms,omitempty"`
 }
 
 // MigPlanStatus defines the observed state of MigPlan
@@ -98,10 +587,15 @@ type MigPlanStatus struct {
 	UnhealthyResources `json:",inline"`
 	Conditions         `json:",inline"`
 	Incompatible       `json:",inline"`
-	ObservedDigest     string         `json:"observedDigest,omitempty"`
-	ExcludedResources  []string       `json:"excludedResources,omitempty"`
-	SrcStorageClasses  []StorageClass `json:"srcStorageClasses,omitempty"`
-	DestStorageClasses []StorageClass `json:"destStorageClasses,omitempty"`
+	ObservedDigest     string   `json:"observedDigest,omitempty"`
+	ExcludedResources  []string `json:"excludedResources,omitempty"`
+	IncludedResources  []string `json:"includedResources,omitempty"`
+	// Digest of the resource filters resolved in the included and excluded resources.
+	ResourceFiltersDigest string            `json:"resourceFiltersDigest,omitempty"`
+	SrcStorageClasses     []StorageClass    `json:"srcStorageClasses,omitempty"`
+	DestStorageClasses    []StorageClass    `json:"destStorageClasses,omitempty"`
+	ClusterResources      []ClusterResource `json:"clusterResources,omitempty"`
+	PVCPreview            []PVCPreview      `json:"pvcPreview,omitempty"`
 }
 
 // +genclient
@@ -457,7 +951,7 @@ func (r *MigPlan) UpdateRegistryDeployment(storage *MigStorage, deployment *apps
 }
 
 // Get an existing registry Deployment on the specified cluster.
-//TODO: We need to convert this from a list call to a get call. The name of the deployment is equal to the name of the secret
+// TODO: We need to convert this from a list call to a get call. The name of the deployment is equal to the name of the secret
 func (r *MigPlan) GetRegistryDeployment(client k8sclient.Client) (*appsv1.Deployment, error) {
 	list := appsv1.DeploymentList{}
 	labels := r.GetCorrelationLabels()
@@ -536,7 +1030,7 @@ func (r *MigPlan) UpdateRegistryService(service *kapi.Service, name string) {
 }
 
 // Get an existing registry Service on the specifiedcluster.
-//TODO: We need to convert this from a list call to a get call. The name of the deployment is equal to the name of the secret
+// TODO: We need to convert this from a list call to a get call. The name of the deployment is equal to the name of the secret
 func (r *MigPlan) GetRegistryService(client k8sclient.Client) (*kapi.Service, error) {
 	list := kapi.ServiceList{}
 	labels := r.GetCorrelationLabels()
@@ -640,6 +1134,17 @@ func (r *MigPlan) GetDestinationNamespaces() []string {
 	return includedNamespaces
 }
 
+// GetNamespaceMapping get the destination namespaces keyed by source namespace.
+func (r *MigPlan) GetNamespaceMapping() map[string]string {
+	mapping := map[string]string{}
+	destination := r.GetDestinationNamespaces()
+	for i, namespace := range r.GetSourceNamespaces() {
+		mapping[namespace] = destination[i]
+	}
+
+	return mapping
+}
+
 // Get whether the plan conflicts with another.
 // Plans conflict when:
 //   - Have any of the clusters in common.
@@ -734,6 +1239,10 @@ type PVC struct {
 	Name         string                            `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
 	AccessModes  []kapi.PersistentVolumeAccessMode `json:"accessModes,omitempty" protobuf:"bytes,1,rep,name=accessModes,casttype=PersistentVolumeAccessMode"`
 	HasReference bool                              `json:"hasReference,omitempty"`
+	// The storage class of the PVC.
+	StorageClass string `json:"storageClass,omitempty"`
+	// The requested size of the PVC.
+	Capacity resource.Quantity `json:"capacity,omitempty"`
 }
 
 // Supported
@@ -782,7 +1291,6 @@ func (r *PV) Update(pv PV) {
 // plan.Spec.AddPv(pvB)
 // plan.Spec.AddPv(pvC)
 // plan.Spec.EndPvStaging()
-//
 type PersistentVolumes struct {
 	List    []PV           `json:"persistentVolumes,omitempty"`
 	index   map[string]int `json:"-"`
@@ -882,9 +1390,10 @@ func (r *PersistentVolumes) ResetPvs() {
 
 // Convert name to a DNS_LABEL-compliant string
 // DNS_LABEL:  This is a string, no more than 63 characters long, that conforms
-//     to the definition of a "label" in RFCs 1035 and 1123. This is captured
-//     by the following regex:
-//         [a-z0-9]([-a-z0-9]*[a-z0-9])?
+//
+//	to the definition of a "label" in RFCs 1035 and 1123. This is captured
+//	by the following regex:
+//	    [a-z0-9]([-a-z0-9]*[a-z0-9])?
 func toDnsLabel(name string) (string, error) {
 	// keep lowercase alphanumeric and hyphen
 	reg, err := regexp.Compile("[^-a-z0-9]+")
@@ -310,3 +310,22 @@ func TestMigPlan_EqualsRegistrySecret(t *testing.T) {
 		})
 	}
 }
+
+func TestMigPlan_ResourceFiltersResolved(t *testing.T) {
+	plan := &MigPlan{
+		Spec: MigPlanSpec{
+			ResourceFilters: &ResourceFilters{Include: []ResourceFilterRule{{Kind: "Deployment"}}},
+		},
+	}
+	if plan.ResourceFiltersResolved() {
+		t.Fatalf("ResourceFiltersResolved() = true, want false")
+	}
+	plan.MarkResourceFiltersResolved()
+	if !plan.ResourceFiltersResolved() {
+		t.Fatalf("ResourceFiltersResolved() = false, want true")
+	}
+	plan.Spec.ResourceFilters.Exclude = []ResourceFilterRule{{Kind: "Secret"}}
+	if plan.ResourceFiltersResolved() {
+		t.Errorf("ResourceFiltersResolved() = true after the filters changed, want false")
+	}
+}
@@ -51,6 +51,136 @@ func (in *BackupStorageConfig) DeepCopy() *BackupStorageConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandwidthStatus) DeepCopyInto(out *BandwidthStatus) {
+	*out = *in
+	if in.Until != nil {
+		in, out := &in.Until, &out.Until
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandwidthStatus.
+func (in *BandwidthStatus) DeepCopy() *BandwidthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BandwidthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandwidthWindow) DeepCopyInto(out *BandwidthWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandwidthWindow.
+func (in *BandwidthWindow) DeepCopy() *BandwidthWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BandwidthWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+	*out = *in
+	if in.LastCheckTimestamp != nil {
+		in, out := &in.LastCheckTimestamp, &out.LastCheckTimestamp
+		*out = (*in).DeepCopy()
+	}
+	out.Velero = in.Velero
+	out.Restic = in.Restic
+	if in.AllocatableCPU != nil {
+		in, out := &in.AllocatableCPU, &out.AllocatableCPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.AllocatableMemory != nil {
+		in, out := &in.AllocatableMemory, &out.AllocatableMemory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryReachable != nil {
+		in, out := &in.RegistryReachable, &out.RegistryReachable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.APILatency != nil {
+		in, out := &in.APILatency, &out.APILatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealth.
+func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResource) DeepCopyInto(out *ClusterResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResource.
+func (in *ClusterResource) DeepCopy() *ClusterResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResources) DeepCopyInto(out *ClusterResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResources.
+func (in *ClusterResources) DeepCopy() *ClusterResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentHealth) DeepCopyInto(out *ComponentHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHealth.
+func (in *ComponentHealth) DeepCopy() *ComponentHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -94,6 +224,26 @@ func (in *Conditions) DeepCopy() *Conditions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DestImageRegistry) DeepCopyInto(out *DestImageRegistry) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestImageRegistry.
+func (in *DestImageRegistry) DeepCopy() *DestImageRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(DestImageRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DirectImageMigration) DeepCopyInto(out *DirectImageMigration) {
 	*out = *in
@@ -171,6 +321,16 @@ func (in *DirectImageMigrationSpec) DeepCopyInto(out *DirectImageMigrationSpec)
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExternalImages != nil {
+		in, out := &in.ExternalImages, &out.ExternalImages
+		*out = new(ExternalImageMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DestImageRegistry != nil {
+		in, out := &in.DestImageRegistry, &out.DestImageRegistry
+		*out = new(DestImageRegistry)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectImageMigrationSpec.
@@ -240,6 +400,11 @@ func (in *DirectImageMigrationStatus) DeepCopyInto(out *DirectImageMigrationStat
 			}
 		}
 	}
+	if in.ImageMappings != nil {
+		in, out := &in.ImageMappings, &out.ImageMappings
+		*out = make([]ImageMapping, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectImageMigrationStatus.
@@ -329,6 +494,16 @@ func (in *DirectImageStreamMigrationSpec) DeepCopyInto(out *DirectImageStreamMig
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.ExternalImages != nil {
+		in, out := &in.ExternalImages, &out.ExternalImages
+		*out = new(ExternalImageMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DestImageRegistry != nil {
+		in, out := &in.DestImageRegistry, &out.DestImageRegistry
+		*out = new(DestImageRegistry)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectImageStreamMigrationSpec.
@@ -354,6 +529,18 @@ func (in *DirectImageStreamMigrationStatus) DeepCopyInto(out *DirectImageStreamM
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImageMappings != nil {
+		in, out := &in.ImageMappings, &out.ImageMappings
+		*out = make([]ImageMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagProgress != nil {
+		in, out := &in.TagProgress, &out.TagProgress
+		*out = make([]ImageStreamTagProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectImageStreamMigrationStatus.
@@ -523,6 +710,16 @@ func (in *DirectVolumeMigrationProgressStatus) DeepCopyInto(out *DirectVolumeMig
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.TotalBytes != nil {
+		in, out := &in.TotalBytes, &out.TotalBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TransferredBytes != nil {
+		in, out := &in.TransferredBytes, &out.TransferredBytes
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectVolumeMigrationProgressStatus.
@@ -555,6 +752,11 @@ func (in *DirectVolumeMigrationSpec) DeepCopyInto(out *DirectVolumeMigrationSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Transfer != nil {
+		in, out := &in.Transfer, &out.Transfer
+		*out = new(TransferSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectVolumeMigrationSpec.
@@ -613,6 +815,37 @@ func (in *DirectVolumeMigrationStatus) DeepCopyInto(out *DirectVolumeMigrationSt
 			}
 		}
 	}
+	if in.PendingPods != nil {
+		in, out := &in.PendingPods, &out.PendingPods
+		*out = make([]*PodProgress, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(PodProgress)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.Bandwidth != nil {
+		in, out := &in.Bandwidth, &out.Bandwidth
+		*out = new(BandwidthStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeAttempts != nil {
+		in, out := &in.VolumeAttempts, &out.VolumeAttempts
+		*out = make([]*VolumeAttempts, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(VolumeAttempts)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectVolumeMigrationStatus.
@@ -626,176 +859,374 @@ func (in *DirectVolumeMigrationStatus) DeepCopy() *DirectVolumeMigrationStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageStreamListItem) DeepCopyInto(out *ImageStreamListItem) {
+func (in *ExecCheck) DeepCopyInto(out *ExecCheck) {
 	*out = *in
-	if in.ObjectReference != nil {
-		in, out := &in.ObjectReference, &out.ObjectReference
-		*out = new(v1.ObjectReference)
-		**out = **in
-	}
-	if in.DirectMigration != nil {
-		in, out := &in.DirectMigration, &out.DirectMigration
-		*out = new(v1.ObjectReference)
-		**out = **in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Errors != nil {
-		in, out := &in.Errors, &out.Errors
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStreamListItem.
-func (in *ImageStreamListItem) DeepCopy() *ImageStreamListItem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecCheck.
+func (in *ExecCheck) DeepCopy() *ExecCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageStreamListItem)
+	out := new(ExecCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Incompatible) DeepCopyInto(out *Incompatible) {
+func (in *ExternalImageMigration) DeepCopyInto(out *ExternalImageMigration) {
 	*out = *in
-	if in.Namespaces != nil {
-		in, out := &in.Namespaces, &out.Namespaces
-		*out = make([]IncompatibleNamespace, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedRegistries != nil {
+		in, out := &in.DeniedRegistries, &out.DeniedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Incompatible.
-func (in *Incompatible) DeepCopy() *Incompatible {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalImageMigration.
+func (in *ExternalImageMigration) DeepCopy() *ExternalImageMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(Incompatible)
+	out := new(ExternalImageMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IncompatibleGVK) DeepCopyInto(out *IncompatibleGVK) {
+func (in *GitHookSource) DeepCopyInto(out *GitHookSource) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncompatibleGVK.
-func (in *IncompatibleGVK) DeepCopy() *IncompatibleGVK {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHookSource.
+func (in *GitHookSource) DeepCopy() *GitHookSource {
 	if in == nil {
 		return nil
 	}
-	out := new(IncompatibleGVK)
+	out := new(GitHookSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IncompatibleNamespace) DeepCopyInto(out *IncompatibleNamespace) {
+func (in *HTTPGetCheck) DeepCopyInto(out *HTTPGetCheck) {
 	*out = *in
-	if in.GVKs != nil {
-		in, out := &in.GVKs, &out.GVKs
-		*out = make([]IncompatibleGVK, len(*in))
-		copy(*out, *in)
-	}
+	out.Port = in.Port
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncompatibleNamespace.
-func (in *IncompatibleNamespace) DeepCopy() *IncompatibleNamespace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGetCheck.
+func (in *HTTPGetCheck) DeepCopy() *HTTPGetCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(IncompatibleNamespace)
+	out := new(HTTPGetCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MigAnalytic) DeepCopyInto(out *MigAnalytic) {
+func (in *HookSource) DeepCopyInto(out *HookSource) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitHookSource)
+		**out = **in
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIHookSource)
+		**out = **in
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalytic.
-func (in *MigAnalytic) DeepCopy() *MigAnalytic {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookSource.
+func (in *HookSource) DeepCopy() *HookSource {
 	if in == nil {
 		return nil
 	}
-	out := new(MigAnalytic)
+	out := new(HookSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MigAnalytic) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MigAnalyticList) DeepCopyInto(out *MigAnalyticList) {
+func (in *HookStatus) DeepCopyInto(out *HookStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]MigAnalytic, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTimestamp != nil {
+		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTimestamp != nil {
+		in, out := &in.LastFailureTimestamp, &out.LastFailureTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalyticList.
-func (in *MigAnalyticList) DeepCopy() *MigAnalyticList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookStatus.
+func (in *HookStatus) DeepCopy() *HookStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MigAnalyticList)
+	out := new(HookStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MigAnalyticList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MigAnalyticNSImage) DeepCopyInto(out *MigAnalyticNSImage) {
+func (in *ImageMapping) DeepCopyInto(out *ImageMapping) {
 	*out = *in
-	out.Size = in.Size.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalyticNSImage.
-func (in *MigAnalyticNSImage) DeepCopy() *MigAnalyticNSImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMapping.
+func (in *ImageMapping) DeepCopy() *ImageMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(MigAnalyticNSImage)
+	out := new(ImageMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MigAnalyticNSResource) DeepCopyInto(out *MigAnalyticNSResource) {
+func (in *ImageStreamListItem) DeepCopyInto(out *ImageStreamListItem) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalyticNSResource.
-func (in *MigAnalyticNSResource) DeepCopy() *MigAnalyticNSResource {
-	if in == nil {
-		return nil
+	if in.ObjectReference != nil {
+		in, out := &in.ObjectReference, &out.ObjectReference
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.DirectMigration != nil {
+		in, out := &in.DirectMigration, &out.DirectMigration
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagProgress != nil {
+		in, out := &in.TagProgress, &out.TagProgress
+		*out = make([]ImageStreamTagProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStreamListItem.
+func (in *ImageStreamListItem) DeepCopy() *ImageStreamListItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStreamListItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStreamTagProgress) DeepCopyInto(out *ImageStreamTagProgress) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTimestamp != nil {
+		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStreamTagProgress.
+func (in *ImageStreamTagProgress) DeepCopy() *ImageStreamTagProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStreamTagProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Incompatible) DeepCopyInto(out *Incompatible) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]IncompatibleNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Incompatible.
+func (in *Incompatible) DeepCopy() *Incompatible {
+	if in == nil {
+		return nil
+	}
+	out := new(Incompatible)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncompatibleGVK) DeepCopyInto(out *IncompatibleGVK) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncompatibleGVK.
+func (in *IncompatibleGVK) DeepCopy() *IncompatibleGVK {
+	if in == nil {
+		return nil
+	}
+	out := new(IncompatibleGVK)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncompatibleNamespace) DeepCopyInto(out *IncompatibleNamespace) {
+	*out = *in
+	if in.GVKs != nil {
+		in, out := &in.GVKs, &out.GVKs
+		*out = make([]IncompatibleGVK, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncompatibleNamespace.
+func (in *IncompatibleNamespace) DeepCopy() *IncompatibleNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(IncompatibleNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigAnalytic) DeepCopyInto(out *MigAnalytic) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalytic.
+func (in *MigAnalytic) DeepCopy() *MigAnalytic {
+	if in == nil {
+		return nil
+	}
+	out := new(MigAnalytic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigAnalytic) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigAnalyticList) DeepCopyInto(out *MigAnalyticList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MigAnalytic, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalyticList.
+func (in *MigAnalyticList) DeepCopy() *MigAnalyticList {
+	if in == nil {
+		return nil
+	}
+	out := new(MigAnalyticList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigAnalyticList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigAnalyticNSImage) DeepCopyInto(out *MigAnalyticNSImage) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalyticNSImage.
+func (in *MigAnalyticNSImage) DeepCopy() *MigAnalyticNSImage {
+	if in == nil {
+		return nil
+	}
+	out := new(MigAnalyticNSImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigAnalyticNSResource) DeepCopyInto(out *MigAnalyticNSResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigAnalyticNSResource.
+func (in *MigAnalyticNSResource) DeepCopy() *MigAnalyticNSResource {
+	if in == nil {
+		return nil
 	}
 	out := new(MigAnalyticNSResource)
 	in.DeepCopyInto(out)
@@ -1004,6 +1435,11 @@ func (in *MigClusterSpec) DeepCopyInto(out *MigClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigClusterSpec.
@@ -1020,6 +1456,11 @@ func (in *MigClusterSpec) DeepCopy() *MigClusterSpec {
 func (in *MigClusterStatus) DeepCopyInto(out *MigClusterStatus) {
 	*out = *in
 	in.Conditions.DeepCopyInto(&out.Conditions)
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(ClusterHealth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigClusterStatus.
@@ -1037,7 +1478,7 @@ func (in *MigHook) DeepCopyInto(out *MigHook) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -1094,6 +1535,11 @@ func (in *MigHookList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigHookSpec) DeepCopyInto(out *MigHookSpec) {
 	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(HookSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigHookSpec.
@@ -1110,6 +1556,11 @@ func (in *MigHookSpec) DeepCopy() *MigHookSpec {
 func (in *MigHookStatus) DeepCopyInto(out *MigHookStatus) {
 	*out = *in
 	in.Conditions.DeepCopyInto(&out.Conditions)
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(ResolvedHookSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigHookStatus.
@@ -1226,6 +1677,23 @@ func (in *MigMigrationStatus) DeepCopyInto(out *MigMigrationStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(VerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceDiff != nil {
+		in, out := &in.ResourceDiff, &out.ResourceDiff
+		*out = new(ResourceDiff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]HookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigMigrationStatus.
@@ -1273,6 +1741,21 @@ func (in *MigPlanHook) DeepCopyInto(out *MigPlanHook) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.RetryBackoff != nil {
+		in, out := &in.RetryBackoff, &out.RetryBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigPlanHook.
@@ -1348,6 +1831,45 @@ func (in *MigPlanSpec) DeepCopyInto(out *MigPlanSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DestImageRegistry != nil {
+		in, out := &in.DestImageRegistry, &out.DestImageRegistry
+		*out = new(DestImageRegistry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QuiesceGroups != nil {
+		in, out := &in.QuiesceGroups, &out.QuiesceGroups
+		*out = make([]QuiesceGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(Verification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceFilters != nil {
+		in, out := &in.ResourceFilters, &out.ResourceFilters
+		*out = new(ResourceFilters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterResources != nil {
+		in, out := &in.ClusterResources, &out.ClusterResources
+		*out = new(ClusterResources)
+		**out = **in
+	}
+	if in.Transfer != nil {
+		in, out := &in.Transfer, &out.Transfer
+		*out = new(TransferSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PVCTransforms != nil {
+		in, out := &in.PVCTransforms, &out.PVCTransforms
+		*out = make([]PVCTransformRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigPlanSpec.
@@ -1371,6 +1893,11 @@ func (in *MigPlanStatus) DeepCopyInto(out *MigPlanStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.IncludedResources != nil {
+		in, out := &in.IncludedResources, &out.IncludedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.SrcStorageClasses != nil {
 		in, out := &in.SrcStorageClasses, &out.SrcStorageClasses
 		*out = make([]StorageClass, len(*in))
@@ -1385,6 +1912,18 @@ func (in *MigPlanStatus) DeepCopyInto(out *MigPlanStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ClusterResources != nil {
+		in, out := &in.ClusterResources, &out.ClusterResources
+		*out = make([]ClusterResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.PVCPreview != nil {
+		in, out := &in.PVCPreview, &out.PVCPreview
+		*out = make([]PVCPreview, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigPlanStatus.
@@ -1490,18 +2029,48 @@ func (in *MigStorageStatus) DeepCopy() *MigStorageStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PV) DeepCopyInto(out *PV) {
+func (in *OCIHookSource) DeepCopyInto(out *OCIHookSource) {
 	*out = *in
-	out.Capacity = in.Capacity.DeepCopy()
-	in.Supported.DeepCopyInto(&out.Supported)
-	out.Selection = in.Selection
-	in.PVC.DeepCopyInto(&out.PVC)
-	if in.NFS != nil {
-		in, out := &in.NFS, &out.NFS
-		*out = new(v1.NFSVolumeSource)
-		**out = **in
-	}
-	out.ProposedCapacity = in.ProposedCapacity.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIHookSource.
+func (in *OCIHookSource) DeepCopy() *OCIHookSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIHookSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectCountCheck) DeepCopyInto(out *ObjectCountCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectCountCheck.
+func (in *ObjectCountCheck) DeepCopy() *ObjectCountCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectCountCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PV) DeepCopyInto(out *PV) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	in.Supported.DeepCopyInto(&out.Supported)
+	out.Selection = in.Selection
+	in.PVC.DeepCopyInto(&out.PVC)
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(v1.NFSVolumeSource)
+		**out = **in
+	}
+	out.ProposedCapacity = in.ProposedCapacity.DeepCopy()
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PV.
@@ -1522,6 +2091,7 @@ func (in *PVC) DeepCopyInto(out *PVC) {
 		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
 		copy(*out, *in)
 	}
+	out.Capacity = in.Capacity.DeepCopy()
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVC.
@@ -1534,6 +2104,56 @@ func (in *PVC) DeepCopy() *PVC {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCPreview) DeepCopyInto(out *PVCPreview) {
+	*out = *in
+	if in.ObjectReference != nil {
+		in, out := &in.ObjectReference, &out.ObjectReference
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Rule != nil {
+		in, out := &in.Rule, &out.Rule
+		*out = new(int)
+		**out = **in
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeMode != nil {
+		in, out := &in.VolumeMode, &out.VolumeMode
+		*out = new(v1.PersistentVolumeMode)
+		**out = **in
+	}
+	out.Capacity = in.Capacity.DeepCopy()
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCPreview.
+func (in *PVCPreview) DeepCopy() *PVCPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PVCToMigrate) DeepCopyInto(out *PVCToMigrate) {
 	*out = *in
@@ -1559,6 +2179,60 @@ func (in *PVCToMigrate) DeepCopy() *PVCToMigrate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCTransformRule) DeepCopyInto(out *PVCTransformRule) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeMode != nil {
+		in, out := &in.VolumeMode, &out.VolumeMode
+		*out = new(v1.PersistentVolumeMode)
+		**out = **in
+	}
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RemoveLabels != nil {
+		in, out := &in.RemoveLabels, &out.RemoveLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCTransformRule.
+func (in *PVCTransformRule) DeepCopy() *PVCTransformRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCTransformRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistentVolumes) DeepCopyInto(out *PersistentVolumes) {
 	*out = *in
@@ -1643,6 +2317,180 @@ func (in *PodProgress) DeepCopy() *PodProgress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuiesceGroup) DeepCopyInto(out *QuiesceGroup) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainHook != nil {
+		in, out := &in.DrainHook, &out.DrainHook
+		*out = new(QuiesceGroupHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuiesceGroup.
+func (in *QuiesceGroup) DeepCopy() *QuiesceGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(QuiesceGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuiesceGroupHook) DeepCopyInto(out *QuiesceGroupHook) {
+	*out = *in
+	if in.Reference != nil {
+		in, out := &in.Reference, &out.Reference
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuiesceGroupHook.
+func (in *QuiesceGroupHook) DeepCopy() *QuiesceGroupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(QuiesceGroupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedHookSource) DeepCopyInto(out *ResolvedHookSource) {
+	*out = *in
+	if in.ResolvedTimestamp != nil {
+		in, out := &in.ResolvedTimestamp, &out.ResolvedTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedHookSource.
+func (in *ResolvedHookSource) DeepCopy() *ResolvedHookSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedHookSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDiff) DeepCopyInto(out *ResourceDiff) {
+	*out = *in
+	if in.ReportRef != nil {
+		in, out := &in.ReportRef, &out.ReportRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDiff.
+func (in *ResourceDiff) DeepCopy() *ResourceDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFilterRule) DeepCopyInto(out *ResourceFilterRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFilterRule.
+func (in *ResourceFilterRule) DeepCopy() *ResourceFilterRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFilterRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFilters) DeepCopyInto(out *ResourceFilters) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]ResourceFilterRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]ResourceFilterRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFilters.
+func (in *ResourceFilters) DeepCopy() *ResourceFilters {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFilters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RsyncOptions) DeepCopyInto(out *RsyncOptions) {
+	*out = *in
+	if in.BwLimit != nil {
+		in, out := &in.BwLimit, &out.BwLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Partial != nil {
+		in, out := &in.Partial, &out.Partial
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HardLinks != nil {
+		in, out := &in.HardLinks, &out.HardLinks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Extras != nil {
+		in, out := &in.Extras, &out.Extras
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RsyncOptions.
+func (in *RsyncOptions) DeepCopy() *RsyncOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(RsyncOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Selection) DeepCopyInto(out *Selection) {
 	*out = *in
@@ -1747,6 +2595,268 @@ func (in *Timed) DeepCopy() *Timed {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferAttempt) DeepCopyInto(out *TransferAttempt) {
+	*out = *in
+	in.Started.DeepCopyInto(&out.Started)
+	in.Failed.DeepCopyInto(&out.Failed)
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferAttempt.
+func (in *TransferAttempt) DeepCopy() *TransferAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferBandwidth) DeepCopyInto(out *TransferBandwidth) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]BandwidthWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferBandwidth.
+func (in *TransferBandwidth) DeepCopy() *TransferBandwidth {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferBandwidth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferConcurrency) DeepCopyInto(out *TransferConcurrency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferConcurrency.
+func (in *TransferConcurrency) DeepCopy() *TransferConcurrency {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferConcurrency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferRetry) DeepCopyInto(out *TransferRetry) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferRetry.
+func (in *TransferRetry) DeepCopy() *TransferRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferScheduling) DeepCopyInto(out *TransferScheduling) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferScheduling.
+func (in *TransferScheduling) DeepCopy() *TransferScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferSnapshot) DeepCopyInto(out *TransferSnapshot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferSnapshot.
+func (in *TransferSnapshot) DeepCopy() *TransferSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferSpec) DeepCopyInto(out *TransferSpec) {
+	*out = *in
+	if in.Rsync != nil {
+		in, out := &in.Rsync, &out.Rsync
+		*out = new(RsyncOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TransferPodResources != nil {
+		in, out := &in.TransferPodResources, &out.TransferPodResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientPodResources != nil {
+		in, out := &in.ClientPodResources, &out.ClientPodResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StunnelPodResources != nil {
+		in, out := &in.StunnelPodResources, &out.StunnelPodResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(TransferScheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(TransferScheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transport != nil {
+		in, out := &in.Transport, &out.Transport
+		*out = new(TransferTransport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TransferTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(TransferConcurrency)
+		**out = **in
+	}
+	if in.Bandwidth != nil {
+		in, out := &in.Bandwidth, &out.Bandwidth
+		*out = new(TransferBandwidth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(TransferRetry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(TransferSnapshot)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferSpec.
+func (in *TransferSpec) DeepCopy() *TransferSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferTLS) DeepCopyInto(out *TransferTLS) {
+	*out = *in
+	if in.VerifyClient != nil {
+		in, out := &in.VerifyClient, &out.VerifyClient
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferTLS.
+func (in *TransferTLS) DeepCopy() *TransferTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransferTransport) DeepCopyInto(out *TransferTransport) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransferTransport.
+func (in *TransferTransport) DeepCopy() *TransferTransport {
+	if in == nil {
+		return nil
+	}
+	out := new(TransferTransport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnhealthyNamespace) DeepCopyInto(out *UnhealthyNamespace) {
 	*out = *in
@@ -1791,6 +2901,139 @@ func (in *UnhealthyResources) DeepCopy() *UnhealthyResources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Verification) DeepCopyInto(out *Verification) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]VerificationCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Verification.
+func (in *Verification) DeepCopy() *Verification {
+	if in == nil {
+		return nil
+	}
+	out := new(Verification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerificationCheck) DeepCopyInto(out *VerificationCheck) {
+	*out = *in
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(HTTPGetCheck)
+		**out = **in
+	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectCount != nil {
+		in, out := &in.ObjectCount, &out.ObjectCount
+		*out = new(ObjectCountCheck)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerificationCheck.
+func (in *VerificationCheck) DeepCopy() *VerificationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(VerificationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerificationCheckResult) DeepCopyInto(out *VerificationCheckResult) {
+	*out = *in
+	if in.LastAttemptTimestamp != nil {
+		in, out := &in.LastAttemptTimestamp, &out.LastAttemptTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerificationCheckResult.
+func (in *VerificationCheckResult) DeepCopy() *VerificationCheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(VerificationCheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerificationStatus) DeepCopyInto(out *VerificationStatus) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]VerificationCheckResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerificationStatus.
+func (in *VerificationStatus) DeepCopy() *VerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeAttempts) DeepCopyInto(out *VolumeAttempts) {
+	*out = *in
+	if in.ObjectReference != nil {
+		in, out := &in.ObjectReference, &out.ObjectReference
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]TransferAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RetryAt != nil {
+		in, out := &in.RetryAt, &out.RetryAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAttempts.
+func (in *VolumeAttempts) DeepCopy() *VolumeAttempts {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeAttempts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSnapshotConfig) DeepCopyInto(out *VolumeSnapshotConfig) {
 	*out = *in
@@ -0,0 +1,363 @@
+package clusterresource
+
+import (
+	"encoding/json"
+	"strings"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+// Annotation prefix of the SCC users and groups merged by a plan.
+// The annotation key is suffixed by the plan UID.
+const MergedSubjectsAnnotation = "migration.openshift.io/merged-subjects-"
+
+// Service account user and group name prefixes.
+const (
+	serviceAccountUserPrefix  = "system:serviceaccount:"
+	serviceAccountGroupPrefix = "system:serviceaccounts:"
+)
+
+// Get the namespace of a service account user.
+func serviceAccountUserNamespace(user string) (string, bool) {
+	if !strings.HasPrefix(user, serviceAccountUserPrefix) {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(user, serviceAccountUserPrefix), ":")
+	if len(parts) != 2 {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// Get the namespace of a service accounts group.
+func serviceAccountsGroupNamespace(group string) (string, bool) {
+	if !strings.HasPrefix(group, serviceAccountGroupPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(group, serviceAccountGroupPrefix), true
+}
+
+// Build the object created on the destination cluster.
+// The metadata is reduced to the name, labels and annotations, the status
+// is removed and the namespaces referenced by the subjects are mapped to the
+// destination namespaces. The subjects of bindings and the users and groups
+// of SCCs are reduced to the service accounts of the migrated namespaces.
+// The namespaces are the destination namespaces keyed by source namespace.
+func (r *Resource) Desired(namespaces map[string]string) (*unstructured.Unstructured, error) {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(r.Object.GetAPIVersion())
+	object.SetKind(r.Object.GetKind())
+	for k, v := range r.Object.Object {
+		if k == "metadata" || k == "status" || k == "apiVersion" || k == "kind" {
+			continue
+		}
+		object.Object[k] = runtime.DeepCopyJSONValue(v)
+	}
+	object.SetName(r.Object.GetName())
+	object.SetLabels(r.Object.GetLabels())
+	object.SetAnnotations(r.Object.GetAnnotations())
+	switch r.GVR() {
+	case ClusterRoleBindingResource:
+		err := mapSubjects(object, namespaces)
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+	case SCCResource:
+		err := mapSCCSubjects(object, namespaces)
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+	}
+
+	return object, nil
+}
+
+// Reduce the binding subjects to the (mapped) service accounts of the namespaces.
+// Users and groups are kept only when they are the service accounts of the namespaces.
+func mapSubjects(object *unstructured.Unstructured, namespaces map[string]string) error {
+	subjects, found, err := unstructured.NestedSlice(object.Object, "subjects")
+	if err != nil || !found {
+		return liberr.Wrap(err)
+	}
+	mapped := []interface{}{}
+	for _, s := range subjects {
+		subject, cast := s.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		name, _ := subject["name"].(string)
+		switch subject["kind"] {
+		case "ServiceAccount":
+			ns, _ := subject["namespace"].(string)
+			mappedNs, found := namespaces[ns]
+			if !found {
+				continue
+			}
+			subject["namespace"] = mappedNs
+		case "User":
+			mappedUser, found := mapUser(name, namespaces)
+			if !found {
+				continue
+			}
+			subject["name"] = mappedUser
+		case "Group":
+			ns, found := serviceAccountsGroupNamespace(name)
+			if !found {
+				continue
+			}
+			if _, found := namespaces[ns]; !found {
+				continue
+			}
+			subject["name"] = mapGroup(name, namespaces)
+		default:
+			continue
+		}
+		mapped = append(mapped, subject)
+	}
+	return liberr.Wrap(unstructured.SetNestedSlice(object.Object, mapped, "subjects"))
+}
+
+// Map the namespace of a service account user.
+// Returns `false` when the user is not a service account of the namespaces.
+func mapUser(user string, namespaces map[string]string) (string, bool) {
+	ns, found := serviceAccountUserNamespace(user)
+	if !found {
+		return "", false
+	}
+	mapped, found := namespaces[ns]
+	if !found {
+		return "", false
+	}
+	name := strings.TrimPrefix(user, serviceAccountUserPrefix+ns+":")
+	return serviceAccountUserPrefix + mapped + ":" + name, true
+}
+
+// Reduce the SCC users and groups to the (mapped) service accounts of the namespaces.
+func mapSCCSubjects(object *unstructured.Unstructured, namespaces map[string]string) error {
+	users, _, err := unstructured.NestedStringSlice(object.Object, "users")
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	mappedUsers := []interface{}{}
+	for _, user := range users {
+		if mapped, found := mapUser(user, namespaces); found {
+			mappedUsers = append(mappedUsers, mapped)
+		}
+	}
+	groups, _, err := unstructured.NestedStringSlice(object.Object, "groups")
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	mappedGroups := []interface{}{}
+	for _, group := range groups {
+		ns, found := serviceAccountsGroupNamespace(group)
+		if !found {
+			continue
+		}
+		if _, found := namespaces[ns]; found {
+			mappedGroups = append(mappedGroups, mapGroup(group, namespaces))
+		}
+	}
+	object.Object["users"] = mappedUsers
+	object.Object["groups"] = mappedGroups
+	return nil
+}
+
+// Map the namespace of a service accounts group.
+func mapGroup(group string, namespaces map[string]string) string {
+	ns, found := serviceAccountsGroupNamespace(group)
+	if !found {
+		return group
+	}
+	if mapped, found := namespaces[ns]; found {
+		return serviceAccountGroupPrefix + mapped
+	}
+	return group
+}
+
+// Get whether the desired object conflicts with the object found on the destination cluster.
+// The metadata and status are ignored, the SCC users and groups are merged and ignored.
+func Conflicts(desired, existing *unstructured.Unstructured) bool {
+	return !equality.Semantic.DeepEqual(content(desired), content(existing))
+}
+
+// Get the content compared for conflicts.
+func content(object *unstructured.Unstructured) map[string]interface{} {
+	content := map[string]interface{}{}
+	for k, v := range object.Object {
+		switch k {
+		case "apiVersion", "metadata", "status":
+			continue
+		}
+		if object.GetKind() == kinds[SCCResource] && (k == "users" || k == "groups") {
+			continue
+		}
+		content[k] = v
+	}
+	return content
+}
+
+// Merge the SCC users and groups of the desired object into the existing object.
+// Returns the users and groups added to the existing object keyed by field.
+func MergeSubjects(desired, existing *unstructured.Unstructured) map[string][]string {
+	added := map[string][]string{}
+	for _, field := range []string{"users", "groups"} {
+		wanted, _, _ := unstructured.NestedStringSlice(desired.Object, field)
+		current, _, _ := unstructured.NestedStringSlice(existing.Object, field)
+		merged := append([]string{}, current...)
+		for _, name := range wanted {
+			if !contains(current, name) {
+				merged = append(merged, name)
+				added[field] = append(added[field], name)
+			}
+		}
+		_ = unstructured.SetNestedStringSlice(existing.Object, merged, field)
+	}
+	return added
+}
+
+// Record the SCC users and groups merged by the owner (plan UID).
+// The subjects are recorded in an annotation of the existing object
+// along with the subjects recorded by previous migrations.
+func RecordSubjects(existing *unstructured.Unstructured, owner string, added map[string][]string) error {
+	recorded, err := recordedSubjects(existing, owner)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for field, names := range added {
+		for _, name := range names {
+			if !contains(recorded[field], name) {
+				recorded[field] = append(recorded[field], name)
+			}
+		}
+	}
+	encoded, err := json.Marshal(recorded)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	annotations := existing.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[MergedSubjectsAnnotation+owner] = string(encoded)
+	existing.SetAnnotations(annotations)
+	return nil
+}
+
+// Remove the SCC users and groups merged by the owner (plan UID).
+// Returns `true` when the existing object is updated.
+func RevertSubjects(existing *unstructured.Unstructured, owner string) (bool, error) {
+	annotations := existing.GetAnnotations()
+	if _, found := annotations[MergedSubjectsAnnotation+owner]; !found {
+		return false, nil
+	}
+	recorded, err := recordedSubjects(existing, owner)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	for field, names := range recorded {
+		current, _, _ := unstructured.NestedStringSlice(existing.Object, field)
+		kept := []string{}
+		for _, name := range current {
+			if !contains(names, name) {
+				kept = append(kept, name)
+			}
+		}
+		_ = unstructured.SetNestedStringSlice(existing.Object, kept, field)
+	}
+	delete(annotations, MergedSubjectsAnnotation+owner)
+	existing.SetAnnotations(annotations)
+	return true, nil
+}
+
+// Get the SCC users and groups recorded for the owner keyed by field.
+func recordedSubjects(existing *unstructured.Unstructured, owner string) (map[string][]string, error) {
+	recorded := map[string][]string{}
+	value, found := existing.GetAnnotations()[MergedSubjectsAnnotation+owner]
+	if !found {
+		return recorded, nil
+	}
+	err := json.Unmarshal([]byte(value), &recorded)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	return recorded, nil
+}
+
+// Get whether a list contains a name.
+func contains(list []string, name string) bool {
+	for _, s := range list {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the object found on the destination cluster.
+// Returns nil when not found.
+func (r *Resource) Existing(client dynamic.Interface) (*unstructured.Unstructured, error) {
+	object, err := client.Resource(r.GVR()).Get(r.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, liberr.Wrap(err)
+	}
+	return object, nil
+}
+
+// Check the resources against the destination cluster.
+// Sets whether each resource exists and conflicts. Objects labeled by
+// the owner (created by a previous migration) do not conflict.
+func Check(client dynamic.Interface, resources []*Resource, namespaces map[string]string, owner labels.Set) error {
+	for _, resource := range resources {
+		existing, err := resource.Existing(client)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		resource.Exists = existing != nil
+		resource.Conflict = false
+		if existing == nil || Owned(existing, owner) {
+			continue
+		}
+		desired, err := resource.Desired(namespaces)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		resource.Conflict = Conflicts(desired, existing)
+	}
+
+	return nil
+}
+
+// Get whether an object is labeled by the owner.
+func Owned(object *unstructured.Unstructured, owner labels.Set) bool {
+	return len(owner) > 0 && labels.SelectorFromSet(owner).Matches(labels.Set(object.GetLabels()))
+}
+
+// Get whether a created object is ready to be used.
+// CRDs are ready when established, other objects are ready when created.
+func Established(object *unstructured.Unstructured) bool {
+	if object.GetKind() != kinds[CRDResource] {
+		return true
+	}
+	conditions, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, cast := c.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		if condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
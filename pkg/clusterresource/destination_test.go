@@ -0,0 +1,213 @@
+package clusterresource
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Build a resource found on the source cluster.
+func resource(gvr string, content map[string]interface{}) *Resource {
+	found := Resource{}
+	for _, r := range Resources {
+		if r.Resource == gvr {
+			found.ClusterResource = migapi.ClusterResource{
+				Group:   r.Group,
+				Version: r.Version,
+				Kind:    kinds[r],
+				Name:    "test",
+			}
+		}
+	}
+	object := &unstructured.Unstructured{Object: content}
+	object.SetAPIVersion(found.GVR().GroupVersion().String())
+	object.SetKind(found.Kind)
+	object.SetName(found.Name)
+	object.SetUID("1234")
+	object.SetResourceVersion("10")
+	found.Object = object
+	return &found
+}
+
+func TestResource_Desired(t *testing.T) {
+	namespaces := map[string]string{"app": "app-new", "db": "db"}
+	tests := []struct {
+		name     string
+		resource *Resource
+		field    string
+		want     interface{}
+	}{
+		{
+			name: "binding subjects reduced and mapped",
+			resource: resource("clusterrolebindings", map[string]interface{}{
+				"subjects": []interface{}{
+					map[string]interface{}{"kind": "ServiceAccount", "name": "sa", "namespace": "app"},
+					map[string]interface{}{"kind": "ServiceAccount", "name": "sa", "namespace": "other"},
+					map[string]interface{}{"kind": "User", "name": "admin"},
+					map[string]interface{}{"kind": "User", "name": "system:serviceaccount:db:sa"},
+					map[string]interface{}{"kind": "User", "name": "system:serviceaccount:other:sa"},
+					map[string]interface{}{"kind": "Group", "name": "system:authenticated"},
+					map[string]interface{}{"kind": "Group", "name": "system:serviceaccounts:app"},
+					map[string]interface{}{"kind": "Group", "name": "system:serviceaccounts:other"},
+				},
+			}),
+			field: "subjects",
+			want: []interface{}{
+				map[string]interface{}{"kind": "ServiceAccount", "name": "sa", "namespace": "app-new"},
+				map[string]interface{}{"kind": "User", "name": "system:serviceaccount:db:sa"},
+				map[string]interface{}{"kind": "Group", "name": "system:serviceaccounts:app-new"},
+			},
+		},
+		{
+			name: "scc users reduced and mapped",
+			resource: resource("securitycontextconstraints", map[string]interface{}{
+				"users": []interface{}{
+					"system:admin",
+					"system:serviceaccount:app:sa",
+					"system:serviceaccount:other:sa",
+				},
+			}),
+			field: "users",
+			want:  []interface{}{"system:serviceaccount:app-new:sa"},
+		},
+		{
+			name: "scc groups reduced",
+			resource: resource("securitycontextconstraints", map[string]interface{}{
+				"groups": []interface{}{
+					"system:authenticated",
+					"system:serviceaccounts:db",
+				},
+			}),
+			field: "groups",
+			want:  []interface{}{"system:serviceaccounts:db"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resource.Desired(namespaces)
+			if err != nil {
+				t.Fatalf("Desired() error = %v", err)
+			}
+			if got.GetUID() != "" || got.GetResourceVersion() != "" {
+				t.Errorf("Desired() metadata not reduced")
+			}
+			if !reflect.DeepEqual(got.Object[tt.field], tt.want) {
+				t.Errorf("Desired() %s = %v, want %v", tt.field, got.Object[tt.field], tt.want)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	desired := resource("storageclasses", map[string]interface{}{"provisioner": "a"}).Object
+	same := resource("storageclasses", map[string]interface{}{"provisioner": "a"}).Object
+	same.SetUID("5678")
+	different := resource("storageclasses", map[string]interface{}{"provisioner": "b"}).Object
+	scc := resource("securitycontextconstraints", map[string]interface{}{"users": []interface{}{"a"}}).Object
+	otherUsers := resource("securitycontextconstraints", map[string]interface{}{"users": []interface{}{"b"}}).Object
+	tests := []struct {
+		name     string
+		desired  *unstructured.Unstructured
+		existing *unstructured.Unstructured
+		want     bool
+	}{
+		{name: "metadata ignored", desired: desired, existing: same, want: false},
+		{name: "content differs", desired: desired, existing: different, want: true},
+		{name: "scc users ignored", desired: scc, existing: otherUsers, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Conflicts(tt.desired, tt.existing); got != tt.want {
+				t.Errorf("Conflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSubjects(t *testing.T) {
+	desired := resource("securitycontextconstraints", map[string]interface{}{
+		"users": []interface{}{"system:serviceaccount:app:sa"},
+	}).Object
+	existing := resource("securitycontextconstraints", map[string]interface{}{
+		"users": []interface{}{"system:admin"},
+	}).Object
+	added := MergeSubjects(desired, existing)
+	if !reflect.DeepEqual(added, map[string][]string{"users": {"system:serviceaccount:app:sa"}}) {
+		t.Fatalf("MergeSubjects() = %v", added)
+	}
+	users, _, _ := unstructured.NestedStringSlice(existing.Object, "users")
+	want := []string{"system:admin", "system:serviceaccount:app:sa"}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("MergeSubjects() users = %v, want %v", users, want)
+	}
+	if added := MergeSubjects(desired, existing); len(added) != 0 {
+		t.Errorf("MergeSubjects() = %v, want none when merged", added)
+	}
+}
+
+func TestRevertSubjects(t *testing.T) {
+	desired := resource("securitycontextconstraints", map[string]interface{}{
+		"users":  []interface{}{"system:serviceaccount:app:sa"},
+		"groups": []interface{}{"system:serviceaccounts:app"},
+	}).Object
+	existing := resource("securitycontextconstraints", map[string]interface{}{
+		"users": []interface{}{"system:admin"},
+	}).Object
+	err := RecordSubjects(existing, "plan", MergeSubjects(desired, existing))
+	if err != nil {
+		t.Fatalf("RecordSubjects() error = %v", err)
+	}
+	reverted, err := RevertSubjects(existing, "other-plan")
+	if err != nil || reverted {
+		t.Fatalf("RevertSubjects() = %v, %v, want false for another plan", reverted, err)
+	}
+	reverted, err = RevertSubjects(existing, "plan")
+	if err != nil || !reverted {
+		t.Fatalf("RevertSubjects() = %v, %v, want true", reverted, err)
+	}
+	users, _, _ := unstructured.NestedStringSlice(existing.Object, "users")
+	if !reflect.DeepEqual(users, []string{"system:admin"}) {
+		t.Errorf("RevertSubjects() users = %v", users)
+	}
+	groups, _, _ := unstructured.NestedStringSlice(existing.Object, "groups")
+	if len(groups) != 0 {
+		t.Errorf("RevertSubjects() groups = %v", groups)
+	}
+	if _, found := existing.GetAnnotations()[MergedSubjectsAnnotation+"plan"]; found {
+		t.Errorf("RevertSubjects() annotation not removed")
+	}
+}
+
+func TestOwned(t *testing.T) {
+	object := resource("clusterroles", map[string]interface{}{}).Object
+	object.SetLabels(map[string]string{"plan": "1"})
+	if !Owned(object, labels.Set{"plan": "1"}) {
+		t.Errorf("Owned() = false, want true")
+	}
+	if Owned(object, labels.Set{"plan": "2"}) {
+		t.Errorf("Owned() = true, want false")
+	}
+	if Owned(object, labels.Set{}) {
+		t.Errorf("Owned() = true, want false without owner")
+	}
+}
+
+func TestCRDResource(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group": "example.com",
+			"names": map[string]interface{}{"plural": "widgets", "kind": "Widget"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": false},
+				map[string]interface{}{"name": "v1", "served": true},
+			},
+		},
+	}}
+	gvr, kind := crdResource(crd)
+	if gvr.String() != "example.com/v1, Resource=widgets" || kind != "Widget" {
+		t.Errorf("crdResource() = %v, %v", gvr, kind)
+	}
+}
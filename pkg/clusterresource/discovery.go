@@ -0,0 +1,425 @@
+package clusterresource
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Cluster-scoped resources.
+var (
+	CRDResource = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+	ClusterRoleResource = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "clusterroles",
+	}
+	ClusterRoleBindingResource = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "clusterrolebindings",
+	}
+	SCCResource = schema.GroupVersionResource{
+		Group:    "security.openshift.io",
+		Version:  "v1",
+		Resource: "securitycontextconstraints",
+	}
+	StorageClassResource = schema.GroupVersionResource{
+		Group:    "storage.k8s.io",
+		Version:  "v1",
+		Resource: "storageclasses",
+	}
+)
+
+// The cluster-scoped resources migrated.
+var Resources = []schema.GroupVersionResource{
+	CRDResource,
+	ClusterRoleResource,
+	ClusterRoleBindingResource,
+	SCCResource,
+	StorageClassResource,
+}
+
+// Namespaced resources referencing the cluster-scoped resources.
+var (
+	podResource = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "pods",
+	}
+	pvcResource = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "persistentvolumeclaims",
+	}
+	roleBindingResource = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "rolebindings",
+	}
+)
+
+// Kinds of the cluster-scoped resources.
+var kinds = map[schema.GroupVersionResource]string{
+	CRDResource:                "CustomResourceDefinition",
+	ClusterRoleResource:        "ClusterRole",
+	ClusterRoleBindingResource: "ClusterRoleBinding",
+	SCCResource:                "SecurityContextConstraints",
+	StorageClassResource:       "StorageClass",
+}
+
+// Annotations and labels.
+const (
+	// Set by OpenShift on pods with the name of the SCC admitting the pod.
+	SCCAnnotation = "openshift.io/scc"
+	// Set on the RBAC resources created by the cluster (bootstrap policy).
+	BootstrappingLabel = "kubernetes.io/bootstrapping"
+	// The (deprecated) storage class annotation on PVCs.
+	StorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+)
+
+// Prefix of the names reserved for the cluster (system) resources.
+const systemPrefix = "system:"
+
+// A cluster-scoped resource and the object found on the source cluster.
+type Resource struct {
+	migapi.ClusterResource
+	Object *unstructured.Unstructured
+}
+
+// Get the resource GVR.
+func (r *Resource) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    r.Group,
+		Version:  r.Version,
+		Resource: resourceOf(r.Kind),
+	}
+}
+
+// Get the resource (name) of a kind.
+func resourceOf(kind string) string {
+	for gvr, k := range kinds {
+		if k == kind {
+			return gvr.Resource
+		}
+	}
+	return ""
+}
+
+// Discovery of the cluster-scoped resources the namespaces depend on.
+type Discovery struct {
+	// The source cluster client.
+	Client dynamic.Interface
+	// The kinds of resources discovered.
+	Options *migapi.ClusterResources
+	// The (source) namespaces.
+	Namespaces []string
+	// Resources found, keyed by GVR and name.
+	found map[string]*Resource
+	// Resources found in order.
+	list []*Resource
+}
+
+// Discover the cluster-scoped resources.
+func (r *Discovery) Run() ([]*Resource, error) {
+	r.found = map[string]*Resource{}
+	r.list = []*Resource{}
+	if !r.Options.Enabled() {
+		return r.list, nil
+	}
+	r.Namespaces = append([]string{}, r.Namespaces...)
+	sort.Strings(r.Namespaces)
+	steps := []struct {
+		enabled  bool
+		discover func() error
+	}{
+		{r.Options.CRDs, r.discoverCRDs},
+		{r.Options.RBAC, r.discoverRBAC},
+		{r.Options.SCCs, r.discoverSCCs},
+		{r.Options.StorageClasses, r.discoverStorageClasses},
+	}
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+		err := step.discover()
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+	}
+
+	return r.list, nil
+}
+
+// Discover the CRDs of the custom resources found in the namespaces.
+func (r *Discovery) discoverCRDs() error {
+	list, err := r.Client.Resource(CRDResource).List(metav1.ListOptions{})
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		crd := &list.Items[i]
+		scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+		if scope != "Namespaced" {
+			continue
+		}
+		gvr, kind := crdResource(crd)
+		if gvr.Version == "" {
+			continue
+		}
+		for _, ns := range r.Namespaces {
+			objects, err := r.Client.Resource(gvr).Namespace(ns).List(metav1.ListOptions{Limit: 1})
+			if err != nil {
+				if k8serror.IsNotFound(err) || k8serror.IsMethodNotSupported(err) {
+					break
+				}
+				return liberr.Wrap(err)
+			}
+			if len(objects.Items) > 0 {
+				reason := kind + " " + path.Join(ns, objects.Items[0].GetName())
+				r.add(CRDResource, crd, reason)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// Get the (served) resource and kind defined by a CRD.
+func crdResource(crd *unstructured.Unstructured) (schema.GroupVersionResource, string) {
+	gvr := schema.GroupVersionResource{}
+	gvr.Group, _, _ = unstructured.NestedString(crd.Object, "spec", "group")
+	gvr.Resource, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, cast := v.(map[string]interface{})
+		if !cast {
+			continue
+		}
+		served, _, _ := unstructured.NestedBool(version, "served")
+		if served {
+			gvr.Version, _, _ = unstructured.NestedString(version, "name")
+			break
+		}
+	}
+	if gvr.Version == "" {
+		gvr.Version, _, _ = unstructured.NestedString(crd.Object, "spec", "version")
+	}
+	return gvr, kind
+}
+
+// Discover the ClusterRoleBindings bound to the service accounts of the
+// namespaces and the ClusterRoles referenced by the bindings (including
+// the RoleBindings) of the namespaces. The bootstrap policy is not migrated.
+func (r *Discovery) discoverRBAC() error {
+	list, err := r.Client.Resource(ClusterRoleBindingResource).List(metav1.ListOptions{})
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		object := &list.Items[i]
+		if isBootstrapPolicy(object) {
+			continue
+		}
+		binding := rbacv1.ClusterRoleBinding{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &binding)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		subject := r.boundSubject(binding.Subjects)
+		if subject == "" {
+			continue
+		}
+		r.add(ClusterRoleBindingResource, object, subject)
+		err = r.addClusterRole(binding.RoleRef, "ClusterRoleBinding "+binding.Name)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+	for _, ns := range r.Namespaces {
+		list, err := r.Client.Resource(roleBindingResource).Namespace(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		for i := range list.Items {
+			binding := rbacv1.RoleBinding{}
+			err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, &binding)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+			err = r.addClusterRole(binding.RoleRef, "RoleBinding "+path.Join(ns, binding.Name))
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Get the first subject in the namespaces.
+// Returns "" when no subjects are in the namespaces.
+func (r *Discovery) boundSubject(subjects []rbacv1.Subject) string {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			if r.hasNamespace(subject.Namespace) {
+				return subject.Kind + " " + path.Join(subject.Namespace, subject.Name)
+			}
+		case rbacv1.GroupKind:
+			if ns, found := serviceAccountsGroupNamespace(subject.Name); found && r.hasNamespace(ns) {
+				return subject.Kind + " " + subject.Name
+			}
+		}
+	}
+	return ""
+}
+
+// Add the ClusterRole referenced by a binding.
+func (r *Discovery) addClusterRole(ref rbacv1.RoleRef, reason string) error {
+	if ref.Kind != "ClusterRole" {
+		return nil
+	}
+	role, err := r.get(ClusterRoleResource, ref.Name)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if role == nil || isBootstrapPolicy(role) {
+		return nil
+	}
+	r.add(ClusterRoleResource, role, reason)
+	return nil
+}
+
+// Discover the SCCs used by the pods of the namespaces.
+// Clusters without SCCs (not OpenShift) are skipped.
+func (r *Discovery) discoverSCCs() error {
+	for _, ns := range r.Namespaces {
+		list, err := r.Client.Resource(podResource).Namespace(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		for i := range list.Items {
+			pod := &list.Items[i]
+			name := pod.GetAnnotations()[SCCAnnotation]
+			if name == "" {
+				continue
+			}
+			scc, err := r.get(SCCResource, name)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+			if scc == nil {
+				continue
+			}
+			r.add(SCCResource, scc, "Pod "+path.Join(ns, pod.GetName()))
+		}
+	}
+
+	return nil
+}
+
+// Discover the StorageClasses of the PVCs found in the namespaces.
+func (r *Discovery) discoverStorageClasses() error {
+	for _, ns := range r.Namespaces {
+		list, err := r.Client.Resource(pvcResource).Namespace(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		for i := range list.Items {
+			pvc := &list.Items[i]
+			name, _, _ := unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+			if name == "" {
+				name = pvc.GetAnnotations()[StorageClassAnnotation]
+			}
+			if name == "" {
+				continue
+			}
+			class, err := r.get(StorageClassResource, name)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+			if class == nil {
+				continue
+			}
+			r.add(StorageClassResource, class, "PersistentVolumeClaim "+path.Join(ns, pvc.GetName()))
+		}
+	}
+
+	return nil
+}
+
+// Get a resource.
+// Returns nil when not found.
+func (r *Discovery) get(gvr schema.GroupVersionResource, name string) (*unstructured.Unstructured, error) {
+	if found, ok := r.found[key(gvr, name)]; ok {
+		return found.Object, nil
+	}
+	object, err := r.Client.Resource(gvr).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, liberr.Wrap(err)
+	}
+	return object, nil
+}
+
+// Add a resource.
+// Resources already found are ignored.
+func (r *Discovery) add(gvr schema.GroupVersionResource, object *unstructured.Unstructured, reason string) {
+	k := key(gvr, object.GetName())
+	if _, found := r.found[k]; found {
+		return
+	}
+	resource := &Resource{
+		ClusterResource: migapi.ClusterResource{
+			Group:   gvr.Group,
+			Version: gvr.Version,
+			Kind:    kinds[gvr],
+			Name:    object.GetName(),
+			Reason:  reason,
+		},
+		Object: object,
+	}
+	r.found[k] = resource
+	r.list = append(r.list, resource)
+}
+
+// Get whether a namespace is migrated.
+func (r *Discovery) hasNamespace(namespace string) bool {
+	for _, ns := range r.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Get whether an object is part of the RBAC bootstrap policy.
+func isBootstrapPolicy(object *unstructured.Unstructured) bool {
+	if _, found := object.GetLabels()[BootstrappingLabel]; found {
+		return true
+	}
+	return strings.HasPrefix(object.GetName(), systemPrefix)
+}
+
+// Get the resource key.
+func key(gvr schema.GroupVersionResource, name string) string {
+	return fmt.Sprintf("%s/%s", gvr.String(), name)
+}
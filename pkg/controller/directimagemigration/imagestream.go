@@ -151,6 +151,8 @@ func (t *Task) buildDirectImageStreamMigration(is imagev1.ImageStream, destNsNam
 				Name:      is.Name,
 				Namespace: is.Namespace,
 			},
+			ExternalImages:    t.Owner.Spec.ExternalImages,
+			DestImageRegistry: t.Owner.Spec.DestImageRegistry,
 		},
 	}
 	migapi.SetOwnerReference(t.Owner, t.Owner, &imageStreamMigration)
@@ -181,10 +183,12 @@ func (t *Task) checkDISMCompletion() (bool, []string) {
 			t.Owner.Status.FailedISs = append(t.Owner.Status.FailedISs, item)
 			continue
 		}
+		item.TagProgress = dism.Status.TagProgress
 		dismCompleted, dismErrors := dism.HasCompleted()
 		switch {
 		case dismCompleted && len(dismErrors) == 0:
 			t.Owner.Status.SuccessfulISs = append(t.Owner.Status.SuccessfulISs, item)
+			t.Owner.AddImageMappings(dism.Status.ImageMappings)
 		case dismCompleted:
 			item.Errors = append(item.Errors, dismErrors...)
 			t.Owner.Status.FailedISs = append(t.Owner.Status.FailedISs, item)
@@ -41,6 +41,9 @@ const (
 	MissingDestinationClusterRegistryPath = "MissingDestinationClusterRegistryPath"
 	NsListEmpty                           = "NamespaceListEmpty"
 	NsNotFoundOnSourceCluster             = "NamespaceNotFoundOnSourceCluster"
+	InvalidRegistryCredentialsSecretRef   = "InvalidRegistryCredentialsSecretRef"
+	InvalidRegistryCredentialsSecret      = "InvalidRegistryCredentialsSecret"
+	InvalidDestImageRegistry              = "InvalidDestImageRegistry"
 )
 
 // Validate the image migration resource
@@ -58,6 +61,16 @@ func (r ReconcileDirectImageMigration) validate(imageMigration *migapi.DirectIma
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	// External images.
+	err = r.validateExternalImages(imageMigration)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	// Destination image registry.
+	err = r.validateDestImageRegistry(imageMigration)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	return nil
 }
 
@@ -174,7 +187,10 @@ func (r ReconcileDirectImageMigration) validateDestCluster(imageMigration *migap
 				path.Join(imageMigration.Spec.DestMigClusterRef.Namespace, imageMigration.Spec.DestMigClusterRef.Name)),
 		})
 	}
-	// Exposed registry path
+	// Exposed registry path, not needed when pushing to a registry outside of the cluster.
+	if imageMigration.Spec.DestImageRegistry != nil {
+		return nil
+	}
 	registryPath, err := cluster.GetRegistryPath(r)
 	if err != nil || registryPath == "" {
 		imageMigration.Status.SetCondition(migapi.Condition{
@@ -240,3 +256,84 @@ func (r ReconcileDirectImageMigration) validateNamespaces(imageMigration *migapi
 
 	return nil
 }
+
+// Validate the registry credentials secret used for external images.
+func (r ReconcileDirectImageMigration) validateExternalImages(imageMigration *migapi.DirectImageMigration) error {
+	externalImages := imageMigration.Spec.ExternalImages
+	if externalImages == nil || !externalImages.Enabled {
+		return nil
+	}
+	return r.validateCredentialsSecret(
+		imageMigration,
+		externalImages.CredentialsSecretRef,
+		"spec.externalImages.credentialsSecretRef")
+}
+
+// Validate the registry images are pushed to when not using the destination cluster registry.
+func (r ReconcileDirectImageMigration) validateDestImageRegistry(imageMigration *migapi.DirectImageMigration) error {
+	destImageRegistry := imageMigration.Spec.DestImageRegistry
+	if destImageRegistry == nil {
+		return nil
+	}
+	if destImageRegistry.Registry == "" {
+		imageMigration.Status.SetCondition(migapi.Condition{
+			Type:     InvalidDestImageRegistry,
+			Status:   migapi.True,
+			Reason:   migapi.NotSet,
+			Category: migapi.Critical,
+			Message:  "spec.destImageRegistry.registry must be set",
+		})
+		return nil
+	}
+	return r.validateCredentialsSecret(
+		imageMigration,
+		destImageRegistry.CredentialsSecretRef,
+		"spec.destImageRegistry.credentialsSecretRef")
+}
+
+// Validate a referenced `kubernetes.io/dockerconfigjson` registry credentials secret.
+func (r ReconcileDirectImageMigration) validateCredentialsSecret(imageMigration *migapi.DirectImageMigration, ref *kapi.ObjectReference, field string) error {
+	// Not needed.
+	if ref == nil {
+		return nil
+	}
+	if !migref.RefSet(ref) {
+		imageMigration.Status.SetCondition(migapi.Condition{
+			Type:     InvalidRegistryCredentialsSecretRef,
+			Status:   migapi.True,
+			Reason:   migapi.NotSet,
+			Category: migapi.Critical,
+			Message:  fmt.Sprintf("%s must reference name and namespace for a valid `secret`", field),
+		})
+		return nil
+	}
+	secret, err := migapi.GetSecret(r, ref)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	// Not found
+	if secret == nil {
+		imageMigration.Status.SetCondition(migapi.Condition{
+			Type:     InvalidRegistryCredentialsSecretRef,
+			Status:   migapi.True,
+			Reason:   migapi.NotFound,
+			Category: migapi.Critical,
+			Message: fmt.Sprintf("%s %s must reference a valid `secret`",
+				field, path.Join(ref.Namespace, ref.Name)),
+		})
+		return nil
+	}
+	// Wrong format
+	if _, found := secret.Data[kapi.DockerConfigJsonKey]; !found {
+		imageMigration.Status.SetCondition(migapi.Condition{
+			Type:     InvalidRegistryCredentialsSecret,
+			Status:   migapi.True,
+			Reason:   migapi.NotFound,
+			Category: migapi.Critical,
+			Message: fmt.Sprintf("The `%s` key not found in the registry credentials secret %s",
+				kapi.DockerConfigJsonKey,
+				path.Join(ref.Namespace, ref.Name)),
+		})
+	}
+	return nil
+}
@@ -2,12 +2,17 @@ package directimagestreammigration
 
 import (
 	"errors"
+	"path"
+	"strings"
 
 	"github.com/containers/image/v5/copy"
 	"github.com/containers/image/v5/types"
 	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	"github.com/konveyor/mig-controller/pkg/compat"
 	"github.com/konveyor/openshift-velero-plugin/velero-plugins/imagecopy"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/library-go/pkg/image/reference"
 )
 
 func (t *Task) migrateInternalImages() error {
@@ -19,11 +24,6 @@ func (t *Task) migrateInternalImages() error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	destCluster, err := t.Owner.GetDestinationCluster(t.Client)
-	if err != nil {
-		return liberr.Wrap(err)
-	}
-
 	srcInternalRegistry, err := srcCluster.GetInternalRegistryPath(t.Client)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -40,18 +40,10 @@ func (t *Task) migrateInternalImages() error {
 		return liberr.Wrap(errors.New("Source cluster registry path not found"))
 	}
 
-	destRegistry, err := destCluster.GetRegistryPath(t.Client)
+	destRegistry, destNamespace, destinationCtx, err := t.getDestination()
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	if destRegistry == "" {
-		return liberr.Wrap(errors.New("Source cluster registry path not found"))
-	}
-
-	destNamespace := t.Owner.GetDestinationNamespace()
-	if destNamespace == "" {
-		return liberr.Wrap(errors.New("Destination namespace not found"))
-	}
 
 	srcClient, err := t.getSourceClient()
 	if err != nil {
@@ -62,26 +54,112 @@ func (t *Task) migrateInternalImages() error {
 		return liberr.Wrap(err)
 	}
 
+	options := &copy.Options{
+		SourceCtx:      sourceCtx,
+		DestinationCtx: destinationCtx,
+	}
+	// Copy tag by tag to report the progress of each tag.
+	for _, tag := range imageStream.Status.Tags {
+		images := []tagImage{}
+		for _, item := range tag.Items {
+			if !strings.HasPrefix(item.DockerImageReference, srcInternalRegistry) {
+				continue
+			}
+			images = append(images, tagImage{
+				src: "docker://" + srcRegistry + strings.TrimPrefix(item.DockerImageReference, srcInternalRegistry),
+				ctx: sourceCtx,
+			})
+		}
+		if len(images) == 0 {
+			continue
+		}
+		tagImageStream := *imageStream
+		tagImageStream.Status.Tags = []imagev1.NamedTagEventList{tag}
+		err = t.copyTag(tag.Tag, tag.Items[0].Image, images, options, func(options *copy.Options) error {
+			return imagecopy.CopyLocalImageStreamImages(tagImageStream,
+				srcInternalRegistry,
+				srcRegistry,
+				destRegistry,
+				destNamespace,
+				options,
+				t.Log,
+				false)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Record where the internal images have been migrated to.
+	destRepository := path.Join(destRegistry, destNamespace, imageStream.Name)
+	mappings := []migapi.ImageMapping{}
+	for _, tag := range imageStream.Status.Tags {
+		for _, item := range tag.Items {
+			if !strings.HasPrefix(item.DockerImageReference, srcInternalRegistry) {
+				continue
+			}
+			ref, err := reference.Parse(item.DockerImageReference)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+			mappings = append(mappings, migapi.ImageMapping{
+				Source:      ref.AsRepository().Exact(),
+				Destination: destRepository,
+			})
+		}
+	}
+	t.Owner.AddImageMappings(mappings)
+
+	return nil
+}
+
+// Get the registry, the namespace (or project) within the registry and the
+// system context used to push images to the destination.
+func (t *Task) getDestination() (string, string, *types.SystemContext, error) {
+	destNamespace := t.Owner.GetDestinationNamespace()
+	if destNamespace == "" {
+		return "", "", nil, liberr.Wrap(errors.New("Destination namespace not found"))
+	}
+
+	// Registry outside of the destination cluster.
+	destImageRegistry := t.Owner.Spec.DestImageRegistry
+	if destImageRegistry != nil {
+		if destImageRegistry.Registry == "" {
+			return "", "", nil, liberr.Wrap(errors.New("Destination image registry not set"))
+		}
+		credentials, err := t.getCredentials(destImageRegistry.CredentialsSecretRef)
+		if err != nil {
+			return "", "", nil, liberr.Wrap(err)
+		}
+		registry := strings.TrimSuffix(destImageRegistry.Registry, "/")
+		ctx := externalRegistrySystemContext(credentials, registry)
+		if destImageRegistry.Insecure {
+			ctx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
+		}
+		return registry, destImageRegistry.GetProject(destNamespace), ctx, nil
+	}
+
+	// Destination cluster registry.
+	destCluster, err := t.Owner.GetDestinationCluster(t.Client)
+	if err != nil {
+		return "", "", nil, liberr.Wrap(err)
+	}
+	destRegistry, err := destCluster.GetRegistryPath(t.Client)
+	if err != nil {
+		return "", "", nil, liberr.Wrap(err)
+	}
+	if destRegistry == "" {
+		return "", "", nil, liberr.Wrap(errors.New("Destination cluster registry path not found"))
+	}
 	destClient, err := t.getDestinationClient()
 	if err != nil {
-		return liberr.Wrap(err)
+		return "", "", nil, liberr.Wrap(err)
 	}
 	destinationCtx, err := internalRegistrySystemContext(destClient)
 	if err != nil {
-		return liberr.Wrap(err)
+		return "", "", nil, liberr.Wrap(err)
 	}
-
-	return imagecopy.CopyLocalImageStreamImages(*imageStream,
-		srcInternalRegistry,
-		srcRegistry,
-		destRegistry,
-		destNamespace,
-		&copy.Options{
-			SourceCtx:      sourceCtx,
-			DestinationCtx: destinationCtx,
-		},
-		t.Log,
-		false)
+	return destRegistry, destNamespace, destinationCtx, nil
 }
 
 func internalRegistrySystemContext(c compat.Client) (*types.SystemContext, error) {
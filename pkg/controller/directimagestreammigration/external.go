@@ -0,0 +1,286 @@
+package directimagestreammigration
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/openshift/library-go/pkg/image/reference"
+	kapi "k8s.io/api/core/v1"
+)
+
+// Suffixes of the tags cosign uses to store signatures and attestations
+// next to the signed image.
+var cosignTagSuffixes = []string{".sig", ".att"}
+
+// Docker config (.dockerconfigjson) secret content.
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// Migrate images that the imagestream references from registries
+// other than the source cluster internal registry.
+func (t *Task) migrateExternalImages() error {
+	externalImages := t.Owner.Spec.ExternalImages
+	if externalImages == nil || !externalImages.Enabled {
+		return nil
+	}
+	imageStream, err := t.Owner.GetImageStream(t.Client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	srcCluster, err := t.Owner.GetSourceCluster(t.Client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	srcInternalRegistry, err := srcCluster.GetInternalRegistryPath(t.Client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	destRegistry, destNamespace, destinationCtx, err := t.getDestination()
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	credentials, err := t.getCredentials(externalImages.CredentialsSecretRef)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	destRepository := path.Join(destRegistry, destNamespace, imageStream.Name)
+	mappings := []migapi.ImageMapping{}
+	for _, tag := range imageStream.Status.Tags {
+		images := []tagImage{}
+		refs := []reference.DockerImageReference{}
+		digests := []string{}
+		// Iterate over items in reverse order so most recently tagged is copied last
+		for i := len(tag.Items) - 1; i >= 0; i-- {
+			item := tag.Items[i]
+			if srcInternalRegistry != "" && strings.HasPrefix(item.DockerImageReference, srcInternalRegistry) {
+				continue
+			}
+			ref, err := reference.Parse(item.DockerImageReference)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+			if !externalImages.IsImageAllowed(ref.DockerClientDefaults().AsRepository().Exact()) {
+				t.Log.Info("[external] Image not allowed, skipped.",
+					"image", item.DockerImageReference)
+				continue
+			}
+			images = append(images, tagImage{
+				src: "docker://" + item.DockerImageReference,
+				ctx: externalRegistrySystemContext(credentials, ref.DockerClientDefaults().Registry),
+			})
+			refs = append(refs, ref)
+			digests = append(digests, item.Image)
+		}
+		if len(images) == 0 {
+			continue
+		}
+		options := &copy.Options{
+			DestinationCtx:   destinationCtx,
+			RemoveSignatures: !externalImages.CopySignatures,
+		}
+		if externalImages.CopySignatures {
+			// Signatures are bound to the manifest digest, copy
+			// manifest lists as-is to preserve it.
+			options.ImageListSelection = copy.CopyAllImages
+		}
+		dest := fmt.Sprintf("docker://%s:%s", destRepository, tag.Tag)
+		err = t.copyTag(tag.Tag, tag.Items[0].Image, images, options, func(options *copy.Options) error {
+			for i, image := range images {
+				imageOptions := *options
+				imageOptions.SourceCtx = image.ctx
+				t.Log.Info("[external] Copying image.",
+					"image", refs[i].Exact(),
+					"destination", destRepository+":"+tag.Tag)
+				err := copyImage(image.src, dest, &imageOptions)
+				if err != nil {
+					return liberr.Wrap(err)
+				}
+				if externalImages.CopySignatures {
+					srcRepository := refs[i].DockerClientDefaults().AsRepository().Exact()
+					err = t.copyCosignArtifacts(srcRepository, destRepository, digests[i], &imageOptions)
+					if err != nil {
+						return liberr.Wrap(err)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		for _, ref := range refs {
+			mappings = append(mappings, migapi.ImageMapping{
+				Source:      ref.AsRepository().Exact(),
+				Destination: destRepository,
+			})
+		}
+	}
+	t.Owner.AddImageMappings(mappings)
+
+	return nil
+}
+
+// Copy the cosign signatures and attestations stored as tags
+// derived from the image digest in the source repository.
+func (t *Task) copyCosignArtifacts(srcRepository, destRepository, digest string, options *copy.Options) error {
+	if digest == "" {
+		return nil
+	}
+	srcRef, err := docker.ParseReference("//" + srcRepository)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	tags, err := docker.GetRepositoryTags(context.TODO(), options.SourceCtx, srcRef)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	found := map[string]bool{}
+	for _, tag := range tags {
+		found[tag] = true
+	}
+	artifactOptions := *options
+	artifactOptions.RemoveSignatures = true
+	for _, suffix := range cosignTagSuffixes {
+		tag := strings.Replace(digest, ":", "-", 1) + suffix
+		if !found[tag] {
+			continue
+		}
+		t.Log.Info("[external] Copying cosign artifact.",
+			"artifact", srcRepository+":"+tag)
+		err = copyImage(
+			fmt.Sprintf("docker://%s:%s", srcRepository, tag),
+			fmt.Sprintf("docker://%s:%s", destRepository, tag),
+			&artifactOptions)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Get the per-registry credentials from the referenced secret.
+func (t *Task) getCredentials(ref *kapi.ObjectReference) (map[string]types.DockerAuthConfig, error) {
+	credentials := map[string]types.DockerAuthConfig{}
+	if ref == nil {
+		return credentials, nil
+	}
+	secret, err := migapi.GetSecret(t.Client, ref)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	if secret == nil {
+		return nil, liberr.Wrap(
+			fmt.Errorf("registry credentials secret %s/%s not found", ref.Namespace, ref.Name))
+	}
+	return parseDockerConfig(secret.Data[kapi.DockerConfigJsonKey])
+}
+
+// Parse .dockerconfigjson content into credentials keyed by registry host.
+func parseDockerConfig(content []byte) (map[string]types.DockerAuthConfig, error) {
+	credentials := map[string]types.DockerAuthConfig{}
+	if len(content) == 0 {
+		return credentials, nil
+	}
+	config := dockerConfigJSON{}
+	err := json.Unmarshal(content, &config)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	for registry, entry := range config.Auths {
+		username, password := entry.Username, entry.Password
+		if entry.Auth != "" {
+			username, password, err = decodeDockerAuth(entry.Auth)
+			if err != nil {
+				return nil, liberr.Wrap(err)
+			}
+		}
+		credentials[normalizeRegistry(registry)] = types.DockerAuthConfig{
+			Username: username,
+			Password: password,
+		}
+	}
+	return credentials, nil
+}
+
+// Decode the base64 `user:password` auth value.
+func decodeDockerAuth(auth string) (string, string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return "", "", err
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return "", "", errors.New("invalid registry auth value")
+	}
+	return parts[0], parts[1], nil
+}
+
+// Strip the scheme and path from a docker config registry key.
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	registry = strings.SplitN(registry, "/", 2)[0]
+	if registry == "index.docker.io" {
+		registry = reference.DockerDefaultRegistry
+	}
+	return registry
+}
+
+// Build the system context used to pull from an external registry.
+func externalRegistrySystemContext(credentials map[string]types.DockerAuthConfig, registry string) *types.SystemContext {
+	ctx := &types.SystemContext{
+		DockerDisableDestSchema1MIMETypes: true,
+	}
+	if auth, found := credentials[registry]; found {
+		ctx.DockerAuthConfig = &auth
+	}
+	return ctx
+}
+
+// Copy an image between two transport references.
+func copyImage(src, dest string, options *copy.Options) error {
+	policyContext, err := signature.NewPolicyContext(
+		&signature.Policy{
+			Default: []signature.PolicyRequirement{
+				signature.NewPRInsecureAcceptAnything(),
+			},
+		})
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	defer policyContext.Destroy()
+	srcRef, err := alltransports.ParseImageName(src)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	destRef, err := alltransports.ParseImageName(dest)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	_, err = copy.Image(context.TODO(), policyContext, destRef, srcRef, options)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package directimagestreammigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// How often the copy progress is reported while copying.
+const (
+	ProgressInterval       = 2 * time.Second
+	ProgressUpdateInterval = 10 * time.Second
+)
+
+// An image to be copied as part of a tag.
+type tagImage struct {
+	// Transport reference of the source image.
+	src string
+	// Context used to inspect the source image.
+	ctx *types.SystemContext
+}
+
+// Tracks the copy progress of an ImageStream tag.
+// The tag status is updated from the copy progress events and
+// periodically persisted while the images are being copied.
+type tagProgressTracker struct {
+	task     *Task
+	tag      string
+	layers   map[digest.Digest]int64
+	copied   map[digest.Digest]int64
+	done     map[digest.Digest]bool
+	baseline migapi.ImageStreamTagProgress
+}
+
+// Copy the images of a tag using the copy function while tracking the progress.
+// The image digest is the digest of the most recent image of the tag.
+// The images are copied in the background: the progress events are applied
+// and the owner is updated by the calling (reconcile) goroutine only.
+func (t *Task) copyTag(tag, imageDigest string, images []tagImage, options *copy.Options, copyFn func(*copy.Options) error) error {
+	tracker := t.newTagProgressTracker(tag, imageDigest, images)
+	progress := make(chan types.ProgressProperties)
+	trackedOptions := *options
+	trackedOptions.Progress = progress
+	trackedOptions.ProgressInterval = ProgressInterval
+	done := make(chan error, 1)
+	go func() {
+		done <- copyFn(&trackedOptions)
+	}()
+	ticker := time.NewTicker(ProgressUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event := <-progress:
+			tracker.update(event)
+		case <-ticker.C:
+			tracker.persist()
+		case err := <-done:
+			tracker.finish(err)
+			return err
+		}
+	}
+}
+
+// Build a tracker for a tag and mark the tag as running.
+// The layers of the source images are inspected to get the totals.
+func (t *Task) newTagProgressTracker(tag, imageDigest string, images []tagImage) *tagProgressTracker {
+	tracker := &tagProgressTracker{
+		task:   t,
+		tag:    tag,
+		layers: map[digest.Digest]int64{},
+		copied: map[digest.Digest]int64{},
+		done:   map[digest.Digest]bool{},
+	}
+	for _, image := range images {
+		layers, err := inspectLayers(image.src, image.ctx)
+		if err != nil {
+			t.Log.Info("[progress] Unable to inspect image layers.",
+				"image", image.src,
+				"error", err.Error())
+			continue
+		}
+		for _, layer := range layers {
+			tracker.layers[layer.Digest] = layer.Size
+		}
+	}
+	progress := t.Owner.GetTagProgress(tag)
+	if progress.StartTimestamp == nil {
+		progress.StartTimestamp = &metav1.Time{Time: time.Now()}
+	}
+	if imageDigest != "" {
+		progress.Digest = imageDigest
+	}
+	progress.Phase = migapi.TagProgressRunning
+	progress.CompletionTimestamp = nil
+	// The images of a tag may be copied in several passes,
+	// the totals of previous passes are kept.
+	tracker.baseline = *progress
+	progress.LayersTotal += len(tracker.layers)
+	for _, size := range tracker.layers {
+		progress.BytesTotal += size
+	}
+	tracker.persist()
+	return tracker
+}
+
+// Update the tag progress with a copy progress event.
+// Only the layers of the inspected images are counted when known.
+func (r *tagProgressTracker) update(event types.ProgressProperties) {
+	blob := event.Artifact.Digest
+	if len(r.layers) > 0 {
+		if _, found := r.layers[blob]; !found {
+			return
+		}
+	}
+	switch event.Event {
+	case types.ProgressEventRead:
+		r.copied[blob] = int64(event.Offset)
+	case types.ProgressEventDone, types.ProgressEventSkipped:
+		r.copied[blob] = event.Artifact.Size
+		r.done[blob] = true
+	}
+	r.apply()
+}
+
+// Mark the tag as completed or failed.
+func (r *tagProgressTracker) finish(err error) {
+	r.apply()
+	progress := r.task.Owner.GetTagProgress(r.tag)
+	progress.CompletionTimestamp = &metav1.Time{Time: time.Now()}
+	if err != nil {
+		progress.Phase = migapi.TagProgressFailed
+		progress.Error = err.Error()
+	} else {
+		progress.Phase = migapi.TagProgressCompleted
+		progress.Error = ""
+	}
+	r.persist()
+}
+
+// Apply the tracked counters to the tag status.
+func (r *tagProgressTracker) apply() {
+	progress := r.task.Owner.GetTagProgress(r.tag)
+	progress.LayersCopied = r.baseline.LayersCopied + len(r.done)
+	progress.BytesCopied = r.baseline.BytesCopied
+	for _, size := range r.copied {
+		progress.BytesCopied += size
+	}
+	// Layers not found while inspecting the images are still reported.
+	if len(r.layers) == 0 {
+		progress.LayersTotal = r.baseline.LayersTotal + len(r.copied)
+		progress.BytesTotal = r.baseline.BytesTotal + progress.BytesCopied - r.baseline.BytesCopied
+	}
+}
+
+// Persist the owner status so the progress is visible while copying.
+// Failing to persist is not fatal, the status is updated again when reconciled.
+func (r *tagProgressTracker) persist() {
+	err := r.task.Client.Update(context.TODO(), r.task.Owner)
+	if err != nil {
+		r.task.Log.Info("[progress] Unable to update tag progress.",
+			"tag", r.tag,
+			"error", err.Error())
+	}
+}
+
+// Get the layers of an image.
+func inspectLayers(src string, ctx *types.SystemContext) ([]types.BlobInfo, error) {
+	ref, err := alltransports.ParseImageName(src)
+	if err != nil {
+		return nil, err
+	}
+	image, err := ref.NewImage(context.TODO(), ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer image.Close()
+	return image.LayerInfos(), nil
+}
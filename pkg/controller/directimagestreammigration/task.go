@@ -138,8 +138,14 @@ func (t *Task) Run() error {
 			return liberr.Wrap(err)
 		}
 	case MigrateImageStream:
+		// Progress is reported for this (possibly repeated) attempt only
+		t.Owner.Status.TagProgress = nil
 		// Migrate internal images in the imagestream
 		err := t.migrateInternalImages()
+		if err == nil {
+			// Migrate images the imagestream references from external registries
+			err = t.migrateExternalImages()
+		}
 		if err != nil {
 			t.fail(MigrationFailed, []string{err.Error()})
 		}
@@ -179,7 +179,10 @@ func (r ReconcileDirectImageStreamMigration) validateDestCluster(imageStreamMigr
 				path.Join(imageStreamMigration.Spec.DestMigClusterRef.Namespace, imageStreamMigration.Spec.DestMigClusterRef.Name)),
 		})
 	}
-	// Exposed registry path
+	// Exposed registry path, not needed when pushing to a registry outside of the cluster.
+	if imageStreamMigration.Spec.DestImageRegistry != nil {
+		return nil
+	}
 	registryPath, err := cluster.GetRegistryPath(r)
 	if err != nil || registryPath == "" {
 		imageStreamMigration.Status.SetCondition(migapi.Condition{
@@ -242,6 +245,10 @@ func (r ReconcileDirectImageStreamMigration) validateImageStream(imageStreamMigr
 // Validate required namespaces on the source cluster.
 // Returns error and the total error conditions set.
 func (r ReconcileDirectImageStreamMigration) validateDestNamespace(imageStreamMigration *migapi.DirectImageStreamMigration) error {
+	// Not needed when pushing to a registry outside of the cluster.
+	if imageStreamMigration.Spec.DestImageRegistry != nil {
+		return nil
+	}
 	cluster, err := imageStreamMigration.GetDestinationCluster(r)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -0,0 +1,327 @@
+package directvolumemigration
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/mig-controller/pkg/transfer"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Transfer agent.
+const (
+	DirectVolumeMigrationAgentServer = "agent-server"
+	DirectVolumeMigrationAgentClient = "agent-client"
+	DirectVolumeMigrationAgentCerts  = "agent-certs"
+	// The transfer agent binary.
+	TransferAgentCommand = "/transfer-agent"
+	// The path of the certificates mounted in the agent pods.
+	TransferAgentCertsPath = "/etc/transfer/certs"
+)
+
+// Build the volume with the certificates of the agent pods.
+func agentCertsVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: DirectVolumeMigrationAgentCerts,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: DirectVolumeMigrationStunnelCerts,
+			},
+		},
+	}
+}
+
+// Build the TLS options of the agent command.
+func (t *Task) agentTLSArgs() []string {
+	tls := t.transferSpec().GetTLS()
+	return []string{
+		"--ca=" + TransferAgentCertsPath + "/ca.crt",
+		"--cert=" + TransferAgentCertsPath + "/tls.crt",
+		"--key=" + TransferAgentCertsPath + "/tls.key",
+		"--tls-min-version=" + tls.MinVersion,
+	}
+}
+
+// Create the transfer agent server pods on the destination cluster.
+// One pod is created per namespace, mounting all PVCs of the namespace.
+// The pods replace the rsync transfer pods and are exposed by the same
+// service, route or ingress.
+func (t *Task) createAgentServerPods() error {
+	destClient, err := t.getDestinationClient()
+	if err != nil {
+		return err
+	}
+	cluster, err := t.Owner.GetDestinationCluster(t.Client)
+	if err != nil {
+		return err
+	}
+	agentImage, err := cluster.GetTransferAgentImage(t.Client)
+	if err != nil {
+		return err
+	}
+	password, err := t.getRsyncPassword()
+	if err != nil {
+		return err
+	}
+	limits, requests, err := getTransferPodResources(
+		t.Client,
+		t.transferSpec().TransferPodResources,
+		TRANSFER_POD_CPU_LIMIT,
+		TRANSFER_POD_MEMORY_LIMIT,
+		TRANSFER_POD_CPU_REQUEST,
+		TRANSFER_POD_MEMORY_REQUEST)
+	if err != nil {
+		return err
+	}
+	isPrivileged, err := isRsyncPrivileged(destClient)
+	if err != nil {
+		return err
+	}
+	tls := t.transferSpec().GetTLS()
+	trueBool := true
+	runAsUser := int64(0)
+
+	pvcMap := t.getPVCNamespaceMap()
+	for ns, vols := range pvcMap {
+		volumes := []corev1.Volume{agentCertsVolume()}
+		volumeMounts := []corev1.VolumeMount{
+			{
+				Name:      DirectVolumeMigrationAgentCerts,
+				MountPath: TransferAgentCertsPath,
+			},
+		}
+		for _, vol := range vols {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      vol.Name,
+				MountPath: fmt.Sprintf("/mnt/%s/%s", ns, vol.Name),
+			})
+			volumes = append(volumes, corev1.Volume{
+				Name: vol.Name,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: vol.Name,
+					},
+				},
+			})
+		}
+		command := []string{
+			TransferAgentCommand,
+			"server",
+			fmt.Sprintf("--listen=:%d", rsyncTransferPort),
+			fmt.Sprintf("--root=/mnt/%s", ns),
+			"--verify-client=" + strconv.FormatBool(*tls.VerifyClient),
+		}
+		command = append(command, t.agentTLSArgs()...)
+
+		dvmLabels := t.buildDVMLabels()
+		dvmLabels["purpose"] = DirectVolumeMigrationRsync
+
+		serverPod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DirectVolumeMigrationRsyncTransfer,
+				Namespace: ns,
+				Labels:    dvmLabels,
+			},
+			Spec: corev1.PodSpec{
+				Volumes: volumes,
+				Containers: []corev1.Container{
+					{
+						Name:    DirectVolumeMigrationAgentServer,
+						Image:   agentImage,
+						Command: command,
+						Env: []corev1.EnvVar{
+							{
+								Name:  transfer.TokenEnv,
+								Value: password,
+							},
+						},
+						Ports: []corev1.ContainerPort{
+							{
+								Name:          DirectVolumeMigrationStunnel,
+								Protocol:      corev1.ProtocolTCP,
+								ContainerPort: int32(rsyncTransferPort),
+							},
+						},
+						VolumeMounts: volumeMounts,
+						SecurityContext: &corev1.SecurityContext{
+							Privileged:             &isPrivileged,
+							RunAsUser:              &runAsUser,
+							ReadOnlyRootFilesystem: &trueBool,
+						},
+						Resources: corev1.ResourceRequirements{
+							Limits:   limits,
+							Requests: requests,
+						},
+					},
+				},
+			},
+		}
+		applyTransferScheduling(&serverPod.Spec, t.transferSpec().Destination)
+		err = destClient.Create(context.TODO(), &serverPod)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Transfer agent server pod already exists on destination", "namespace", serverPod.Namespace)
+		} else if err != nil {
+			return err
+		}
+		t.Log.Info("Transfer agent server pod created", "name", serverPod.Name, "namespace", serverPod.Namespace)
+	}
+	return nil
+}
+
+// Create the transfer agent client pods on the source cluster.
+// One pod is created per PVC, connecting directly to the endpoint
+// of the transport. The pods replace the rsync client pods and are
+// named the same so the progress is reported by the DVMP. The pods
+// are created within the concurrency limits, the others are queued.
+func (t *Task) createAgentClientPods() error {
+	srcClient, err := t.getSourceClient()
+	if err != nil {
+		return err
+	}
+	cluster, err := t.Owner.GetSourceCluster(t.Client)
+	if err != nil {
+		return err
+	}
+	agentImage, err := cluster.GetTransferAgentImage(t.Client)
+	if err != nil {
+		return err
+	}
+	pvcMap, err := t.getfsGroupMapForNamespace()
+	if err != nil {
+		return err
+	}
+	password, err := t.getRsyncPassword()
+	if err != nil {
+		return err
+	}
+	pvcNodeMap, err := t.getPVCNodeNameMap()
+	if err != nil {
+		return err
+	}
+	limits, requests, err := getTransferPodResources(
+		t.Client,
+		t.transferSpec().ClientPodResources,
+		CLIENT_POD_CPU_LIMIT,
+		CLIENT_POD_MEMORY_LIMIT,
+		CLIENT_POD_CPU_REQUEST,
+		CLIENT_POD_MEMORY_REQUEST)
+	if err != nil {
+		return err
+	}
+	isPrivileged, err := isRsyncPrivileged(srcClient)
+	if err != nil {
+		return err
+	}
+	trueBool := true
+	runAsUser := int64(0)
+
+	clientPods := []corev1.Pod{}
+	for ns, vols := range pvcMap {
+		endpoint, err := t.getRsyncEndpoint(ns)
+		if err != nil {
+			return err
+		}
+		for _, vol := range vols {
+			command := []string{
+				TransferAgentCommand,
+				"client",
+				"--endpoint=" + endpoint.String(),
+				fmt.Sprintf("--source=/mnt/%s/%s", ns, vol.name),
+				"--module=" + vol.name,
+				fmt.Sprintf("--progress-address=:%d", transfer.ProgressPort),
+			}
+			if endpoint.SNI != "" {
+				command = append(command, "--server-name="+endpoint.SNI)
+			}
+			if vol.verify {
+				command = append(command, "--checksum")
+			}
+			bandwidth, err := t.getBandwidth()
+			if err != nil {
+				return err
+			}
+			if bandwidth.BwLimit > 0 {
+				command = append(command, fmt.Sprintf("--bwlimit=%d", bandwidth.BwLimit))
+			}
+			command = append(command, t.agentTLSArgs()...)
+			t.Log.Info(fmt.Sprintf("Using transfer agent command [%s]", strings.Join(command, " ")))
+			clientPod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("directvolumemigration-rsync-transfer-%s", vol.name),
+					Namespace: ns,
+					Labels: map[string]string{
+						"app":                   DirectVolumeMigrationRsyncTransfer,
+						"directvolumemigration": DirectVolumeMigrationRsyncClient,
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes: []corev1.Volume{
+						agentCertsVolume(),
+						{
+							Name: vol.name,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: t.getClientClaimName(vol.name),
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    DirectVolumeMigrationAgentClient,
+							Image:   agentImage,
+							Command: command,
+							Env: []corev1.EnvVar{
+								{
+									Name:  transfer.TokenEnv,
+									Value: password,
+								},
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "progress",
+									Protocol:      corev1.ProtocolTCP,
+									ContainerPort: int32(transfer.ProgressPort),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      DirectVolumeMigrationAgentCerts,
+									MountPath: TransferAgentCertsPath,
+								},
+								{
+									Name:      vol.name,
+									MountPath: fmt.Sprintf("/mnt/%s/%s", ns, vol.name),
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged:             &isPrivileged,
+								RunAsUser:              &runAsUser,
+								ReadOnlyRootFilesystem: &trueBool,
+							},
+							Resources: corev1.ResourceRequirements{
+								Limits:   limits,
+								Requests: requests,
+							},
+						},
+					},
+					NodeName: pvcNodeMap[ns+"/"+vol.name],
+					SecurityContext: &corev1.PodSecurityContext{
+						SupplementalGroups: vol.supplementalGroups,
+						FSGroup:            vol.fsGroup,
+						SELinuxOptions:     vol.seLinuxOptions,
+					},
+				},
+			}
+			applyTransferScheduling(&clientPod.Spec, t.transferSpec().Source)
+			clientPods = append(clientPods, clientPod)
+		}
+	}
+	return t.createClientPods(srcClient, clientPods)
+}
@@ -0,0 +1,156 @@
+package directvolumemigration
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTask_next(t *testing.T) {
+	tests := []struct {
+		name     string
+		engine   string
+		cluster  *corev1.ObjectReference
+		snapshot *migapi.TransferSnapshot
+		phase    string
+		want     []string
+	}{
+		{
+			name:  "rsync engine",
+			phase: WaitForRsyncTransferPodsRunning,
+			want: []string{
+				CreateStunnelClientPods,
+				WaitForStunnelClientPodsRunning,
+				EnsureRsyncTransportReachable,
+				CreateRsyncClientPods,
+				WaitForRsyncClientPodsCompleted,
+			},
+		},
+		{
+			name:   "agent engine",
+			engine: migapi.TransferEngineAgent,
+			phase:  CreatePVProgressCRs,
+			want: []string{
+				CreateAgentServerPods,
+				WaitForRsyncTransferPodsRunning,
+				CreateAgentClientPods,
+				WaitForRsyncClientPodsCompleted,
+			},
+		},
+		{
+			name:    "intra-cluster",
+			cluster: &corev1.ObjectReference{Namespace: "openshift-migration", Name: "host"},
+			phase:   DestinationPVCsCreated,
+			want: []string{
+				CreatePVProgressCRs,
+				CreateLocalServerPods,
+				WaitForRsyncTransferPodsRunning,
+				CreateRsyncClientPods,
+				WaitForRsyncClientPodsCompleted,
+			},
+		},
+		{
+			name:    "intra-cluster agent engine",
+			engine:  migapi.TransferEngineAgent,
+			cluster: &corev1.ObjectReference{Namespace: "openshift-migration", Name: "host"},
+			phase:   DestinationPVCsCreated,
+			want: []string{
+				CreatePVProgressCRs,
+				CreateLocalServerPods,
+				WaitForRsyncTransferPodsRunning,
+				CreateRsyncClientPods,
+				WaitForRsyncClientPodsCompleted,
+			},
+		},
+		{
+			name:     "snapshot",
+			snapshot: &migapi.TransferSnapshot{},
+			phase:    CreateStunnelConfig,
+			want: []string{
+				CreateSourceSnapshots,
+				WaitForSourceSnapshotsReady,
+				CreateSnapshotClones,
+				CreatePVProgressCRs,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					Spec: migapi.DirectVolumeMigrationSpec{
+						SrcMigClusterRef:  tt.cluster,
+						DestMigClusterRef: tt.cluster,
+						Transfer:          &migapi.TransferSpec{Engine: tt.engine, Snapshot: tt.snapshot},
+					},
+				},
+				Phase:     tt.phase,
+				Itinerary: VolumeMigration,
+			}
+			got := []string{}
+			for range tt.want {
+				if err := task.next(); err != nil {
+					t.Fatalf("next() error = %v", err)
+				}
+				got = append(got, task.Phase)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_getEngine(t *testing.T) {
+	host := &corev1.ObjectReference{Namespace: "openshift-migration", Name: "host"}
+	remote := &corev1.ObjectReference{Namespace: "openshift-migration", Name: "remote"}
+	tests := []struct {
+		name   string
+		engine string
+		dest   *corev1.ObjectReference
+		want   string
+	}{
+		{name: "default", dest: remote, want: migapi.TransferEngineRsync},
+		{name: "agent", engine: migapi.TransferEngineAgent, dest: remote, want: migapi.TransferEngineAgent},
+		{name: "intra-cluster agent", engine: migapi.TransferEngineAgent, dest: host, want: migapi.TransferEngineRsync},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					Spec: migapi.DirectVolumeMigrationSpec{
+						SrcMigClusterRef:  host,
+						DestMigClusterRef: tt.dest,
+						Transfer:          &migapi.TransferSpec{Engine: tt.engine},
+					},
+				},
+			}
+			if got := task.getEngine(); got != tt.want {
+				t.Errorf("getEngine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_agentTLSArgs(t *testing.T) {
+	task := &Task{
+		Owner: &migapi.DirectVolumeMigration{
+			Spec: migapi.DirectVolumeMigrationSpec{
+				Transfer: &migapi.TransferSpec{
+					TLS: &migapi.TransferTLS{MinVersion: migapi.TLSv13},
+				},
+			},
+		},
+	}
+	want := []string{
+		"--ca=/etc/transfer/certs/ca.crt",
+		"--cert=/etc/transfer/certs/tls.crt",
+		"--key=/etc/transfer/certs/tls.key",
+		"--tls-min-version=TLSv1.3",
+	}
+	if got := task.agentTLSArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("agentTLSArgs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package directvolumemigration
+
+import (
+	"context"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	migsettings "github.com/konveyor/mig-controller/pkg/settings"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Get the active bandwidth limit of the transfers.
+// The rsync bwLimit applies when no window is active.
+func (t *Task) getBandwidth() (migapi.BandwidthStatus, error) {
+	bwLimit := t.mergeRsyncOptions(migsettings.Settings.RsyncOpts).BwLimit
+	if !t.transferSpec().HasBandwidthSchedule() {
+		return migapi.BandwidthStatus{BwLimit: bwLimit}, nil
+	}
+	return activeBandwidth(t.transferSpec().Bandwidth, bwLimit, time.Now())
+}
+
+// Restart the transfers when the active bandwidth limit changed.
+// The active client pods are deleted and queued, then created again with
+// the new limit by startQueuedTransfers(). The transfers resume from the
+// partially transferred files.
+func (t *Task) applyBandwidthSchedule() error {
+	if !t.transferSpec().HasBandwidthSchedule() {
+		return nil
+	}
+	bandwidth, err := t.getBandwidth()
+	if err != nil {
+		return err
+	}
+	status := t.Owner.Status.Bandwidth
+	if status != nil && status.BwLimit != bandwidth.BwLimit {
+		srcClient, err := t.getSourceClient()
+		if err != nil {
+			return err
+		}
+		pods, err := t.listClientPods(srcClient)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			switch pod.Status.Phase {
+			case corev1.PodPending, corev1.PodRunning:
+			default:
+				continue
+			}
+			err = t.restartClientPod(srcClient, pod)
+			if err != nil {
+				return err
+			}
+			t.Log.Info("Client pod restarted with the bandwidth limit",
+				"name", pod.Name,
+				"namespace", pod.Namespace,
+				"bwLimit", bandwidth.BwLimit)
+		}
+	}
+	t.Owner.Status.Bandwidth = &bandwidth
+	return nil
+}
+
+// Queue the transfer of a client pod and delete the pod.
+// The pod is deleted only when the transfer is queued, otherwise
+// it would never be created again by startQueuedTransfers().
+func (t *Task) restartClientPod(client k8sclient.Client, pod *corev1.Pod) error {
+	err := t.setQueuePosition(t.newVolumeTransfer(pod), 1)
+	if err != nil {
+		return err
+	}
+	err = client.Delete(context.TODO(), pod)
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Get the bandwidth limit active at a time.
+// The first window including the time applies, the default limit applies
+// outside the windows. Until is the next window start or end, when the
+// limit may change. The time zone is validated with the transfer spec.
+func activeBandwidth(spec *migapi.TransferBandwidth, bwLimit int, now time.Time) (migapi.BandwidthStatus, error) {
+	location, err := time.LoadLocation(spec.TimeZone)
+	if err != nil {
+		return migapi.BandwidthStatus{}, err
+	}
+	now = now.In(location)
+	active := migapi.BandwidthStatus{BwLimit: bwLimit}
+	found := false
+	var until time.Time
+	for _, window := range spec.Windows {
+		startMinutes, endMinutes, err := window.Minutes()
+		if err != nil || startMinutes == endMinutes {
+			continue
+		}
+		// Windows starting the day before may span midnight.
+		// The next boundary is found within a week.
+		for day := -1; day <= 7; day++ {
+			date := time.Date(now.Year(), now.Month(), now.Day()+day, 0, 0, 0, 0, location)
+			if !window.StartsOn(date.Weekday()) {
+				continue
+			}
+			endDay := date.Day()
+			if endMinutes < startMinutes {
+				endDay++
+			}
+			start := time.Date(date.Year(), date.Month(), date.Day(), startMinutes/60, startMinutes%60, 0, 0, location)
+			end := time.Date(date.Year(), date.Month(), endDay, endMinutes/60, endMinutes%60, 0, 0, location)
+			if !found && !now.Before(start) && now.Before(end) {
+				active.BwLimit = window.BwLimit
+				active.Window = window.String()
+				found = true
+			}
+			for _, boundary := range []time.Time{start, end} {
+				if boundary.After(now) && (until.IsZero() || boundary.Before(until)) {
+					until = boundary
+				}
+			}
+		}
+	}
+	if !until.IsZero() {
+		active.Until = &metav1.Time{Time: until}
+	}
+	return active, nil
+}
@@ -0,0 +1,216 @@
+package directvolumemigration
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_activeBandwidth(t *testing.T) {
+	windows := []migapi.BandwidthWindow{
+		{Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "08:00", End: "18:00", BwLimit: 20480},
+		{Start: "22:00", End: "06:00", BwLimit: -1},
+	}
+	// 2021-03-01 is a Monday.
+	at := func(day, hour int, location *time.Location) time.Time {
+		return time.Date(2021, 3, day, hour, 0, 0, 0, location)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		timeZone string
+		now      time.Time
+		bwLimit  int
+		window   string
+		until    time.Time
+	}{
+		{
+			name:    "business hours",
+			now:     at(1, 10, time.UTC),
+			bwLimit: 20480,
+			window:  "Mon,Tue,Wed,Thu,Fri 08:00-18:00",
+			until:   at(1, 18, time.UTC),
+		},
+		{
+			name:    "outside the windows",
+			now:     at(1, 19, time.UTC),
+			bwLimit: 1000,
+			until:   at(1, 22, time.UTC),
+		},
+		{
+			name:    "spanning midnight",
+			now:     at(2, 3, time.UTC),
+			bwLimit: -1,
+			window:  "22:00-06:00",
+			until:   at(2, 6, time.UTC),
+		},
+		{
+			name:    "weekend",
+			now:     at(6, 10, time.UTC),
+			bwLimit: 1000,
+			until:   at(6, 22, time.UTC),
+		},
+		{
+			name:    "spanning midnight to monday",
+			now:     at(7, 23, time.UTC),
+			bwLimit: -1,
+			window:  "22:00-06:00",
+			until:   at(8, 6, time.UTC),
+		},
+		{
+			name:     "time zone",
+			timeZone: "America/New_York",
+			now:      at(1, 14, time.UTC),
+			bwLimit:  20480,
+			window:   "Mon,Tue,Wed,Thu,Fri 08:00-18:00",
+			until:    at(1, 18, newYork),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &migapi.TransferBandwidth{TimeZone: tt.timeZone, Windows: windows}
+			got, err := activeBandwidth(spec, 1000, tt.now)
+			if err != nil {
+				t.Fatalf("activeBandwidth() error = %v", err)
+			}
+			if got.BwLimit != tt.bwLimit || got.Window != tt.window {
+				t.Errorf("activeBandwidth() = %d %q, want %d %q", got.BwLimit, got.Window, tt.bwLimit, tt.window)
+			}
+			if got.Until == nil || !got.Until.Time.Equal(tt.until) {
+				t.Errorf("activeBandwidth() until = %v, want %v", got.Until, tt.until)
+			}
+		})
+	}
+}
+
+func Test_activeBandwidth_invalidTimeZone(t *testing.T) {
+	spec := &migapi.TransferBandwidth{
+		TimeZone: "Mars/Olympus",
+		Windows:  []migapi.BandwidthWindow{{Start: "08:00", End: "18:00", BwLimit: 20480}},
+	}
+	if _, err := activeBandwidth(spec, 1000, time.Now()); err == nil {
+		t.Errorf("activeBandwidth() error = nil, want an error")
+	}
+}
+
+func TestTransferSpec_InvalidBandwidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		transfer *migapi.TransferSpec
+		want     []string
+	}{
+		{
+			name: "defaults",
+			want: []string{},
+		},
+		{
+			name: "valid",
+			transfer: &migapi.TransferSpec{
+				Bandwidth: &migapi.TransferBandwidth{
+					TimeZone: "Europe/Paris",
+					Windows: []migapi.BandwidthWindow{
+						{Days: []string{"Sat", "Sun"}, Start: "00:00", End: "23:59", BwLimit: -1},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "invalid",
+			transfer: &migapi.TransferSpec{
+				Bandwidth: &migapi.TransferBandwidth{
+					TimeZone: "Mars/Olympus",
+					Windows: []migapi.BandwidthWindow{
+						{Days: []string{"Monday"}, Start: "8am", End: "18:00", BwLimit: -2},
+						{Start: "10:00", End: "10:00"},
+					},
+				},
+			},
+			want: []string{
+				"timeZone: Mars/Olympus not valid",
+				"windows[0]: 8am-18:00 not HH:MM",
+				"windows[0]: day Monday not valid",
+				"windows[0]: bwLimit -2 not valid",
+				"windows[1]: 10:00-10:00 empty",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.transfer.InvalidBandwidth(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvalidBandwidth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_restartClientPod(t *testing.T) {
+	tests := []struct {
+		name        string
+		conflicts   int
+		wantErr     bool
+		wantDeleted bool
+		wantQueued  int
+	}{
+		{name: "restarted", wantDeleted: true, wantQueued: 1},
+		{name: "queue position conflict", conflicts: 100, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dvmpKey := types.NamespacedName{
+				Name:      getMD5Hash("dvm" + "pvc" + "ns"),
+				Namespace: migapi.OpenshiftMigrationNamespace,
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "rsync", Namespace: "ns"},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "pvc",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc"},
+							},
+						},
+					},
+				},
+			}
+			client := &conflictClient{
+				Client: fakeClient(
+					pod.DeepCopy(),
+					&migapi.DirectVolumeMigrationProgress{
+						ObjectMeta: metav1.ObjectMeta{Name: dvmpKey.Name, Namespace: dvmpKey.Namespace},
+					}),
+				conflicts: tt.conflicts,
+			}
+			task := &Task{
+				Client: client,
+				Owner:  &migapi.DirectVolumeMigration{ObjectMeta: metav1.ObjectMeta{Name: "dvm"}},
+			}
+			err := task.restartClientPod(client, pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("restartClientPod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			err = client.Get(context.TODO(), types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &corev1.Pod{})
+			if deleted := k8serror.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("restartClientPod() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			dvmp := migapi.DirectVolumeMigrationProgress{}
+			if err = client.Get(context.TODO(), dvmpKey, &dvmp); err != nil {
+				t.Fatal(err)
+			}
+			if dvmp.Status.QueuePosition != tt.wantQueued {
+				t.Errorf("restartClientPod() position = %v, want %v", dvmp.Status.QueuePosition, tt.wantQueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package directvolumemigration
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// A certificate and its private key.
+// Cert - The parsed certificate.
+// Key - The private key.
+// CertPEM - The PEM encoded certificate (chain).
+// KeyPEM - The PEM encoded private key.
+type certificate struct {
+	Cert    *x509.Certificate
+	Key     crypto.Signer
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// Subject of the generated certificates.
+func certSubject(cn string) pkix.Name {
+	return pkix.Name{
+		CommonName:         cn,
+		Country:            []string{"US"},
+		Province:           []string{"NC"},
+		Locality:           []string{"RDU"},
+		Organization:       []string{"Migration Engineering"},
+		OrganizationalUnit: []string{"Engineering"},
+	}
+}
+
+// Generate a random certificate serial number.
+func certSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// Generate a self-signed CA.
+func generateCA() (*certificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := certSerial()
+	if err != nil {
+		return nil, err
+	}
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               certSubject("openshift.io"),
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return newCertificate(der, key)
+}
+
+// Load the CA from a secret.
+// The secret contains the CA certificate (tls.crt), its key (tls.key)
+// and optionally the root CA (ca.crt) as created by cert-manager.
+func loadCA(secret *corev1.Secret) (*certificate, error) {
+	certPEM, keyPEM := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("secret %s/%s: %s not PEM encoded", secret.Namespace, secret.Name, corev1.TLSCertKey)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("secret %s/%s: %s not a CA", secret.Namespace, secret.Name, corev1.TLSCertKey)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return nil, fmt.Errorf("secret %s/%s: CA expired %s", secret.Namespace, secret.Name, cert.NotAfter)
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s: %s", secret.Namespace, secret.Name, err.Error())
+	}
+	chain := append([]byte{}, certPEM...)
+	if root, found := secret.Data["ca.crt"]; found && !bytes.Equal(root, certPEM) {
+		chain = append(chain, root...)
+	}
+	return &certificate{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: chain,
+		KeyPEM:  keyPEM,
+	}, nil
+}
+
+// Parse a PEM encoded (PKCS1, PKCS8 or EC) private key.
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("private key not PEM encoded")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("private key type not supported")
+}
+
+// Issue a certificate signed by the CA.
+// The certificate does not outlive the CA.
+func (r *certificate) issue(cn string, usage x509.ExtKeyUsage, duration time.Duration) (*certificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := certSerial()
+	if err != nil {
+		return nil, err
+	}
+	notAfter := time.Now().Add(duration)
+	if notAfter.After(r.Cert.NotAfter) {
+		notAfter = r.Cert.NotAfter
+	}
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               certSubject(cn),
+		NotBefore:             time.Now(),
+		NotAfter:              notAfter,
+		ExtKeyUsage:           []x509.ExtKeyUsage{usage},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, r.Cert, &key.PublicKey, r.Key)
+	if err != nil {
+		return nil, err
+	}
+	return newCertificate(der, key)
+}
+
+// Build a certificate with PEM encoding.
+func newCertificate(der []byte, key *rsa.PrivateKey) (*certificate, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	certPEM := new(bytes.Buffer)
+	err = pem.Encode(certPEM, &pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: der,
+	})
+	if err != nil {
+		return nil, err
+	}
+	keyPEM := new(bytes.Buffer)
+	err = pem.Encode(keyPEM, &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &certificate{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: certPEM.Bytes(),
+		KeyPEM:  keyPEM.Bytes(),
+	}, nil
+}
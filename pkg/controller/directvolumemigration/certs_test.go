@@ -0,0 +1,137 @@
+package directvolumemigration
+
+import (
+	"bytes"
+	"crypto/x509"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCertificate_issue(t *testing.T) {
+	generated, err := generateCA()
+	if err != nil {
+		t.Fatalf("generateCA() error = %v", err)
+	}
+	ca, err := loadCA(&corev1.Secret{
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       generated.CertPEM,
+			corev1.TLSPrivateKeyKey: generated.KeyPEM,
+		},
+	})
+	if err != nil {
+		t.Fatalf("loadCA() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.CertPEM)
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth} {
+		cert, err := ca.issue("ns", usage, time.Hour)
+		if err != nil {
+			t.Fatalf("issue() error = %v", err)
+		}
+		_, err = cert.Cert.Verify(x509.VerifyOptions{
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{usage},
+		})
+		if err != nil {
+			t.Errorf("issue() certificate not verified: %v", err)
+		}
+		if cert.Cert.NotAfter.After(time.Now().Add(time.Hour)) {
+			t.Errorf("issue() NotAfter = %v", cert.Cert.NotAfter)
+		}
+	}
+	cert, err := ca.issue("ns", x509.ExtKeyUsageServerAuth, 100*365*24*time.Hour)
+	if err != nil {
+		t.Fatalf("issue() error = %v", err)
+	}
+	if !cert.Cert.NotAfter.Equal(ca.Cert.NotAfter) {
+		t.Errorf("issue() NotAfter = %v, want CA NotAfter %v", cert.Cert.NotAfter, ca.Cert.NotAfter)
+	}
+	leaf := &corev1.Secret{
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       cert.CertPEM,
+			corev1.TLSPrivateKeyKey: cert.KeyPEM,
+		},
+	}
+	if _, err = loadCA(leaf); err == nil {
+		t.Errorf("loadCA() error = nil, want error for a certificate not a CA")
+	}
+}
+
+func TestStunnelDestinationConfigTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config stunnelConfig
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "defaults",
+			config: stunnelConfig{TLSMinVersion: migapi.TLSv12, VerifyClient: true},
+			want: []string{
+				"    sslVersionMin = TLSv1.2\n",
+				"    CAFile = /etc/stunnel/certs/ca.crt\n    verify = 2\n    TIMEOUTclose = 0\n",
+			},
+			absent: []string{"ciphers"},
+		},
+		{
+			name: "ciphers without client verification",
+			config: stunnelConfig{
+				TLSMinVersion: migapi.TLSv13,
+				Ciphers:       "ECDHE+AESGCM",
+				CipherSuites:  "TLS_AES_256_GCM_SHA384",
+			},
+			want: []string{
+				"    sslVersionMin = TLSv1.3\n    ciphers = ECDHE+AESGCM\n    ciphersuites = TLS_AES_256_GCM_SHA384\n",
+			},
+			absent: []string{"verify"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl := template.Must(template.New("config").Parse(stunnelDestinationConfigTemplate))
+			out := bytes.Buffer{}
+			if err := tpl.Execute(&out, tt.config); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("config = %s, want %q", out.String(), want)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(out.String(), absent) {
+					t.Errorf("config = %s, unexpected %q", out.String(), absent)
+				}
+			}
+		})
+	}
+}
+
+func TestTransferSpec_InvalidTLS(t *testing.T) {
+	spec := &migapi.TransferSpec{
+		TLS: &migapi.TransferTLS{
+			MinVersion: "TLSv1.1",
+			Ciphers:    "HIGH\n    verify = 0",
+			Duration:   &metav1.Duration{},
+		},
+	}
+	want := []string{
+		"minVersion: TLSv1.1 not supported",
+		"ciphers: HIGH\n    verify = 0 not valid",
+		"duration: 0s not valid",
+	}
+	if got := spec.InvalidTLS(); !reflect.DeepEqual(got, want) {
+		t.Errorf("InvalidTLS() = %v, want %v", got, want)
+	}
+	var unset *migapi.TransferSpec
+	if got := unset.InvalidTLS(); len(got) != 0 {
+		t.Errorf("InvalidTLS() = %v, want none", got)
+	}
+}
@@ -10,15 +10,22 @@ var phaseDescriptions = map[string]string{
 	DestinationNamespacesCreated:         "Checking if the target namespaces have been created, currently it is non-operational",
 	CreateDestinationPVCs:                "Creating PVCs in the target namespaces",
 	DestinationPVCsCreated:               "Checking whether the created PVCs are bound",
-	CreateRsyncRoute:                     "Creating one route for each namespace for Rsync on the target cluster",
+	CreateRsyncRoute:                     "Creating one route, service or ingress for each namespace for Rsync on the target cluster",
 	CreateRsyncConfig:                    "Creating a config map and secrets on both the source and target clusters for Rsync configuration",
 	CreateStunnelConfig:                  "Creating a config map and secrets for Stunnel to connect to Rsync on the source and target clusters",
+	CreateSourceSnapshots:                "Creating a CSI VolumeSnapshot of each source PVC",
+	WaitForSourceSnapshotsReady:          "Waiting for the source PVC snapshots to be ready to use",
+	CreateSnapshotClones:                 "Restoring the source PVC snapshots to temporary PVCs",
 	CreatePVProgressCRs:                  "Creating a Direct Volume Migration Progress CR to get progress percentage and transfer rate",
 	CreateRsyncTransferPods:              "Creating Rsync daemon pods on the target cluster",
+	CreateAgentServerPods:                "Creating transfer agent server pods on the target cluster",
 	WaitForRsyncTransferPodsRunning:      "Waiting for the Rsync daemon pod to run",
 	CreateStunnelClientPods:              "Creating Stunnel client pods on the source cluster",
 	WaitForStunnelClientPodsRunning:      "Waiting for the Stunnel client pods to run",
+	EnsureRsyncTransportReachable:        "Checking that Rsync on the target cluster can be reached from the source cluster",
 	CreateRsyncClientPods:                "Creating Rsync client pods",
+	CreateAgentClientPods:                "Creating transfer agent client pods on the source cluster",
+	CreateLocalServerPods:                "Creating Rsync daemon pods in the destination namespaces",
 	WaitForRsyncClientPodsCompleted:      "Waiting for the Rsync client pods to be completed",
 	DeleteRsyncResources:                 "Deleting resources created by this migration",
 	WaitForRsyncResourcesTerminated:      "Waiting for resources to terminate",
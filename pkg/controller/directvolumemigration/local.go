@@ -0,0 +1,365 @@
+package directvolumemigration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The port of the rsync daemon reached by the clients within the cluster.
+const localRsyncPort = 873
+
+// The rsync daemon config of intra-cluster transfers.
+// The daemon is reached through a ClusterIP service by the client pods
+// of the source namespaces, without a tunnel. The transfer is protected
+// only by the rsync password, so it must be enabled by the
+// DVM_LOCAL_PLAINTEXT setting.
+const localRsyncConfigTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    purpose: rsync
+data:
+  rsyncd.conf: |
+    syslog facility = local7
+    read only = no
+    list = yes
+    log file = /dev/stdout
+    max verbosity = 4
+    auth users = {{ .SshUser }}
+    secrets file = /etc/rsyncd.secrets
+    uid = root
+    gid = root
+    {{ range $i, $pvc := .PVCList }}
+    [{{ $pvc.Name }}]
+        comment = archive for {{ $pvc.Name }}
+        path = /mnt/{{ $.Namespace }}/{{ $pvc.Name }}
+        use chroot = no
+        munge symlinks = no
+        list = yes
+        auth users = {{ $.SshUser }}
+        secrets file = /etc/rsyncd.secrets
+        read only = false
+   {{ end }}
+`
+
+// Find the PVC to migrate.
+func (t *Task) findPVCToMigrate(namespace, name string) *migapi.PVCToMigrate {
+	for i := range t.Owner.Spec.PersistentVolumeClaims {
+		pvc := &t.Owner.Spec.PersistentVolumeClaims[i]
+		if pvc.Namespace == namespace && pvc.Name == name {
+			return pvc
+		}
+	}
+	return nil
+}
+
+// Get the destination PVCs keyed by destination namespace.
+func (t *Task) getTargetPVCNamespaceMap() map[string][]pvcMapElement {
+	nsMap := map[string][]pvcMapElement{}
+	for i := range t.Owner.Spec.PersistentVolumeClaims {
+		pvc := &t.Owner.Spec.PersistentVolumeClaims[i]
+		ns := pvc.GetTargetNamespace()
+		nsMap[ns] = append(nsMap[ns], pvcMapElement{Name: pvc.GetTargetName(), Verify: pvc.Verify})
+	}
+	return nsMap
+}
+
+// Get the namespaces of the transfer pods on the destination cluster.
+func (t *Task) getServerNamespaces() []string {
+	namespaces := []string{}
+	nsMap := t.getPVCNamespaceMap()
+	if t.Owner.IsIntraCluster() {
+		nsMap = t.getTargetPVCNamespaceMap()
+	}
+	for ns := range nsMap {
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// Get the namespaces of the transfer resources on both clusters.
+func (t *Task) getRsyncNamespaces() []string {
+	found := map[string]bool{}
+	namespaces := []string{}
+	for _, nsMap := range []map[string][]pvcMapElement{t.getPVCNamespaceMap(), t.getTargetPVCNamespaceMap()} {
+		for ns := range nsMap {
+			if !found[ns] {
+				found[ns] = true
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	return namespaces
+}
+
+// Create the rsync daemon pods of intra-cluster transfers.
+// One pod is created per destination namespace, mounting the destination
+// PVCs of the namespace, and exposed by a ClusterIP service.
+func (t *Task) createLocalServerPods() error {
+	client, err := t.getDestinationClient()
+	if err != nil {
+		return err
+	}
+	cluster, err := t.Owner.GetDestinationCluster(t.Client)
+	if err != nil {
+		return err
+	}
+	transferImage, err := cluster.GetRsyncTransferImage(t.Client)
+	if err != nil {
+		return err
+	}
+	password, err := t.getRsyncPassword()
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		password, err = t.createRsyncPassword()
+		if err != nil {
+			return err
+		}
+	}
+	limits, requests, err := getTransferPodResources(
+		t.Client,
+		t.transferSpec().TransferPodResources,
+		TRANSFER_POD_CPU_LIMIT,
+		TRANSFER_POD_MEMORY_LIMIT,
+		TRANSFER_POD_CPU_REQUEST,
+		TRANSFER_POD_MEMORY_REQUEST)
+	if err != nil {
+		return err
+	}
+	isPrivileged, err := isRsyncPrivileged(client)
+	if err != nil {
+		return err
+	}
+	trueBool := true
+	runAsUser := int64(0)
+	mode := int32(0600)
+
+	for ns, vols := range t.getTargetPVCNamespaceMap() {
+		pvcList := []pvc{}
+		for _, vol := range vols {
+			pvcList = append(pvcList, pvc{Name: vol.Name})
+		}
+		var tpl bytes.Buffer
+		temp, err := template.New("config").Parse(localRsyncConfigTemplate)
+		if err != nil {
+			return err
+		}
+		err = temp.Execute(&tpl, rsyncConfig{
+			SshUser:   "root",
+			Namespace: ns,
+			PVCList:   pvcList,
+		})
+		if err != nil {
+			return err
+		}
+		configMap := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      DirectVolumeMigrationRsyncConfig,
+				Labels: map[string]string{
+					"app": DirectVolumeMigrationRsyncTransfer,
+				},
+			},
+		}
+		err = yaml.Unmarshal(tpl.Bytes(), &configMap)
+		if err != nil {
+			return err
+		}
+		err = client.Create(context.TODO(), &configMap)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Configmap already exists", "namespace", configMap.Namespace)
+		} else if err != nil {
+			return err
+		}
+		secret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      DirectVolumeMigrationRsyncCreds,
+				Labels: map[string]string{
+					"app": DirectVolumeMigrationRsyncTransfer,
+				},
+			},
+			Data: map[string][]byte{
+				"credentials": []byte("root:" + password),
+			},
+		}
+		err = client.Create(context.TODO(), &secret)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Secret already exists", "namespace", secret.Namespace)
+		} else if err != nil {
+			return err
+		}
+
+		volumes := []corev1.Volume{
+			{
+				Name: "rsync-creds",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  DirectVolumeMigrationRsyncCreds,
+						DefaultMode: &mode,
+						Items: []corev1.KeyToPath{
+							{
+								Key:  "credentials",
+								Path: "rsyncd.secrets",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "rsyncd-conf",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: DirectVolumeMigrationRsyncConfig,
+						},
+					},
+				},
+			},
+		}
+		volumeMounts := []corev1.VolumeMount{
+			{
+				Name:      "rsyncd-conf",
+				MountPath: "/etc/rsyncd.conf",
+				SubPath:   "rsyncd.conf",
+			},
+			{
+				Name:      "rsync-creds",
+				MountPath: "/etc/rsyncd.secrets",
+				SubPath:   "rsyncd.secrets",
+			},
+		}
+		for _, vol := range vols {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      vol.Name,
+				MountPath: fmt.Sprintf("/mnt/%s/%s", ns, vol.Name),
+			})
+			volumes = append(volumes, corev1.Volume{
+				Name: vol.Name,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: vol.Name,
+					},
+				},
+			})
+		}
+
+		dvmLabels := t.buildDVMLabels()
+		dvmLabels["purpose"] = DirectVolumeMigrationRsync
+
+		serverPod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DirectVolumeMigrationRsyncTransfer,
+				Namespace: ns,
+				Labels:    dvmLabels,
+			},
+			Spec: corev1.PodSpec{
+				Volumes: volumes,
+				Containers: []corev1.Container{
+					{
+						Name:    "rsyncd",
+						Image:   transferImage,
+						Command: []string{"/usr/bin/rsync", "--daemon", "--no-detach", fmt.Sprintf("--port=%d", localRsyncPort), "-vvv"},
+						Ports: []corev1.ContainerPort{
+							{
+								Name:          "rsyncd",
+								Protocol:      corev1.ProtocolTCP,
+								ContainerPort: int32(localRsyncPort),
+							},
+						},
+						VolumeMounts: volumeMounts,
+						SecurityContext: &corev1.SecurityContext{
+							Privileged:             &isPrivileged,
+							RunAsUser:              &runAsUser,
+							ReadOnlyRootFilesystem: &trueBool,
+						},
+						Resources: corev1.ResourceRequirements{
+							Limits:   limits,
+							Requests: requests,
+						},
+					},
+				},
+			},
+		}
+		applyTransferScheduling(&serverPod.Spec, t.transferSpec().Destination)
+		err = client.Create(context.TODO(), &serverPod)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Rsync daemon pod already exists", "namespace", serverPod.Namespace)
+		} else if err != nil {
+			return err
+		}
+		t.Log.Info("Rsync daemon pod created", "name", serverPod.Name, "namespace", serverPod.Namespace)
+
+		svc := corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DirectVolumeMigrationRsyncTransferSvc,
+				Namespace: ns,
+				Labels: map[string]string{
+					"app": DirectVolumeMigrationRsyncTransfer,
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{
+						Name:       "rsyncd",
+						Protocol:   corev1.ProtocolTCP,
+						Port:       int32(localRsyncPort),
+						TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: int32(localRsyncPort)},
+					},
+				},
+				Selector: dvmLabels,
+				Type:     corev1.ServiceTypeClusterIP,
+			},
+		}
+		err = client.Create(context.TODO(), &svc)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Rsync daemon service already exists", "namespace", svc.Namespace)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get the rsync daemon URLs of the volumes keyed by source namespace/name.
+// The clients of cross-cluster transfers reach the daemon through the
+// stunnel service of the source namespace. The clients of intra-cluster
+// transfers reach the daemon of the destination namespace directly.
+func (t *Task) getRsyncDaemonURLs(client k8sclient.Client) (map[string]string, error) {
+	urls := map[string]string{}
+	ips := map[string]string{}
+	for i := range t.Owner.Spec.PersistentVolumeClaims {
+		pvc := &t.Owner.Spec.PersistentVolumeClaims[i]
+		ns, module, port := pvc.Namespace, pvc.Name, ""
+		if t.Owner.IsIntraCluster() {
+			ns, module = pvc.GetTargetNamespace(), pvc.GetTargetName()
+			port = fmt.Sprintf(":%d", localRsyncPort)
+		}
+		ip, found := ips[ns]
+		if !found {
+			svc := corev1.Service{}
+			key := types.NamespacedName{Name: DirectVolumeMigrationRsyncTransferSvc, Namespace: ns}
+			err := client.Get(context.TODO(), key, &svc)
+			if err != nil {
+				return nil, err
+			}
+			ip = svc.Spec.ClusterIP
+			ips[ns] = ip
+		}
+		urls[pvc.Namespace+"/"+pvc.Name] = fmt.Sprintf("rsync://root@%s%s/%s", ip, port, module)
+	}
+	return urls, nil
+}
@@ -0,0 +1,143 @@
+package directvolumemigration
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDirectVolumeMigration_InvalidTargets(t *testing.T) {
+	host := &corev1.ObjectReference{Namespace: "openshift-migration", Name: "host"}
+	remote := &corev1.ObjectReference{Namespace: "openshift-migration", Name: "remote"}
+	tests := []struct {
+		name string
+		dest *corev1.ObjectReference
+		pvcs []migapi.PVCToMigrate
+		want []string
+	}{
+		{
+			name: "across clusters",
+			dest: remote,
+			pvcs: []migapi.PVCToMigrate{
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}},
+			},
+			want: []string{},
+		},
+		{
+			name: "renamed across clusters",
+			dest: remote,
+			pvcs: []migapi.PVCToMigrate{
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}, TargetNamespace: "ns-1"},
+			},
+			want: []string{"ns-0/pvc-0: renamed across clusters"},
+		},
+		{
+			name: "within the cluster",
+			dest: host,
+			pvcs: []migapi.PVCToMigrate{
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}, TargetNamespace: "ns-1"},
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-1"}, TargetName: "pvc-1-gp3"},
+			},
+			want: []string{},
+		},
+		{
+			name: "same as the source",
+			dest: host,
+			pvcs: []migapi.PVCToMigrate{
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}, TargetNamespace: "ns-0"},
+			},
+			want: []string{"ns-0/pvc-0: same as the source"},
+		},
+		{
+			name: "not distinct",
+			dest: host,
+			pvcs: []migapi.PVCToMigrate{
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}, TargetNamespace: "ns-1"},
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-2", Name: "pvc-0"}, TargetNamespace: "ns-1"},
+			},
+			want: []string{"ns-2/pvc-0: ns-1/pvc-0 not distinct"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dvm := &migapi.DirectVolumeMigration{
+				Spec: migapi.DirectVolumeMigrationSpec{
+					SrcMigClusterRef:       host,
+					DestMigClusterRef:      tt.dest,
+					PersistentVolumeClaims: tt.pvcs,
+				},
+			}
+			if got := dvm.InvalidTargets(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvalidTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_getRsyncDaemonURLs(t *testing.T) {
+	host := &corev1.ObjectReference{Namespace: "openshift-migration", Name: "host"}
+	remote := &corev1.ObjectReference{Namespace: "openshift-migration", Name: "remote"}
+	pvcs := []migapi.PVCToMigrate{
+		{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}, TargetNamespace: "ns-1"},
+		{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-1"}, TargetName: "pvc-1-gp3"},
+	}
+	client := fake.NewFakeClient(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-0", Name: DirectVolumeMigrationRsyncTransferSvc},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.1"},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: DirectVolumeMigrationRsyncTransferSvc},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.2"},
+		})
+	tests := []struct {
+		name string
+		dest *corev1.ObjectReference
+		pvcs []migapi.PVCToMigrate
+		want map[string]string
+	}{
+		{
+			name: "across clusters",
+			dest: remote,
+			pvcs: []migapi.PVCToMigrate{
+				{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}},
+			},
+			want: map[string]string{
+				"ns-0/pvc-0": "rsync://root@10.0.0.1/pvc-0",
+			},
+		},
+		{
+			name: "within the cluster",
+			dest: host,
+			pvcs: pvcs,
+			want: map[string]string{
+				"ns-0/pvc-0": "rsync://root@10.0.0.2:873/pvc-0",
+				"ns-0/pvc-1": "rsync://root@10.0.0.1:873/pvc-1-gp3",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					Spec: migapi.DirectVolumeMigrationSpec{
+						SrcMigClusterRef:       host,
+						DestMigClusterRef:      tt.dest,
+						PersistentVolumeClaims: tt.pvcs,
+					},
+				},
+			}
+			got, err := task.getRsyncDaemonURLs(client)
+			if err != nil {
+				t.Fatalf("getRsyncDaemonURLs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getRsyncDaemonURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
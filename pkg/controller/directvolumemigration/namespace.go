@@ -3,6 +3,7 @@ package directvolumemigration
 import (
 	"context"
 	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -21,13 +22,17 @@ func (t *Task) ensureDestinationNamespaces() error {
 	}
 
 	// Get list namespaces to iterate over
-	nsMap := t.getPVCNamespaceMap()
-	for ns, _ := range nsMap {
+	// The destination namespaces are keyed by the source namespaces
+	nsMap := map[string]string{}
+	for _, pvc := range t.Owner.Spec.PersistentVolumeClaims {
+		nsMap[pvc.GetTargetNamespace()] = pvc.Namespace
+	}
+	for ns, srcNamespace := range nsMap {
 		// Get namespace definition from source cluster
 		// This is done to get the needed security context bits
 
 		srcNS := corev1.Namespace{}
-		key := types.NamespacedName{Name: ns}
+		key := types.NamespacedName{Name: srcNamespace}
 		err = srcClient.Get(context.TODO(), key, &srcNS)
 		if err != nil {
 			return err
@@ -41,7 +46,7 @@ func (t *Task) ensureDestinationNamespaces() error {
 			},
 		}
 		err = destClient.Create(context.TODO(), &destNs)
-		if err != nil {
+		if err != nil && !k8serror.IsAlreadyExists(err) {
 			return err
 		}
 	}
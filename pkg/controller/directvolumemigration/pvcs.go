@@ -71,23 +71,38 @@ func (t *Task) createDestinationPVCs() error {
 			pvcLabels = make(map[string]string)
 		}
 
-		if t.MigrationUID != "" && t.PlanResources.MigPlan != nil {
-			pvcLabels[MigratedByMigrationLabel] = t.MigrationUID
-			pvcLabels[MigratedByPlanLabel] = string(t.PlanResources.MigPlan.UID)
-		}
-
 		// Create pvc on destination with same metadata + spec
 		destPVC := corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      pvc.Name,
-				Namespace: pvc.Namespace,
+				Name:      pvc.GetTargetName(),
+				Namespace: pvc.GetTargetNamespace(),
 				Labels:    pvcLabels,
 			},
 			Spec: newSpec,
 		}
+
+		// Apply the transform rule of the plan
+		if plan != nil {
+			storageClass := ""
+			if srcPVC.Spec.StorageClassName != nil {
+				storageClass = *srcPVC.Spec.StorageClassName
+			}
+			if _, rule := plan.FindPVCTransform(pvc.Namespace, storageClass); rule != nil {
+				err = rule.Apply(&destPVC)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if t.MigrationUID != "" && plan != nil {
+			destPVC.Labels[MigratedByMigrationLabel] = t.MigrationUID
+			destPVC.Labels[MigratedByPlanLabel] = string(plan.UID)
+		}
+
 		err = destClient.Create(context.TODO(), &destPVC)
 		if k8serror.IsAlreadyExists(err) {
-			t.Log.Info("PVC already exists on destination", "name", pvc.Name)
+			t.Log.Info("PVC already exists on destination", "name", destPVC.Name, "namespace", destPVC.Namespace)
 		} else if err != nil {
 			return err
 		}
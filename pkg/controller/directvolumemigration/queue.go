@@ -0,0 +1,322 @@
+package directvolumemigration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/compat"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A volume transfer run by a client pod.
+// pod - The client pod.
+// namespace - The namespace of the PVC.
+// pvc - The name of the PVC.
+// node - The source node running the client pod, empty when not known.
+// index - The index of the PVC in the spec.
+// size - The requested size of the PVC.
+// priority - The priority annotated on the PVC.
+type volumeTransfer struct {
+	pod       *corev1.Pod
+	namespace string
+	pvc       string
+	node      string
+	index     int
+	size      int64
+	priority  int
+}
+
+// Create the client pods allowed by the concurrency limits.
+// The other pods are queued and created by later calls as the transfers
+// complete. The queue position is reported in the DVMP of each volume.
+func (t *Task) createClientPods(client compat.Client, pods []corev1.Pod) error {
+	existing, err := t.listClientPods(client)
+	if err != nil {
+		return err
+	}
+	concurrency := t.transferSpec().GetConcurrency()
+	pending := []volumeTransfer{}
+	active := []volumeTransfer{}
+	waiting := []volumeTransfer{}
+	for i := range pods {
+		transfer := t.newVolumeTransfer(&pods[i])
+		found, exists := existing[types.NamespacedName{Namespace: transfer.namespace, Name: transfer.pod.Name}]
+		if !exists {
+			if t.waitingRetry(transfer) {
+				waiting = append(waiting, transfer)
+				continue
+			}
+			if concurrency.Limited() {
+				err = t.setTransferOrder(client, &transfer)
+				if err != nil {
+					return err
+				}
+			}
+			pending = append(pending, transfer)
+			continue
+		}
+		if found.DeletionTimestamp != nil {
+			// Restarted, created again once deleted.
+			continue
+		}
+		switch found.Status.Phase {
+		case corev1.PodPending, corev1.PodRunning:
+			transfer.node = found.Spec.NodeName
+			active = append(active, transfer)
+		}
+		err = t.setQueuePosition(transfer, 0)
+		if err != nil {
+			return err
+		}
+	}
+	start, queued := scheduleTransfers(concurrency, pending, active)
+	queued = append(queued, waiting...)
+	for _, transfer := range start {
+		err = client.Create(context.TODO(), transfer.pod)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Client pod already exists on source", "namespace", transfer.pod.Namespace)
+		} else if err != nil {
+			return err
+		}
+		t.Log.Info("Client pod created", "name", transfer.pod.Name, "namespace", transfer.pod.Namespace)
+		if attempts := t.findVolumeAttempts(transfer.namespace, transfer.pvc); attempts != nil {
+			attempts.RetryAt = nil
+		}
+		err = t.setQueuePosition(transfer, 0)
+		if err != nil {
+			return err
+		}
+	}
+	if len(start) > 0 && t.transferSpec().HasBandwidthSchedule() {
+		bandwidth, err := t.getBandwidth()
+		if err != nil {
+			return err
+		}
+		t.Owner.Status.Bandwidth = &bandwidth
+	}
+	for i, transfer := range queued {
+		t.Log.Info("Client pod queued", "name", transfer.pod.Name, "namespace", transfer.pod.Namespace, "position", i+1)
+		err = t.setQueuePosition(transfer, i+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Build the volume transfer of a client pod.
+func (t *Task) newVolumeTransfer(pod *corev1.Pod) volumeTransfer {
+	transfer := volumeTransfer{
+		pod:       pod,
+		namespace: pod.Namespace,
+		node:      pod.Spec.NodeName,
+	}
+	// The volume is named after the source PVC, the claim
+	// may be a snapshot clone.
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil {
+			transfer.pvc = vol.Name
+			break
+		}
+	}
+	for i, pvc := range t.Owner.Spec.PersistentVolumeClaims {
+		if pvc.Namespace == transfer.namespace && pvc.Name == transfer.pvc {
+			transfer.index = i
+			break
+		}
+	}
+	return transfer
+}
+
+// Set the size and priority of a volume transfer from the source PVC.
+func (t *Task) setTransferOrder(client compat.Client, transfer *volumeTransfer) error {
+	claim := corev1.PersistentVolumeClaim{}
+	err := client.Get(
+		context.TODO(),
+		types.NamespacedName{Namespace: transfer.namespace, Name: transfer.pvc},
+		&claim)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if size, found := claim.Spec.Resources.Requests[corev1.ResourceStorage]; found {
+		transfer.size = size.Value()
+	}
+	if value, found := claim.Annotations[migapi.TransferPriorityAnnotation]; found {
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			t.Log.Info(
+				fmt.Sprintf("Invalid transfer priority %s ignored", value),
+				"namespace", claim.Namespace,
+				"name", claim.Name)
+		} else {
+			transfer.priority = priority
+		}
+	}
+	return nil
+}
+
+// List the client pods on the source cluster.
+func (t *Task) listClientPods(client compat.Client) (map[types.NamespacedName]*corev1.Pod, error) {
+	found := map[types.NamespacedName]*corev1.Pod{}
+	selector := labels.SelectorFromSet(map[string]string{
+		"directvolumemigration": DirectVolumeMigrationRsyncClient,
+	})
+	for ns := range t.getPVCNamespaceMap() {
+		list := corev1.PodList{}
+		err := client.List(
+			context.TODO(),
+			&k8sclient.ListOptions{
+				Namespace:     ns,
+				LabelSelector: selector,
+			},
+			&list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			pod := &list.Items[i]
+			found[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = pod
+		}
+	}
+	return found, nil
+}
+
+// Set the queue position reported by the DVMP of a volume.
+// The DVMP is updated by the DVMP controller as well, the update is
+// retried on conflicts.
+func (t *Task) setQueuePosition(transfer volumeTransfer, position int) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dvmp := migapi.DirectVolumeMigrationProgress{}
+		err := t.Client.Get(
+			context.TODO(),
+			types.NamespacedName{
+				Name:      getMD5Hash(t.Owner.Name + transfer.pvc + transfer.namespace),
+				Namespace: migapi.OpenshiftMigrationNamespace,
+			},
+			&dvmp)
+		if err != nil {
+			if k8serror.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if dvmp.Status.QueuePosition == position {
+			return nil
+		}
+		dvmp.Status.QueuePosition = position
+		return t.Client.Update(context.TODO(), &dvmp)
+	})
+}
+
+// Get whether any volume transfer is queued.
+func (t *Task) hasQueuedTransfers() (bool, error) {
+	for ns, vols := range t.getPVCNamespaceMap() {
+		for _, vol := range vols {
+			dvmp := migapi.DirectVolumeMigrationProgress{}
+			err := t.Client.Get(
+				context.TODO(),
+				types.NamespacedName{
+					Name:      getMD5Hash(t.Owner.Name + vol.Name + ns),
+					Namespace: migapi.OpenshiftMigrationNamespace,
+				},
+				&dvmp)
+			if err != nil {
+				if k8serror.IsNotFound(err) {
+					continue
+				}
+				return false, err
+			}
+			if dvmp.Status.QueuePosition > 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Start the queued volume transfers allowed by the concurrency limits.
+// The transfers restarted at a bandwidth window boundary and the
+// failed transfers retried are queued as well.
+func (t *Task) startQueuedTransfers() error {
+	if !t.transferSpec().GetConcurrency().Limited() &&
+		!t.transferSpec().HasBandwidthSchedule() &&
+		!t.retryEnabled() {
+		return nil
+	}
+	queued, err := t.hasQueuedTransfers()
+	if err != nil {
+		return err
+	}
+	if !queued {
+		return nil
+	}
+	if t.getEngine() == migapi.TransferEngineAgent {
+		return t.createAgentClientPods()
+	}
+	return t.createRsyncClientPods()
+}
+
+// Schedule the pending volume transfers.
+// The pending transfers are sorted by the order of the concurrency spec,
+// then started while the active and started transfers are within the limits.
+// Returns the transfers to start and the transfers queued, in queue order.
+func scheduleTransfers(
+	concurrency migapi.TransferConcurrency,
+	pending []volumeTransfer,
+	active []volumeTransfer) ([]volumeTransfer, []volumeTransfer) {
+	sorted := make([]volumeTransfer, len(pending))
+	copy(sorted, pending)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].index < sorted[j].index
+	})
+	switch concurrency.Order {
+	case migapi.TransferOrderSmallestFirst:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].size < sorted[j].size
+		})
+	case migapi.TransferOrderLargestFirst:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].size > sorted[j].size
+		})
+	case migapi.TransferOrderPriority:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].priority > sorted[j].priority
+		})
+	}
+	total := len(active)
+	perNode := map[string]int{}
+	perNamespace := map[string]int{}
+	for _, transfer := range active {
+		perNode[transfer.node]++
+		perNamespace[transfer.namespace]++
+	}
+	start := []volumeTransfer{}
+	queued := []volumeTransfer{}
+	for _, transfer := range sorted {
+		limited := (concurrency.MaxTransfers > 0 && total >= concurrency.MaxTransfers) ||
+			(concurrency.MaxTransfersPerNamespace > 0 &&
+				perNamespace[transfer.namespace] >= concurrency.MaxTransfersPerNamespace) ||
+			(concurrency.MaxTransfersPerNode > 0 && transfer.node != "" &&
+				perNode[transfer.node] >= concurrency.MaxTransfersPerNode)
+		if limited {
+			queued = append(queued, transfer)
+			continue
+		}
+		start = append(start, transfer)
+		total++
+		perNode[transfer.node]++
+		perNamespace[transfer.namespace]++
+	}
+	return start, queued
+}
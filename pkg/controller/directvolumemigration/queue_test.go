@@ -0,0 +1,180 @@
+package directvolumemigration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_scheduleTransfers(t *testing.T) {
+	pending := []volumeTransfer{
+		{namespace: "ns1", pvc: "a", node: "n1", index: 0, size: 30, priority: 1},
+		{namespace: "ns1", pvc: "b", node: "n1", index: 1, size: 10, priority: 5},
+		{namespace: "ns2", pvc: "c", node: "n2", index: 2, size: 20},
+		{namespace: "ns2", pvc: "d", index: 3, size: 40, priority: 3},
+	}
+	tests := []struct {
+		name        string
+		concurrency migapi.TransferConcurrency
+		active      []volumeTransfer
+		wantStart   []string
+		wantQueued  []string
+	}{
+		{
+			name:        "unlimited",
+			concurrency: migapi.TransferConcurrency{Order: migapi.TransferOrderSpec},
+			wantStart:   []string{"a", "b", "c", "d"},
+			wantQueued:  []string{},
+		},
+		{
+			name:        "max transfers smallest first",
+			concurrency: migapi.TransferConcurrency{MaxTransfers: 2, Order: migapi.TransferOrderSmallestFirst},
+			wantStart:   []string{"b", "c"},
+			wantQueued:  []string{"a", "d"},
+		},
+		{
+			name:        "max transfers largest first with active transfer",
+			concurrency: migapi.TransferConcurrency{MaxTransfers: 2, Order: migapi.TransferOrderLargestFirst},
+			active:      []volumeTransfer{{namespace: "ns3", pvc: "x", node: "n3"}},
+			wantStart:   []string{"d"},
+			wantQueued:  []string{"a", "c", "b"},
+		},
+		{
+			name:        "per node priority",
+			concurrency: migapi.TransferConcurrency{MaxTransfersPerNode: 1, Order: migapi.TransferOrderPriority},
+			wantStart:   []string{"b", "d", "c"},
+			wantQueued:  []string{"a"},
+		},
+		{
+			name:        "per namespace",
+			concurrency: migapi.TransferConcurrency{MaxTransfersPerNamespace: 1, Order: migapi.TransferOrderSpec},
+			active:      []volumeTransfer{{namespace: "ns2", pvc: "x"}},
+			wantStart:   []string{"a"},
+			wantQueued:  []string{"b", "c", "d"},
+		},
+	}
+	names := func(transfers []volumeTransfer) []string {
+		list := []string{}
+		for _, transfer := range transfers {
+			list = append(list, transfer.pvc)
+		}
+		return list
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, queued := scheduleTransfers(tt.concurrency, pending, tt.active)
+			if !reflect.DeepEqual(names(start), tt.wantStart) {
+				t.Errorf("scheduleTransfers() start = %v, want %v", names(start), tt.wantStart)
+			}
+			if !reflect.DeepEqual(names(queued), tt.wantQueued) {
+				t.Errorf("scheduleTransfers() queued = %v, want %v", names(queued), tt.wantQueued)
+			}
+		})
+	}
+}
+
+func TestTransferSpec_InvalidConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		transfer *migapi.TransferSpec
+		want     []string
+	}{
+		{
+			name: "defaults",
+			want: []string{},
+		},
+		{
+			name: "invalid",
+			transfer: &migapi.TransferSpec{
+				Concurrency: &migapi.TransferConcurrency{
+					MaxTransfers:        -1,
+					MaxTransfersPerNode: 2,
+					Order:               "Random",
+				},
+			},
+			want: []string{"maxTransfers: -1 not valid", "order: Random not supported"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.transfer.InvalidConcurrency(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvalidConcurrency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Build a fake client with the migration API registered.
+func fakeClient(objs ...runtime.Object) k8sclient.Client {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = migapi.AddToScheme(s)
+	return fake.NewFakeClientWithScheme(s, objs...)
+}
+
+// A client failing the first updates with a conflict.
+type conflictClient struct {
+	k8sclient.Client
+	conflicts int
+}
+
+func (c *conflictClient) Update(ctx context.Context, obj runtime.Object) error {
+	if c.conflicts > 0 {
+		c.conflicts--
+		return k8serror.NewConflict(schema.GroupResource{}, "", errors.New("object modified"))
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func TestTask_setQueuePosition(t *testing.T) {
+	tests := []struct {
+		name      string
+		conflicts int
+		want      int
+		wantErr   bool
+	}{
+		{name: "updated", want: 2},
+		{name: "retried on conflict", conflicts: 2, want: 2},
+		{name: "conflicts exhausted", conflicts: 100, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := types.NamespacedName{
+				Name:      getMD5Hash("dvm" + "pvc" + "ns"),
+				Namespace: migapi.OpenshiftMigrationNamespace,
+			}
+			client := &conflictClient{
+				Client: fakeClient(&migapi.DirectVolumeMigrationProgress{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				}),
+				conflicts: tt.conflicts,
+			}
+			task := &Task{
+				Client: client,
+				Owner:  &migapi.DirectVolumeMigration{ObjectMeta: metav1.ObjectMeta{Name: "dvm"}},
+			}
+			err := task.setQueuePosition(volumeTransfer{namespace: "ns", pvc: "pvc"}, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setQueuePosition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			dvmp := migapi.DirectVolumeMigrationProgress{}
+			if err = client.Get(context.TODO(), key, &dvmp); err != nil {
+				t.Fatal(err)
+			}
+			if dvmp.Status.QueuePosition != tt.want {
+				t.Errorf("setQueuePosition() position = %v, want %v", dvmp.Status.QueuePosition, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package directvolumemigration
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Transfer failure reasons.
+const (
+	PartialTransfer = "PartialTransfer"
+	VanishedFiles   = "VanishedFiles"
+	TransferTimeout = "Timeout"
+	ConnectionReset = "ConnectionReset"
+	NoRouteToHost   = "NoRouteToHost"
+	ClientKilled    = "Killed"
+	TransferError   = "Error"
+)
+
+// Retryable rsync exit codes.
+var rsyncExitReasons = map[int32]string{
+	10:  ConnectionReset, // error in socket I/O
+	12:  ConnectionReset, // error in rsync protocol data stream
+	20:  ClientKilled,    // received SIGUSR1 or SIGINT
+	23:  PartialTransfer, // partial transfer due to error
+	24:  VanishedFiles,   // partial transfer due to vanished source files
+	30:  TransferTimeout, // timeout in data send/receive
+	35:  TransferTimeout, // timeout waiting for daemon connection
+	137: ClientKilled,    // SIGKILL, e.g. OOM killed
+	143: ClientKilled,    // SIGTERM
+}
+
+// Retryable errors found in the termination message.
+var messageReasons = []struct {
+	text   string
+	reason string
+}{
+	{text: "no route to host", reason: NoRouteToHost},
+	{text: "connection reset", reason: ConnectionReset},
+	{text: "connection refused", reason: ConnectionReset},
+	{text: "broken pipe", reason: ConnectionReset},
+	{text: "unexpected eof", reason: ConnectionReset},
+	{text: "timeout", reason: TransferTimeout},
+	{text: "timed out", reason: TransferTimeout},
+}
+
+// Classify the failure of a client container.
+// Returns the reason and whether the failure is transient.
+func classifyTransferFailure(exitCode *int32, message string) (string, bool) {
+	if exitCode != nil {
+		if reason, found := rsyncExitReasons[*exitCode]; found {
+			return reason, true
+		}
+	}
+	lower := strings.ToLower(message)
+	for _, m := range messageReasons {
+		if strings.Contains(lower, m.text) {
+			return m.reason, true
+		}
+	}
+	return TransferError, false
+}
+
+// Get whether transfers failed with a transient error are retried.
+func (t *Task) retryEnabled() bool {
+	return t.transferSpec().GetRetry().MaxAttempts > 1
+}
+
+// Find the attempts of a volume transfer.
+func (t *Task) findVolumeAttempts(namespace, name string) *migapi.VolumeAttempts {
+	for _, attempts := range t.Owner.Status.VolumeAttempts {
+		if attempts.Namespace == namespace && attempts.Name == name {
+			return attempts
+		}
+	}
+	return nil
+}
+
+// Get whether a volume transfer waits for its retry delay.
+func (t *Task) waitingRetry(transfer volumeTransfer) bool {
+	attempts := t.findVolumeAttempts(transfer.namespace, transfer.pvc)
+	return attempts != nil && attempts.RetryAt != nil && attempts.RetryAt.After(time.Now())
+}
+
+// Retry the failed volume transfers.
+// The failure of each failed client pod is recorded in the attempts of the
+// volume. A transient failure within the max attempts is retried: the pod is
+// deleted and the transfer queued until the retry delay elapsed, then created
+// again by startQueuedTransfers(). Other failed pods are kept and reported.
+func (t *Task) retryFailedTransfers() error {
+	if !t.retryEnabled() {
+		return nil
+	}
+	retry := t.transferSpec().GetRetry()
+	srcClient, err := t.getSourceClient()
+	if err != nil {
+		return err
+	}
+	pods, err := t.listClientPods(srcClient)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		transfer := t.newVolumeTransfer(pod)
+		attempts := t.findVolumeAttempts(transfer.namespace, transfer.pvc)
+		if attempts == nil {
+			attempts = &migapi.VolumeAttempts{
+				ObjectReference: &corev1.ObjectReference{
+					Namespace: transfer.namespace,
+					Name:      transfer.pvc,
+				},
+			}
+			t.Owner.Status.VolumeAttempts = append(t.Owner.Status.VolumeAttempts, attempts)
+		}
+		if n := len(attempts.Attempts); n > 0 && attempts.Attempts[n-1].Started.Equal(&pod.CreationTimestamp) {
+			// Recorded, not retried.
+			continue
+		}
+		attempt := newTransferAttempt(pod, now)
+		if !attempt.Retryable || len(attempts.Attempts)+1 >= retry.MaxAttempts {
+			attempts.Attempts = append(attempts.Attempts, attempt)
+			attempts.RetryAt = nil
+			t.Log.Info("Client pod failed, not retried",
+				"name", pod.Name,
+				"namespace", pod.Namespace,
+				"reason", attempt.Reason,
+				"attempts", len(attempts.Attempts))
+			continue
+		}
+		err = t.resetTransferProgress(transfer)
+		if err != nil {
+			if k8serror.IsConflict(err) {
+				// Retried on the next poll.
+				continue
+			}
+			return err
+		}
+		attempts.Attempts = append(attempts.Attempts, attempt)
+		attempts.RetryAt = &metav1.Time{Time: now.Add(retry.Delay(len(attempts.Attempts)))}
+		err = srcClient.Delete(context.TODO(), pod)
+		if err != nil && !k8serror.IsNotFound(err) {
+			return err
+		}
+		t.Log.Info("Client pod failed, retrying",
+			"name", pod.Name,
+			"namespace", pod.Namespace,
+			"reason", attempt.Reason,
+			"retryAt", attempts.RetryAt.Time)
+	}
+	return nil
+}
+
+// Reset the progress reported by the DVMP of a retried volume transfer
+// so the failure of the previous attempt is not reported. The transfer
+// is queued until created again.
+func (t *Task) resetTransferProgress(transfer volumeTransfer) error {
+	dvmp := migapi.DirectVolumeMigrationProgress{}
+	err := t.Client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      getMD5Hash(t.Owner.Name + transfer.pvc + transfer.namespace),
+			Namespace: migapi.OpenshiftMigrationNamespace,
+		},
+		&dvmp)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	dvmp.Status.PodPhase = ""
+	dvmp.Status.ExitCode = nil
+	dvmp.Status.ContainerElapsedTime = nil
+	dvmp.Status.QueuePosition = 1
+	return t.Client.Update(context.TODO(), &dvmp)
+}
+
+// Build the attempt of a failed client pod.
+func newTransferAttempt(pod *corev1.Pod, now time.Time) migapi.TransferAttempt {
+	attempt := migapi.TransferAttempt{
+		Started: pod.CreationTimestamp,
+		Failed:  metav1.Time{Time: now},
+		Message: pod.Status.Message,
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+		exitCode := terminated.ExitCode
+		attempt.ExitCode = &exitCode
+		attempt.Message = terminated.Message
+		if terminated.Reason == "OOMKilled" {
+			attempt.Message = terminated.Reason
+		}
+		break
+	}
+	attempt.Reason, attempt.Retryable = classifyTransferFailure(attempt.ExitCode, attempt.Message)
+	return attempt
+}
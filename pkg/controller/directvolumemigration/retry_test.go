@@ -0,0 +1,107 @@
+package directvolumemigration
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_classifyTransferFailure(t *testing.T) {
+	code := func(c int32) *int32 { return &c }
+	tests := []struct {
+		name      string
+		exitCode  *int32
+		message   string
+		reason    string
+		retryable bool
+	}{
+		{name: "partial transfer", exitCode: code(23), reason: PartialTransfer, retryable: true},
+		{name: "vanished files", exitCode: code(24), reason: VanishedFiles, retryable: true},
+		{name: "timeout", exitCode: code(30), reason: TransferTimeout, retryable: true},
+		{name: "socket error", exitCode: code(10), reason: ConnectionReset, retryable: true},
+		{name: "oom killed", exitCode: code(137), message: "OOMKilled", reason: ClientKilled, retryable: true},
+		{name: "no route", exitCode: code(1), message: "dial tcp: connect: No route to host", reason: NoRouteToHost, retryable: true},
+		{name: "agent connection reset", exitCode: code(1), message: "read: connection reset by peer", reason: ConnectionReset, retryable: true},
+		{name: "syntax", exitCode: code(1), message: "rsync: unknown option", reason: TransferError},
+		{name: "file i/o", exitCode: code(11), message: "No space left on device", reason: TransferError},
+		{name: "evicted", message: "The node was low on resource: ephemeral-storage.", reason: TransferError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, retryable := classifyTransferFailure(tt.exitCode, tt.message)
+			if reason != tt.reason || retryable != tt.retryable {
+				t.Errorf("classifyTransferFailure() = %s %v, want %s %v", reason, retryable, tt.reason, tt.retryable)
+			}
+		})
+	}
+}
+
+func Test_newTransferAttempt(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 30,
+							Message:  "rsync error: timeout in data send/receive (code 30)",
+						},
+					},
+				},
+			},
+		},
+	}
+	now := time.Now()
+	got := newTransferAttempt(pod, now)
+	if !got.Started.Equal(&created) || !got.Failed.Time.Equal(now) || got.ExitCode == nil || *got.ExitCode != 30 ||
+		got.Reason != TransferTimeout || !got.Retryable || got.Message != pod.Status.ContainerStatuses[0].State.Terminated.Message {
+		t.Errorf("newTransferAttempt() = %+v", got)
+	}
+}
+
+func TestTransferRetry_Delay(t *testing.T) {
+	retry := (&migapi.TransferSpec{}).GetRetry()
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, delay := range want {
+		if got := retry.Delay(i + 1); got != delay {
+			t.Errorf("Delay(%d) = %s, want %s", i+1, got, delay)
+		}
+	}
+}
+
+func TestTransferSpec_InvalidRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		transfer *migapi.TransferSpec
+		want     []string
+	}{
+		{
+			name: "defaults",
+			want: []string{},
+		},
+		{
+			name: "invalid",
+			transfer: &migapi.TransferSpec{
+				Retry: &migapi.TransferRetry{
+					MaxAttempts: -1,
+					Backoff:     &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			want: []string{"maxAttempts: -1 not valid", "maxBackoff: 10m0s less than backoff"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.transfer.InvalidRetry(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvalidRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
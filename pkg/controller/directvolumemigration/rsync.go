@@ -9,7 +9,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	random "math/rand"
-	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
@@ -102,12 +101,11 @@ func (t *Task) areRsyncTransferPodsRunning() (bool, error) {
 		return false, err
 	}
 
-	pvcMap := t.getPVCNamespaceMap()
 	dvmLabels := t.buildDVMLabels()
 	dvmLabels["purpose"] = DirectVolumeMigrationRsync
 	selector := labels.SelectorFromSet(dvmLabels)
 
-	for ns, _ := range pvcMap {
+	for _, ns := range t.getServerNamespaces() {
 		pods := corev1.PodList{}
 		err = destClient.List(
 			context.TODO(),
@@ -307,31 +305,9 @@ func (t *Task) createRsyncTransferRoute() error {
 		return err
 	}
 	pvcMap := t.getPVCNamespaceMap()
-	dvmLabels := t.buildDVMLabels()
-	dvmLabels["purpose"] = DirectVolumeMigrationRsync
 
 	for ns, _ := range pvcMap {
-		svc := corev1.Service{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      DirectVolumeMigrationRsyncTransferSvc,
-				Namespace: ns,
-				Labels: map[string]string{
-					"app": DirectVolumeMigrationRsyncTransfer,
-				},
-			},
-			Spec: corev1.ServiceSpec{
-				Ports: []corev1.ServicePort{
-					{
-						Name:       DirectVolumeMigrationStunnel,
-						Protocol:   corev1.ProtocolTCP,
-						Port:       int32(2222),
-						TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: 2222},
-					},
-				},
-				Selector: dvmLabels,
-				Type:     corev1.ServiceTypeClusterIP,
-			},
-		}
+		svc := t.buildRsyncTransferSvc(ns)
 		err = destClient.Create(context.TODO(), &svc)
 		if k8serror.IsAlreadyExists(err) {
 			t.Log.Info("Rsync transfer svc already exists on destination", "namespace", ns)
@@ -419,7 +395,13 @@ func (t *Task) createRsyncTransferPods() error {
 	if err != nil {
 		return err
 	}
-	limits, requests, err := getPodResourceLists(t.Client, TRANSFER_POD_CPU_LIMIT, TRANSFER_POD_MEMORY_LIMIT, TRANSFER_POD_CPU_REQUEST, TRANSFER_POD_MEMORY_REQUEST)
+	limits, requests, err := getTransferPodResources(
+		t.Client,
+		t.transferSpec().TransferPodResources,
+		TRANSFER_POD_CPU_LIMIT,
+		TRANSFER_POD_MEMORY_LIMIT,
+		TRANSFER_POD_CPU_REQUEST,
+		TRANSFER_POD_MEMORY_REQUEST)
 	if err != nil {
 		return err
 	}
@@ -611,6 +593,7 @@ func (t *Task) createRsyncTransferPods() error {
 				},
 			},
 		}
+		applyTransferScheduling(&transferPod.Spec, t.transferSpec().Destination)
 		err = destClient.Create(context.TODO(), &transferPod)
 		if k8serror.IsAlreadyExists(err) {
 			t.Log.Info("Rsync transfer pod already exists on destination", "namespace", transferPod.Namespace)
@@ -796,8 +779,13 @@ func (t *Task) deleteRsyncPassword() error {
 }
 
 //Returns a map of PVCNamespacedName to the pod.NodeName
+// The snapshot clones are not mounted by the pods and have no node affinity,
+// the client pods are scheduled on any node.
 func (t *Task) getPVCNodeNameMap() (map[string]string, error) {
 	nodeNameMap := map[string]string{}
+	if t.transferSpec().HasSnapshot() {
+		return nodeNameMap, nil
+	}
 	pvcMap := t.getPVCNamespaceMap()
 
 	srcClient, err := t.getSourceClient()
@@ -832,7 +820,7 @@ func (t *Task) getPVCNodeNameMap() (map[string]string, error) {
 // only returns options identified as valid
 func (t *Task) filterRsyncExtraOptions(options []string) (validatedOptions []string) {
 	for _, opt := range options {
-		if valid, _ := regexp.Match(`^\-{1,2}[\w-]+?\w$`, []byte(opt)); valid {
+		if migapi.RsyncExtraOptionRegexp.MatchString(opt) {
 			validatedOptions = append(validatedOptions, opt)
 		} else {
 			t.Log.Info(fmt.Sprintf("Invalid Rsync extra option passed: %s", opt))
@@ -842,7 +830,8 @@ func (t *Task) filterRsyncExtraOptions(options []string) (validatedOptions []str
 }
 
 // generates Rsync options based on custom options provided by the user in MigrationController CR
-func (t *Task) getRsyncOptions() []string {
+// overridden by the options set in the transfer spec
+func (t *Task) getRsyncOptions() ([]string, error) {
 	var rsyncOpts []string
 	defaultInfoOpts := "COPY2,DEL2,REMOVE2,SKIP2,FLIST2,PROGRESS2,STATS2"
 	defaultExtraOpts := []string{
@@ -850,7 +839,17 @@ func (t *Task) getRsyncOptions() []string {
 		"--port", "2222",
 		"--log-file", "/dev/stdout",
 	}
-	rsyncOptions := migsettings.Settings.RsyncOpts
+	rsyncOptions := t.mergeRsyncOptions(migsettings.Settings.RsyncOpts)
+	if t.transferSpec().HasBandwidthSchedule() {
+		// The transfers restarted at the window boundaries
+		// resume from the partially transferred files.
+		bandwidth, err := t.getBandwidth()
+		if err != nil {
+			return nil, err
+		}
+		rsyncOptions.BwLimit = bandwidth.BwLimit
+		rsyncOptions.Partial = true
+	}
 	if rsyncOptions.BwLimit != -1 {
 		rsyncOpts = append(rsyncOpts,
 			fmt.Sprintf("--bwlimit=%d", rsyncOptions.BwLimit))
@@ -869,7 +868,7 @@ func (t *Task) getRsyncOptions() []string {
 	if rsyncOptions.Partial {
 		rsyncOpts = append(rsyncOpts, "--partial")
 	}
-	if valid, _ := regexp.Match(`^\w[\w,]*?\w$`, []byte(rsyncOptions.Info)); valid {
+	if migapi.RsyncInfoRegexp.MatchString(rsyncOptions.Info) {
 		rsyncOpts = append(rsyncOpts,
 			fmt.Sprintf("--info=%s", rsyncOptions.Info))
 	} else {
@@ -879,7 +878,37 @@ func (t *Task) getRsyncOptions() []string {
 	rsyncOpts = append(rsyncOpts, defaultExtraOpts...)
 	rsyncOpts = append(rsyncOpts,
 		t.filterRsyncExtraOptions(rsyncOptions.Extras)...)
-	return rsyncOpts
+	return rsyncOpts, nil
+}
+
+// Override the Rsync options with the options set in the transfer spec.
+// The extra options are appended.
+func (t *Task) mergeRsyncOptions(rsyncOptions migsettings.RsyncOpts) migsettings.RsyncOpts {
+	spec := t.transferSpec().Rsync
+	if spec == nil {
+		return rsyncOptions
+	}
+	if spec.BwLimit != nil {
+		rsyncOptions.BwLimit = *spec.BwLimit
+	}
+	if spec.Archive != nil {
+		rsyncOptions.Archive = *spec.Archive
+	}
+	if spec.Partial != nil {
+		rsyncOptions.Partial = *spec.Partial
+	}
+	if spec.Delete != nil {
+		rsyncOptions.Delete = *spec.Delete
+	}
+	if spec.HardLinks != nil {
+		rsyncOptions.HardLinks = *spec.HardLinks
+	}
+	if spec.Info != "" {
+		rsyncOptions.Info = spec.Info
+	}
+	extras := append([]string{}, rsyncOptions.Extras...)
+	rsyncOptions.Extras = append(extras, spec.Extras...)
+	return rsyncOptions
 }
 
 type PVCWithSecurityContext struct {
@@ -998,23 +1027,26 @@ func (t *Task) createRsyncClientPods() error {
 		return err
 	}
 
-	limits, requests, err := getPodResourceLists(t.Client, CLIENT_POD_CPU_LIMIT, CLIENT_POD_MEMORY_LIMIT, CLIENT_POD_CPU_REQUEST, CLIENT_POD_MEMORY_REQUEST)
+	limits, requests, err := getTransferPodResources(
+		t.Client,
+		t.transferSpec().ClientPodResources,
+		CLIENT_POD_CPU_LIMIT,
+		CLIENT_POD_MEMORY_LIMIT,
+		CLIENT_POD_CPU_REQUEST,
+		CLIENT_POD_MEMORY_REQUEST)
 	if err != nil {
 		return err
 	}
 
 	isPrivileged, err := isRsyncPrivileged(srcClient)
 
+	// Pods created within the concurrency limits, the others are queued.
+	clientPods := []corev1.Pod{}
+	urls, err := t.getRsyncDaemonURLs(srcClient)
+	if err != nil {
+		return err
+	}
 	for ns, vols := range pvcMap {
-		// Get stunnel svc IP
-		svc := corev1.Service{}
-		key := types.NamespacedName{Name: DirectVolumeMigrationRsyncTransferSvc, Namespace: ns}
-		err := srcClient.Get(context.TODO(), key, &svc)
-		if err != nil {
-			return err
-		}
-		ip := svc.Spec.ClusterIP
-
 		trueBool := true
 		runAsUser := int64(0)
 
@@ -1031,17 +1063,21 @@ func (t *Task) createRsyncClientPods() error {
 				Name: vol.name,
 				VolumeSource: corev1.VolumeSource{
 					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: vol.name,
+						ClaimName: t.getClientClaimName(vol.name),
 					},
 				},
 			})
+			rsyncOptions, err := t.getRsyncOptions()
+			if err != nil {
+				return err
+			}
 			rsyncCommand := []string{"rsync"}
-			rsyncCommand = append(rsyncCommand, t.getRsyncOptions()...)
+			rsyncCommand = append(rsyncCommand, rsyncOptions...)
 			if vol.verify {
 				rsyncCommand = append(rsyncCommand, "--checksum")
 			}
 			rsyncCommand = append(rsyncCommand, fmt.Sprintf("/mnt/%s/%s/", ns, vol.name))
-			rsyncCommand = append(rsyncCommand, fmt.Sprintf("rsync://root@%s/%s", ip, vol.name))
+			rsyncCommand = append(rsyncCommand, urls[ns+"/"+vol.name])
 			t.Log.Info(fmt.Sprintf("Using Rsync command [%s]", strings.Join(rsyncCommand, " ")))
 			containers = append(containers, corev1.Container{
 				Name:  DirectVolumeMigrationRsyncClient,
@@ -1093,17 +1129,12 @@ func (t *Task) createRsyncClientPods() error {
 					},
 				},
 			}
-			err = srcClient.Create(context.TODO(), &clientPod)
-			if k8serror.IsAlreadyExists(err) {
-				t.Log.Info("Rsync client pod already exists on source", "namespace", clientPod.Namespace)
-			} else if err != nil {
-				return err
-			}
-			t.Log.Info("Rsync client pod created", "name", clientPod.Name, "namespace", clientPod.Namespace)
+			applyTransferScheduling(&clientPod.Spec, t.transferSpec().Source)
+			clientPods = append(clientPods, clientPod)
 		}
 
 	}
-	return nil
+	return t.createClientPods(srcClient, clientPods)
 }
 
 func isRsyncPrivileged(client compat.Client) (bool, error) {
@@ -1144,6 +1175,7 @@ func (t *Task) createPVProgressCR() error {
 						Namespace: ns,
 						Name:      fmt.Sprintf("directvolumemigration-rsync-transfer-%s", vol.Name),
 					},
+					Engine: t.getEngine(),
 				},
 				Status: migapi.DirectVolumeMigrationProgressStatus{},
 			}
@@ -1188,7 +1220,10 @@ func (t *Task) haveRsyncClientPodsCompletedOrFailed() (bool, bool, error) {
 				Namespace: ns,
 				Name:      fmt.Sprintf("directvolumemigration-rsync-transfer-%s", vol.Name),
 			}
+			attempts := t.findVolumeAttempts(ns, vol.Name)
 			switch {
+			case attempts != nil && attempts.RetryAt != nil:
+				// Failed, retried after the delay.
 			case dvmp.Status.PodPhase == corev1.PodRunning:
 				t.Owner.Status.RunningPods = append(t.Owner.Status.RunningPods, &migapi.PodProgress{
 					ObjectReference:             objRef,
@@ -1348,13 +1383,12 @@ func (t *Task) areRsyncResourcesDeleted(client compat.Client) (error, bool) {
 	selector := labels.SelectorFromSet(map[string]string{
 		"app": DirectVolumeMigrationRsyncTransfer,
 	})
-	pvcMap := t.getPVCNamespaceMap()
-	for ns, _ := range pvcMap {
+	for _, ns := range t.getRsyncNamespaces() {
 		podList := corev1.PodList{}
 		cmList := corev1.ConfigMapList{}
 		svcList := corev1.ServiceList{}
 		secretList := corev1.SecretList{}
-		routeList := routev1.RouteList{}
+		pvcList := corev1.PersistentVolumeClaimList{}
 
 		// Get Pod list
 		err := client.List(
@@ -1404,17 +1438,51 @@ func (t *Task) areRsyncResourcesDeleted(client compat.Client) (error, bool) {
 		}
 
 		// Get route list
+		routes, err := listRsyncTransferRoutes(
+			client,
+			&k8sclient.ListOptions{
+				Namespace:     ns,
+				LabelSelector: selector,
+			})
+		if err != nil {
+			return err, false
+		}
+
+		// Get ingress list
+		ingresses, err := listRsyncTransferIngresses(
+			client,
+			&k8sclient.ListOptions{
+				Namespace:     ns,
+				LabelSelector: selector,
+			})
+		if err != nil {
+			return err, false
+		}
+
+		// Get snapshot clone list
 		err = client.List(
 			context.TODO(),
 			&k8sclient.ListOptions{
 				Namespace:     ns,
-				LabelSelector: selector,
+				LabelSelector: t.snapshotSelector(),
 			},
-			&routeList)
+			&pvcList)
+		if err != nil {
+			return err, false
+		}
+
+		// Get snapshot list
+		snapshots, err := listRsyncTransferSnapshots(
+			client,
+			&k8sclient.ListOptions{
+				Namespace:     ns,
+				LabelSelector: t.snapshotSelector(),
+			})
 		if err != nil {
 			return err, false
 		}
-		if len(routeList.Items) > 0 || len(svcList.Items) > 0 || len(cmList.Items) > 0 || len(secretList.Items) > 0 || len(podList.Items) > 0 {
+		if len(routes) > 0 || len(ingresses) > 0 || len(svcList.Items) > 0 || len(cmList.Items) > 0 || len(secretList.Items) > 0 || len(podList.Items) > 0 ||
+			len(pvcList.Items) > 0 || len(snapshots) > 0 {
 			return nil, false
 		}
 	}
@@ -1428,13 +1496,12 @@ func (t *Task) findAndDeleteResources(client compat.Client) error {
 	selector := labels.SelectorFromSet(map[string]string{
 		"app": DirectVolumeMigrationRsyncTransfer,
 	})
-	pvcMap := t.getPVCNamespaceMap()
-	for ns, _ := range pvcMap {
+	for _, ns := range t.getRsyncNamespaces() {
 		podList := corev1.PodList{}
 		cmList := corev1.ConfigMapList{}
 		svcList := corev1.ServiceList{}
 		secretList := corev1.SecretList{}
-		routeList := routev1.RouteList{}
+		pvcList := corev1.PersistentVolumeClaimList{}
 
 		// Get Pod list
 		err := client.List(
@@ -1484,13 +1551,46 @@ func (t *Task) findAndDeleteResources(client compat.Client) error {
 		}
 
 		// Get route list
+		routes, err := listRsyncTransferRoutes(
+			client,
+			&k8sclient.ListOptions{
+				Namespace:     ns,
+				LabelSelector: selector,
+			})
+		if err != nil {
+			return err
+		}
+
+		// Get ingress list
+		ingresses, err := listRsyncTransferIngresses(
+			client,
+			&k8sclient.ListOptions{
+				Namespace:     ns,
+				LabelSelector: selector,
+			})
+		if err != nil {
+			return err
+		}
+
+		// Get snapshot clone list
 		err = client.List(
 			context.TODO(),
 			&k8sclient.ListOptions{
 				Namespace:     ns,
-				LabelSelector: selector,
+				LabelSelector: t.snapshotSelector(),
 			},
-			&routeList)
+			&pvcList)
+		if err != nil {
+			return err
+		}
+
+		// Get snapshot list
+		snapshots, err := listRsyncTransferSnapshots(
+			client,
+			&k8sclient.ListOptions{
+				Namespace:     ns,
+				LabelSelector: t.snapshotSelector(),
+			})
 		if err != nil {
 			return err
 		}
@@ -1512,13 +1612,21 @@ func (t *Task) findAndDeleteResources(client compat.Client) error {
 		}
 
 		// Delete routes
-		for _, route := range routeList.Items {
+		for _, route := range routes {
 			err = client.Delete(context.TODO(), &route, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
 			if err != nil && !k8serror.IsNotFound(err) {
 				return err
 			}
 		}
 
+		// Delete ingresses
+		for _, ingress := range ingresses {
+			err = client.Delete(context.TODO(), &ingress, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
+			if err != nil && !k8serror.IsNotFound(err) {
+				return err
+			}
+		}
+
 		// Delete svcs
 		for _, svc := range svcList.Items {
 			err = client.Delete(context.TODO(), &svc, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
@@ -1534,6 +1642,22 @@ func (t *Task) findAndDeleteResources(client compat.Client) error {
 				return err
 			}
 		}
+
+		// Delete snapshot clones
+		for _, claim := range pvcList.Items {
+			err = client.Delete(context.TODO(), &claim, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
+			if err != nil && !k8serror.IsNotFound(err) {
+				return err
+			}
+		}
+
+		// Delete snapshots
+		for _, snapshot := range snapshots {
+			err = client.Delete(context.TODO(), &snapshot, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
+			if err != nil && !k8serror.IsNotFound(err) {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,219 @@
+package directvolumemigration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/konveyor/mig-controller/pkg/compat"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	dapi "k8s.io/client-go/discovery"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VolumeSnapshot API.
+const (
+	VolumeSnapshotAPIGroup   = "snapshot.storage.k8s.io"
+	VolumeSnapshotAPIVersion = "snapshot.storage.k8s.io/v1"
+	VolumeSnapshotKind       = "VolumeSnapshot"
+	VolumeSnapshotListKind   = "VolumeSnapshotList"
+)
+
+// Suffixes of the snapshot and clone names.
+const (
+	snapshotSuffix = "dvm-snapshot"
+	cloneSuffix    = "dvm-clone"
+)
+
+// Get the VolumeSnapshot name of a source PVC.
+// The name includes the DVM UID so snapshots left by earlier
+// migrations are never reused.
+func (t *Task) snapshotName(pvcName string) string {
+	return fmt.Sprintf("%s-%s-%s", pvcName, snapshotSuffix, t.Owner.UID)
+}
+
+// Get the clone PVC name of a source PVC.
+func (t *Task) snapshotCloneName(pvcName string) string {
+	return fmt.Sprintf("%s-%s-%s", pvcName, cloneSuffix, t.Owner.UID)
+}
+
+// Get the labels of the VolumeSnapshots and snapshot clones.
+// The DVM UID label scopes the cleanup to the snapshots of the DVM.
+func (t *Task) snapshotLabels() map[string]string {
+	return map[string]string{
+		"app":                      DirectVolumeMigrationRsyncTransfer,
+		DirectVolumeMigrationLabel: string(t.Owner.UID),
+	}
+}
+
+// Get the selector of the VolumeSnapshots and snapshot clones.
+func (t *Task) snapshotSelector() labels.Selector {
+	return labels.SelectorFromSet(t.snapshotLabels())
+}
+
+// Get whether the VolumeSnapshot API is served by a cluster.
+func isVolumeSnapshotAPIServed(client dapi.DiscoveryInterface) (bool, error) {
+	resources, err := client.ServerResourcesForGroupVersion(VolumeSnapshotAPIVersion)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == VolumeSnapshotKind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Get the claim mounted by the client pod of a source PVC.
+// The snapshot clone is mounted when the volumes are transferred from snapshots.
+func (t *Task) getClientClaimName(pvcName string) string {
+	if t.transferSpec().HasSnapshot() {
+		return t.snapshotCloneName(pvcName)
+	}
+	return pvcName
+}
+
+// Build the VolumeSnapshot of a source PVC.
+func (t *Task) buildVolumeSnapshot(namespace, pvcName string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetAPIVersion(VolumeSnapshotAPIVersion)
+	snapshot.SetKind(VolumeSnapshotKind)
+	snapshot.SetName(t.snapshotName(pvcName))
+	snapshot.SetNamespace(namespace)
+	snapshot.SetLabels(t.snapshotLabels())
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+	if class := t.transferSpec().Snapshot.VolumeSnapshotClassName; class != "" {
+		spec["volumeSnapshotClassName"] = class
+	}
+	snapshot.Object["spec"] = spec
+	return snapshot
+}
+
+// Create a VolumeSnapshot of each source PVC.
+func (t *Task) createSourceSnapshots() error {
+	srcClient, err := t.getSourceClient()
+	if err != nil {
+		return err
+	}
+	for ns, vols := range t.getPVCNamespaceMap() {
+		for _, vol := range vols {
+			snapshot := t.buildVolumeSnapshot(ns, vol.Name)
+			err = srcClient.Create(context.TODO(), snapshot)
+			if k8serror.IsAlreadyExists(err) {
+				t.Log.Info("VolumeSnapshot already exists", "name", snapshot.GetName(), "namespace", ns)
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Get whether the VolumeSnapshots of the source PVCs are ready to use.
+// Returns the errors reported by the snapshots.
+func (t *Task) areSourceSnapshotsReady() (bool, []string, error) {
+	srcClient, err := t.getSourceClient()
+	if err != nil {
+		return false, nil, err
+	}
+	ready := true
+	reasons := []string{}
+	for ns, vols := range t.getPVCNamespaceMap() {
+		for _, vol := range vols {
+			snapshot := &unstructured.Unstructured{}
+			snapshot.SetAPIVersion(VolumeSnapshotAPIVersion)
+			snapshot.SetKind(VolumeSnapshotKind)
+			key := types.NamespacedName{Name: t.snapshotName(vol.Name), Namespace: ns}
+			err = srcClient.Get(context.TODO(), key, snapshot)
+			if err != nil {
+				return false, nil, err
+			}
+			message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+			if found && message != "" {
+				reasons = append(reasons, fmt.Sprintf("VolumeSnapshot %s/%s: %s", ns, snapshot.GetName(), message))
+			}
+			readyToUse, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+			if !readyToUse {
+				ready = false
+			}
+		}
+	}
+	return ready, reasons, nil
+}
+
+// Create the clone PVC of each source PVC, restored from its VolumeSnapshot.
+// The clones are provisioned with the storage class, access modes and
+// size of the source PVCs.
+func (t *Task) createSnapshotClones() error {
+	srcClient, err := t.getSourceClient()
+	if err != nil {
+		return err
+	}
+	apiGroup := VolumeSnapshotAPIGroup
+	for ns, vols := range t.getPVCNamespaceMap() {
+		for _, vol := range vols {
+			srcPVC := corev1.PersistentVolumeClaim{}
+			key := types.NamespacedName{Name: vol.Name, Namespace: ns}
+			err = srcClient.Get(context.TODO(), key, &srcPVC)
+			if err != nil {
+				return err
+			}
+			clone := corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      t.snapshotCloneName(vol.Name),
+					Namespace: ns,
+					Labels:    t.snapshotLabels(),
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: srcPVC.Spec.StorageClassName,
+					AccessModes:      srcPVC.Spec.AccessModes,
+					VolumeMode:       srcPVC.Spec.VolumeMode,
+					Resources: corev1.ResourceRequirements{
+						Requests: srcPVC.Spec.Resources.Requests,
+					},
+					DataSource: &corev1.TypedLocalObjectReference{
+						APIGroup: &apiGroup,
+						Kind:     VolumeSnapshotKind,
+						Name:     t.snapshotName(vol.Name),
+					},
+				},
+			}
+			err = srcClient.Create(context.TODO(), &clone)
+			if k8serror.IsAlreadyExists(err) {
+				t.Log.Info("Snapshot clone already exists", "name", clone.Name, "namespace", ns)
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Get the rsync transfer VolumeSnapshots.
+// No VolumeSnapshots are found when the API is not served by the cluster.
+func listRsyncTransferSnapshots(client compat.Client, options *k8sclient.ListOptions) ([]unstructured.Unstructured, error) {
+	list := unstructured.UnstructuredList{}
+	list.SetAPIVersion(VolumeSnapshotAPIVersion)
+	list.SetKind(VolumeSnapshotListKind)
+	err := client.List(context.TODO(), options, &list)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -0,0 +1,209 @@
+package directvolumemigration
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dapi "k8s.io/client-go/discovery"
+)
+
+// A discovery client serving the listed resources.
+type discoveryClient struct {
+	dapi.DiscoveryInterface
+	resources []*metav1.APIResourceList
+}
+
+func (c discoveryClient) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	for _, list := range c.resources {
+		if list.GroupVersion == groupVersion {
+			return list, nil
+		}
+	}
+	return nil, k8serror.NewNotFound(schema.GroupResource{}, groupVersion)
+}
+
+func Test_isVolumeSnapshotAPIServed(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		want      bool
+	}{
+		{
+			name: "not served",
+		},
+		{
+			name: "v1beta1 only",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: VolumeSnapshotAPIGroup + "/v1beta1",
+					APIResources: []metav1.APIResource{{Kind: VolumeSnapshotKind}},
+				},
+			},
+		},
+		{
+			name: "served",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: VolumeSnapshotAPIVersion,
+					APIResources: []metav1.APIResource{{Kind: "VolumeSnapshotClass"}, {Kind: VolumeSnapshotKind}},
+				},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isVolumeSnapshotAPIServed(discoveryClient{resources: tt.resources})
+			if err != nil {
+				t.Fatalf("isVolumeSnapshotAPIServed() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("isVolumeSnapshotAPIServed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_buildVolumeSnapshot(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot *migapi.TransferSnapshot
+		want     map[string]interface{}
+	}{
+		{
+			name:     "default class",
+			snapshot: &migapi.TransferSnapshot{},
+			want: map[string]interface{}{
+				"source": map[string]interface{}{
+					"persistentVolumeClaimName": "pvc-0",
+				},
+			},
+		},
+		{
+			name:     "class",
+			snapshot: &migapi.TransferSnapshot{VolumeSnapshotClassName: "csi-gp3"},
+			want: map[string]interface{}{
+				"source": map[string]interface{}{
+					"persistentVolumeClaimName": "pvc-0",
+				},
+				"volumeSnapshotClassName": "csi-gp3",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					ObjectMeta: metav1.ObjectMeta{UID: "uid-0"},
+					Spec: migapi.DirectVolumeMigrationSpec{
+						Transfer: &migapi.TransferSpec{Snapshot: tt.snapshot},
+					},
+				},
+			}
+			got := task.buildVolumeSnapshot("ns-0", "pvc-0")
+			if got.GetName() != "pvc-0-dvm-snapshot-uid-0" || got.GetNamespace() != "ns-0" {
+				t.Errorf("buildVolumeSnapshot() = %s/%s", got.GetNamespace(), got.GetName())
+			}
+			if !task.snapshotSelector().Matches(labels.Set(got.GetLabels())) {
+				t.Errorf("buildVolumeSnapshot() labels = %v", got.GetLabels())
+			}
+			if !reflect.DeepEqual(got.Object["spec"], tt.want) {
+				t.Errorf("buildVolumeSnapshot() spec = %v, want %v", got.Object["spec"], tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_snapshotSelector(t *testing.T) {
+	task := &Task{
+		Owner: &migapi.DirectVolumeMigration{ObjectMeta: metav1.ObjectMeta{UID: "uid-0"}},
+	}
+	other := &Task{
+		Owner: &migapi.DirectVolumeMigration{ObjectMeta: metav1.ObjectMeta{UID: "uid-1"}},
+	}
+	selector := task.snapshotSelector()
+	if !selector.Matches(labels.Set(task.snapshotLabels())) {
+		t.Errorf("snapshotSelector() does not match the snapshots of the DVM")
+	}
+	if selector.Matches(labels.Set(other.snapshotLabels())) {
+		t.Errorf("snapshotSelector() matches the snapshots of another DVM")
+	}
+	if selector.Matches(labels.Set{"app": DirectVolumeMigrationRsyncTransfer}) {
+		t.Errorf("snapshotSelector() matches unlabeled rsync transfer resources")
+	}
+}
+
+func TestTask_getPVCNodeNameMap(t *testing.T) {
+	task := &Task{
+		Owner: &migapi.DirectVolumeMigration{
+			Spec: migapi.DirectVolumeMigrationSpec{
+				PersistentVolumeClaims: []migapi.PVCToMigrate{
+					{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}},
+				},
+				Transfer: &migapi.TransferSpec{Snapshot: &migapi.TransferSnapshot{}},
+			},
+		},
+	}
+	got, err := task.getPVCNodeNameMap()
+	if err != nil {
+		t.Fatalf("getPVCNodeNameMap() error = %v", err)
+	}
+	if len(got) > 0 {
+		t.Errorf("getPVCNodeNameMap() = %v, want empty", got)
+	}
+}
+
+func TestTask_newVolumeTransfer(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot *migapi.TransferSnapshot
+	}{
+		{
+			name: "source PVC",
+		},
+		{
+			name:     "snapshot clone",
+			snapshot: &migapi.TransferSnapshot{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					Spec: migapi.DirectVolumeMigrationSpec{
+						PersistentVolumeClaims: []migapi.PVCToMigrate{
+							{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-0"}},
+							{ObjectReference: &corev1.ObjectReference{Namespace: "ns-0", Name: "pvc-1"}},
+						},
+						Transfer: &migapi.TransferSpec{Snapshot: tt.snapshot},
+					},
+				},
+			}
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "pvc-1",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: task.getClientClaimName("pvc-1"),
+								},
+							},
+						},
+					},
+				},
+			}
+			pod.Namespace = "ns-0"
+			transfer := task.newVolumeTransfer(pod)
+			if transfer.pvc != "pvc-1" || transfer.index != 1 {
+				t.Errorf("newVolumeTransfer() pvc = %s, index = %d", transfer.pvc, transfer.index)
+			}
+		})
+	}
+}
@@ -3,41 +3,34 @@ package directvolumemigration
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"fmt"
 	"text/template"
 
 	"gopkg.in/yaml.v2"
 
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"crypto/x509/pkix"
-
-	//"encoding/asn1"
-	"encoding/pem"
-	"math/big"
-	"time"
-
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
-
-	//"k8s.io/apimachinery/pkg/types"
 	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type stunnelConfig struct {
-	Name        string
-	Namespace   string
-	StunnelPort int32
-	RsyncRoute  string
-	RsyncPort   int32
+	Name          string
+	Namespace     string
+	StunnelPort   int32
+	Endpoint      string
+	SNI           string
+	RsyncPort     int32
+	TLSMinVersion string
+	Ciphers       string
+	CipherSuites  string
+	VerifyClient  bool
 }
 
-// TODO: Parameterize this more to support custom
-// networking configs from directvolumemigration spec
 const stunnelClientConfigTemplate = `apiVersion: v1
 kind: ConfigMap
 metadata:
@@ -47,14 +40,23 @@ data:
   stunnel.conf: |
     foreground = yes
     pid =
-    sslVersion = TLSv1.2
+    sslVersionMin = {{ .TLSMinVersion }}
+{{- if .Ciphers }}
+    ciphers = {{ .Ciphers }}
+{{- end }}
+{{- if .CipherSuites }}
+    ciphersuites = {{ .CipherSuites }}
+{{- end }}
     client = yes
     syslog = no
     [rsync]
     accept = {{ .StunnelPort}}
     CAFile = /etc/stunnel/certs/ca.crt
     cert = /etc/stunnel/certs/tls.crt
-    connect = {{ .RsyncRoute }}:443
+    connect = {{ .Endpoint }}
+{{- if .SNI }}
+    sni = {{ .SNI }}
+{{- end }}
     verify = 2
     key = /etc/stunnel/certs/tls.key
 `
@@ -70,7 +72,13 @@ data:
     pid =
     socket = l:TCP_NODELAY=1
     socket = r:TCP_NODELAY=1
-    sslVersion = TLSv1.2
+    sslVersionMin = {{ .TLSMinVersion }}
+{{- if .Ciphers }}
+    ciphers = {{ .Ciphers }}
+{{- end }}
+{{- if .CipherSuites }}
+    ciphersuites = {{ .CipherSuites }}
+{{- end }}
     debug = 7
 
     [rsync]
@@ -78,6 +86,10 @@ data:
     connect = {{ .RsyncPort }}
     key = /etc/stunnel/certs/tls.key
     cert = /etc/stunnel/certs/tls.crt
+{{- if .VerifyClient }}
+    CAFile = /etc/stunnel/certs/ca.crt
+    verify = 2
+{{- end }}
     TIMEOUTclose = 0
 `
 
@@ -99,6 +111,10 @@ func (t *Task) createStunnelConfig() error {
 	if err != nil {
 		return err
 	}
+	// The transfer agent terminates TLS without stunnel.
+	if t.getEngine() == migapi.TransferEngineAgent {
+		return nil
+	}
 	// openssl library? to generate new certs
 
 	// Create same stunnel configmap with certs on both source+destination
@@ -127,18 +143,24 @@ func (t *Task) createStunnelConfig() error {
 	// Create 1 rsync transfer+stunnel pod per namespace
 	// Create 1 stunnel svc
 	pvcMap := t.getPVCNamespaceMap()
+	tls := t.transferSpec().GetTLS()
 
 	for ns, _ := range pvcMap {
 		// Declare config
-		rsyncRoute, err := t.getRsyncRoute(ns)
+		endpoint, err := t.getRsyncEndpoint(ns)
 		if err != nil {
 			return err
 		}
 		stunnelConf := stunnelConfig{
-			Namespace:   ns,
-			StunnelPort: 2222,
-			RsyncPort:   22,
-			RsyncRoute:  rsyncRoute,
+			Namespace:     ns,
+			StunnelPort:   2222,
+			RsyncPort:     22,
+			Endpoint:      endpoint.String(),
+			SNI:           endpoint.SNI,
+			TLSMinVersion: tls.MinVersion,
+			Ciphers:       tls.Ciphers,
+			CipherSuites:  tls.CipherSuites,
+			VerifyClient:  *tls.VerifyClient,
 		}
 
 		// Generate templates
@@ -223,8 +245,9 @@ func (t *Task) setupCerts() error {
 	}
 
 	// steps
-	// 1. Generate CA cert
-	// 2. Loop through all namespace generating new certs for each namespace
+	// 1. Generate (or load the user provided) CA cert
+	// 2. Loop through all namespace issuing a client (source) and server
+	//    (destination) cert for each namespace
 	// 3. Create secret in src+destination namespaces containing each cert
 	// 4. Rsync client+transfer pods mount certs from secret
 
@@ -232,69 +255,28 @@ func (t *Task) setupCerts() error {
 	// TODO: Need to handle case where configmap gets deleted and 2 versions of
 	// CA bundle exist
 
-	caPrivKey, err := rsa.GenerateKey(rand.Reader, 4096)
-	if err != nil {
-		return err
-	}
-
-	subj := pkix.Name{
-		CommonName:         "openshift.io",
-		Country:            []string{"US"},
-		Province:           []string{"NC"},
-		Locality:           []string{"RDU"},
-		Organization:       []string{"Migration Engineering"},
-		OrganizationalUnit: []string{"Engineering"},
-	}
-
-	certTemp := x509.Certificate{
-		SerialNumber:          big.NewInt(2020),
-		Subject:               subj,
-		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(10, 0, 0),
-		IsCA:                  true,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
-		BasicConstraintsValid: true,
-	}
-
-	caBytes, err := x509.CreateCertificate(
-		rand.Reader,
-		&certTemp,
-		&certTemp,
-		&caPrivKey.PublicKey,
-		caPrivKey,
-	)
-	if err != nil {
-		return err
-	}
-
-	caPEM := new(bytes.Buffer)
-	err = pem.Encode(caPEM, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: caBytes,
-	})
+	tls := t.transferSpec().GetTLS()
+	ca, err := t.getCA(tls)
 	if err != nil {
 		return err
 	}
+	expiry := ca.Cert.NotAfter
 
-	caPrivKeyPEM := new(bytes.Buffer)
-	err = pem.Encode(caPrivKeyPEM, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(caPrivKey),
-	})
-	if err != nil {
-		return err
-	}
-
-	// Create secret in each namespace  src+dest with tls.crt = caPEM and tls.key
-	// = caPrivKeyPEM
 	// Secret data contains:
 	// ca.crt
-	// tls.crt (right now equal to ca.crt)
+	// tls.crt (client cert on source, server cert on destination)
 	// tls.key
 
 	pvcMap := t.getPVCNamespaceMap()
 	for ns, _ := range pvcMap {
+		clientCert, err := ca.issue(ns, x509.ExtKeyUsageClientAuth, tls.Duration.Duration)
+		if err != nil {
+			return err
+		}
+		serverCert, err := ca.issue(ns, x509.ExtKeyUsageServerAuth, tls.Duration.Duration)
+		if err != nil {
+			return err
+		}
 		srcSecret := corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: ns,
@@ -304,12 +286,19 @@ func (t *Task) setupCerts() error {
 				},
 			},
 			Data: map[string][]byte{
-				"tls.crt": caPEM.Bytes(),
-				"ca.crt":  caPEM.Bytes(),
-				"tls.key": caPrivKeyPEM.Bytes(),
+				"tls.crt": clientCert.CertPEM,
+				"ca.crt":  ca.CertPEM,
+				"tls.key": clientCert.KeyPEM,
+			},
+		}
+		destSecret := corev1.Secret{
+			ObjectMeta: srcSecret.ObjectMeta,
+			Data: map[string][]byte{
+				"tls.crt": serverCert.CertPEM,
+				"ca.crt":  ca.CertPEM,
+				"tls.key": serverCert.KeyPEM,
 			},
 		}
-		destSecret := srcSecret
 		err = srcClient.Create(context.TODO(), &srcSecret)
 		if k8serror.IsAlreadyExists(err) {
 			t.Log.Info("Secret already exists on source", "namespace", srcSecret.Namespace)
@@ -322,10 +311,33 @@ func (t *Task) setupCerts() error {
 		} else if err != nil {
 			return err
 		}
+		for _, cert := range []*certificate{clientCert, serverCert} {
+			if cert.Cert.NotAfter.Before(expiry) {
+				expiry = cert.Cert.NotAfter
+			}
+		}
 	}
+	t.Owner.Status.CertificateExpiry = &metav1.Time{Time: expiry}
 	return nil
 }
 
+// Get the CA issuing the stunnel certificates.
+// The CA is loaded from the secret referenced by the TLS spec,
+// otherwise a self-signed CA is generated.
+func (t *Task) getCA(tls migapi.TransferTLS) (*certificate, error) {
+	if tls.CASecretRef == nil {
+		return generateCA()
+	}
+	secret, err := migapi.GetSecret(t.Client, tls.CASecretRef)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("CA secret %s/%s not found", tls.CASecretRef.Namespace, tls.CASecretRef.Name)
+	}
+	return loadCA(secret)
+}
+
 // Create stunnel client pods + svc
 func (t *Task) createStunnelClientPods() error {
 	srcClient, err := t.getSourceClient()
@@ -343,7 +355,13 @@ func (t *Task) createStunnelClientPods() error {
 		return err
 	}
 
-	limits, requests, err := getPodResourceLists(t.Client, STUNNEL_POD_CPU_LIMIT, STUNNEL_POD_MEMORY_LIMIT, STUNNEL_POD_CPU_REQUEST, STUNNEL_POD_MEMORY_REQUEST)
+	limits, requests, err := getTransferPodResources(
+		t.Client,
+		t.transferSpec().StunnelPodResources,
+		STUNNEL_POD_CPU_LIMIT,
+		STUNNEL_POD_MEMORY_LIMIT,
+		STUNNEL_POD_CPU_REQUEST,
+		STUNNEL_POD_MEMORY_REQUEST)
 	if err != nil {
 		return err
 	}
@@ -471,6 +489,7 @@ func (t *Task) createStunnelClientPods() error {
 			return err
 		}
 		t.Log.Info("stunnel client svc created", "name", clientPod.Name, "namespace", svc.Namespace)
+		applyTransferScheduling(&clientPod.Spec, t.transferSpec().Source)
 		err = srcClient.Create(context.TODO(), &clientPod)
 		if k8serror.IsAlreadyExists(err) {
 			t.Log.Info("Stunnel client pod already exists on source", "namespace", clientPod.Namespace)
@@ -9,6 +9,7 @@ import (
 	liberr "github.com/konveyor/controller/pkg/error"
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	"github.com/konveyor/mig-controller/pkg/compat"
+	migsettings "github.com/konveyor/mig-controller/pkg/settings"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -32,11 +33,18 @@ const (
 	CreateRsyncRoute                     = "CreateRsyncRoute"
 	EnsureRsyncRouteAdmitted             = "EnsureRsyncRouteAdmitted"
 	CreateRsyncTransferPods              = "CreateRsyncTransferPods"
+	CreateAgentServerPods                = "CreateAgentServerPods"
 	WaitForRsyncTransferPodsRunning      = "WaitForRsyncTransferPodsRunning"
 	CreateStunnelClientPods              = "CreateStunnelClientPods"
 	WaitForStunnelClientPodsRunning      = "WaitForStunnelClientPodsRunning"
+	EnsureRsyncTransportReachable        = "EnsureRsyncTransportReachable"
+	CreateSourceSnapshots                = "CreateSourceSnapshots"
+	WaitForSourceSnapshotsReady          = "WaitForSourceSnapshotsReady"
+	CreateSnapshotClones                 = "CreateSnapshotClones"
 	CreatePVProgressCRs                  = "CreatePVProgressCRs"
 	CreateRsyncClientPods                = "CreateRsyncClientPods"
+	CreateAgentClientPods                = "CreateAgentClientPods"
+	CreateLocalServerPods                = "CreateLocalServerPods"
 	WaitForRsyncClientPodsCompleted      = "WaitForRsyncClientPodsCompleted"
 	Verification                         = "Verification"
 	DeleteRsyncResources                 = "DeleteRsyncResources"
@@ -63,14 +71,18 @@ const (
 	DirectVolumeMigrationStunnel            = "stunnel"
 	MigratedByPlanLabel                     = "migration.openshift.io/migrated-by-migplan"      // (migplan UID)
 	MigratedByMigrationLabel                = "migration.openshift.io/migrated-by-migmigration" // (migmigration UID)
+	DirectVolumeMigrationLabel              = "migration.openshift.io/directvolumemigration"    // (DVM UID)
 
 )
 
 // Flags
-// TODO: are there any phases to skip?
-/*const (
-	Quiesce      = 0x01 // Only when QuiescePods (true).
-)*/
+const (
+	RsyncEngine = 0x01 // Only when volumes are transferred by rsync.
+	AgentEngine = 0x02 // Only when volumes are transferred by the transfer agent.
+	Remote      = 0x04 // Only when the source and destination clusters differ.
+	Local       = 0x08 // Only when the source and destination clusters are the same.
+	Snapshot    = 0x10 // Only when volumes are transferred from snapshot clones.
+)
 
 // Step
 type Step struct {
@@ -114,16 +126,23 @@ var VolumeMigration = Itinerary{
 		{phase: DestinationNamespacesCreated},
 		{phase: CreateDestinationPVCs},
 		{phase: DestinationPVCsCreated},
-		{phase: CreateRsyncRoute},
-		{phase: EnsureRsyncRouteAdmitted},
-		{phase: CreateRsyncConfig},
-		{phase: CreateStunnelConfig},
+		{phase: CreateRsyncRoute, all: Remote},
+		{phase: EnsureRsyncRouteAdmitted, all: Remote},
+		{phase: CreateRsyncConfig, all: Remote},
+		{phase: CreateStunnelConfig, all: Remote},
+		{phase: CreateSourceSnapshots, all: Snapshot},
+		{phase: WaitForSourceSnapshotsReady, all: Snapshot},
+		{phase: CreateSnapshotClones, all: Snapshot},
 		{phase: CreatePVProgressCRs},
-		{phase: CreateRsyncTransferPods},
+		{phase: CreateRsyncTransferPods, all: RsyncEngine | Remote},
+		{phase: CreateAgentServerPods, all: AgentEngine | Remote},
+		{phase: CreateLocalServerPods, all: Local},
 		{phase: WaitForRsyncTransferPodsRunning},
-		{phase: CreateStunnelClientPods},
-		{phase: WaitForStunnelClientPodsRunning},
-		{phase: CreateRsyncClientPods},
+		{phase: CreateStunnelClientPods, all: RsyncEngine | Remote},
+		{phase: WaitForStunnelClientPodsRunning, all: RsyncEngine | Remote},
+		{phase: EnsureRsyncTransportReachable, all: RsyncEngine | Remote},
+		{phase: CreateRsyncClientPods, all: RsyncEngine},
+		{phase: CreateAgentClientPods, all: AgentEngine},
 		{phase: WaitForRsyncClientPodsCompleted},
 		{phase: DeleteRsyncResources},
 		{phase: WaitForRsyncResourcesTerminated},
@@ -252,7 +271,7 @@ func (t *Task) Run() error {
 			return liberr.Wrap(err)
 		}
 	case CreateRsyncRoute:
-		err := t.createRsyncTransferRoute()
+		err := t.createRsyncTransport()
 		if err != nil {
 			return liberr.Wrap(err)
 		}
@@ -261,7 +280,7 @@ func (t *Task) Run() error {
 			return liberr.Wrap(err)
 		}
 	case EnsureRsyncRouteAdmitted:
-		admitted, reasons, err := t.areRsyncRoutesAdmitted()
+		admitted, reasons, err := t.isRsyncTransportReady()
 		if err != nil {
 			return liberr.Wrap(err)
 		}
@@ -285,7 +304,7 @@ func (t *Task) Run() error {
 						Status:   True,
 						Reason:   migapi.NotReady,
 						Category: Warn,
-						Message:  fmt.Sprintf("Some or all rsync transfer routes have failed to be admitted within 3 mins on destination cluster. Errors: %v", reasons),
+						Message:  fmt.Sprintf("Some or all rsync transfer routes or services have failed to be ready within 3 mins on destination cluster. Errors: %v", reasons),
 					},
 				)
 			}
@@ -317,6 +336,15 @@ func (t *Task) Run() error {
 		if err = t.next(); err != nil {
 			return liberr.Wrap(err)
 		}
+	case CreateAgentServerPods:
+		err := t.createAgentServerPods()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		t.Requeue = NoReQ
+		if err = t.next(); err != nil {
+			return liberr.Wrap(err)
+		}
 	case WaitForRsyncTransferPodsRunning:
 		running, err := t.areRsyncTransferPodsRunning()
 		if err != nil {
@@ -401,6 +429,119 @@ func (t *Task) Run() error {
 			}
 			t.Requeue = PollReQ
 		}
+	case EnsureRsyncTransportReachable:
+		reachable, reasons, err := t.isRsyncTransportReachable()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if reachable {
+			t.Requeue = NoReQ
+			t.Owner.Status.DeleteCondition(RsyncTransportNotReachable)
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+			t.Owner.Status.StageCondition(Running)
+			cond := t.Owner.Status.FindCondition(Running)
+			if cond == nil {
+				return fmt.Errorf("unable to find running condition")
+			}
+			now := time.Now().UTC()
+			elapsed := now.Sub(cond.LastTransitionTime.Time.UTC())
+			if elapsed > migsettings.Settings.TransportTimeout {
+				t.Owner.Status.SetCondition(
+					migapi.Condition{
+						Type:     RsyncTransportNotReachable,
+						Status:   True,
+						Reason:   migapi.NotReady,
+						Category: migapi.Error,
+						Message: fmt.Sprintf(
+							"Some or all rsync transfer pods cannot be reached from the source cluster within %s. Errors: %v",
+							migsettings.Settings.TransportTimeout,
+							reasons),
+						Durable: true,
+					},
+				)
+				t.fail(MigrationFailed, []string{"the rsync transfer pods cannot be reached from the source cluster"})
+				t.Requeue = NoReQ
+				return nil
+			}
+			if elapsed > 3*time.Minute {
+				t.Owner.Status.SetCondition(
+					migapi.Condition{
+						Type:     RsyncTransportNotReachable,
+						Status:   True,
+						Reason:   migapi.NotReady,
+						Category: Warn,
+						Message:  fmt.Sprintf("Some or all rsync transfer pods cannot be reached from the source cluster within 3 mins. Errors: %v", reasons),
+					},
+				)
+			}
+		}
+	case CreateSourceSnapshots:
+		err := t.createSourceSnapshots()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		t.Requeue = NoReQ
+		if err = t.next(); err != nil {
+			return liberr.Wrap(err)
+		}
+	case WaitForSourceSnapshotsReady:
+		ready, reasons, err := t.areSourceSnapshotsReady()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if ready {
+			t.Requeue = NoReQ
+			t.Owner.Status.DeleteCondition(SourceSnapshotsNotReady)
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+			t.Owner.Status.StageCondition(Running)
+			cond := t.Owner.Status.FindCondition(Running)
+			if cond == nil {
+				return fmt.Errorf("unable to find running condition")
+			}
+			elapsed := time.Now().UTC().Sub(cond.LastTransitionTime.Time.UTC())
+			if elapsed > migsettings.Settings.SnapshotTimeout {
+				t.Owner.Status.SetCondition(migapi.Condition{
+					Type:     SourceSnapshotsTimedOut,
+					Status:   True,
+					Reason:   migapi.NotReady,
+					Category: migapi.Error,
+					Message:  fmt.Sprintf(SourceSnapshotsTimedOutMessage, migsettings.Settings.SnapshotTimeout),
+					Items:    reasons,
+					Durable:  true,
+				})
+				t.fail(MigrationFailed, []string{"the snapshots of the source persistent volume claims are not ready"})
+				t.Requeue = NoReQ
+				return nil
+			}
+			// The snapshot controller retries the failed snapshots.
+			if len(reasons) > 0 {
+				t.Owner.Status.SetCondition(migapi.Condition{
+					Type:     SourceSnapshotsNotReady,
+					Status:   True,
+					Reason:   migapi.NotReady,
+					Category: Warn,
+					Message:  SourceSnapshotsNotReadyMessage,
+					Items:    reasons,
+				})
+			}
+		}
+	case CreateSnapshotClones:
+		err := t.createSnapshotClones()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		t.Requeue = NoReQ
+		if err = t.next(); err != nil {
+			return liberr.Wrap(err)
+		}
 	case CreatePVProgressCRs:
 		err := t.createPVProgressCR()
 		if err != nil {
@@ -419,7 +560,37 @@ func (t *Task) Run() error {
 		if err = t.next(); err != nil {
 			return liberr.Wrap(err)
 		}
+	case CreateAgentClientPods:
+		err := t.createAgentClientPods()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		t.Requeue = NoReQ
+		if err = t.next(); err != nil {
+			return liberr.Wrap(err)
+		}
+	case CreateLocalServerPods:
+		err := t.createLocalServerPods()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		t.Requeue = NoReQ
+		if err = t.next(); err != nil {
+			return liberr.Wrap(err)
+		}
 	case WaitForRsyncClientPodsCompleted:
+		err := t.applyBandwidthSchedule()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		err = t.retryFailedTransfers()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		err = t.startQueuedTransfers()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
 		completed, failed, err := t.haveRsyncClientPodsCompletedOrFailed()
 		if err != nil {
 			return liberr.Wrap(err)
@@ -526,6 +697,9 @@ func (t *Task) next() error {
 	}
 	for n := current + 1; n < len(t.Itinerary.Steps); n++ {
 		next := t.Itinerary.Steps[n]
+		if !t.allFlags(next) || !t.anyFlags(next) {
+			continue
+		}
 		t.Phase = next.phase
 		t.PhaseDescription = phaseDescriptions[t.Phase]
 		return nil
@@ -535,6 +709,50 @@ func (t *Task) next() error {
 	return nil
 }
 
+// Evaluate `all` flags.
+func (t *Task) allFlags(step Step) bool {
+	engine := t.getEngine()
+	if step.all&RsyncEngine != 0 && engine != migapi.TransferEngineRsync {
+		return false
+	}
+	if step.all&AgentEngine != 0 && engine != migapi.TransferEngineAgent {
+		return false
+	}
+	if step.all&Remote != 0 && t.Owner.IsIntraCluster() {
+		return false
+	}
+	if step.all&Local != 0 && !t.Owner.IsIntraCluster() {
+		return false
+	}
+	if step.all&Snapshot != 0 && !t.transferSpec().HasSnapshot() {
+		return false
+	}
+
+	return true
+}
+
+// Evaluate `any` flags.
+func (t *Task) anyFlags(step Step) bool {
+	engine := t.getEngine()
+	if step.any&RsyncEngine != 0 && engine == migapi.TransferEngineRsync {
+		return true
+	}
+	if step.any&AgentEngine != 0 && engine == migapi.TransferEngineAgent {
+		return true
+	}
+	if step.any&Remote != 0 && !t.Owner.IsIntraCluster() {
+		return true
+	}
+	if step.any&Local != 0 && t.Owner.IsIntraCluster() {
+		return true
+	}
+	if step.any&Snapshot != 0 && t.transferSpec().HasSnapshot() {
+		return true
+	}
+
+	return step.any == uint8(0)
+}
+
 // Phase fail.
 func (t *Task) fail(nextPhase string, reasons []string) {
 	t.addErrors(reasons)
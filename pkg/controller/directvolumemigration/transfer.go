@@ -0,0 +1,64 @@
+package directvolumemigration
+
+import (
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Get the transfer spec.
+// Returns an empty spec when not set.
+func (t *Task) transferSpec() *migapi.TransferSpec {
+	if t.Owner.Spec.Transfer == nil {
+		return &migapi.TransferSpec{}
+	}
+	return t.Owner.Spec.Transfer
+}
+
+// Get the engine transferring the volumes.
+// Intra-cluster volumes are always transferred by rsync.
+func (t *Task) getEngine() string {
+	if t.Owner.IsIntraCluster() {
+		return migapi.TransferEngineRsync
+	}
+	return t.transferSpec().GetEngine()
+}
+
+// Get the pod resources.
+// The resources set on the controller are overridden by the resources
+// set in the transfer spec.
+func getTransferPodResources(
+	client k8sclient.Client,
+	resources *corev1.ResourceRequirements,
+	cpuLimit, memoryLimit, cpuRequest, memoryRequest string) (corev1.ResourceList, corev1.ResourceList, error) {
+	limits, requests, err := getPodResourceLists(client, cpuLimit, memoryLimit, cpuRequest, memoryRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resources == nil {
+		return limits, requests, nil
+	}
+	for name, quantity := range resources.Limits {
+		limits[name] = quantity
+	}
+	for name, quantity := range resources.Requests {
+		requests[name] = quantity
+	}
+	return limits, requests, nil
+}
+
+// Apply the transfer scheduling to a pod.
+// The node selector is not applied to pods assigned to a node.
+func applyTransferScheduling(spec *corev1.PodSpec, scheduling *migapi.TransferScheduling) {
+	if scheduling == nil {
+		return
+	}
+	if spec.NodeName == "" && len(scheduling.NodeSelector) > 0 {
+		spec.NodeSelector = map[string]string{}
+		for k, v := range scheduling.NodeSelector {
+			spec.NodeSelector[k] = v
+		}
+	}
+	spec.Tolerations = append(spec.Tolerations, scheduling.Tolerations...)
+	spec.PriorityClassName = scheduling.PriorityClassName
+}
@@ -0,0 +1,110 @@
+package directvolumemigration
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	migsettings "github.com/konveyor/mig-controller/pkg/settings"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTask_mergeRsyncOptions(t *testing.T) {
+	bwLimit := 1024
+	disabled := false
+	defaults := migsettings.RsyncOpts{
+		BwLimit:   -1,
+		Archive:   true,
+		Partial:   true,
+		Delete:    true,
+		HardLinks: true,
+		Extras:    []string{"--numeric-ids"},
+	}
+	tests := []struct {
+		name     string
+		transfer *migapi.TransferSpec
+		want     migsettings.RsyncOpts
+	}{
+		{
+			name: "no transfer spec",
+			want: defaults,
+		},
+		{
+			name: "options overridden",
+			transfer: &migapi.TransferSpec{
+				Rsync: &migapi.RsyncOptions{
+					BwLimit: &bwLimit,
+					Delete:  &disabled,
+					Info:    "PROGRESS2",
+					Extras:  []string{"--sparse"},
+				},
+			},
+			want: migsettings.RsyncOpts{
+				BwLimit:   1024,
+				Archive:   true,
+				Partial:   true,
+				Delete:    false,
+				HardLinks: true,
+				Info:      "PROGRESS2",
+				Extras:    []string{"--numeric-ids", "--sparse"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					Spec: migapi.DirectVolumeMigrationSpec{Transfer: tt.transfer},
+				},
+			}
+			got := task.mergeRsyncOptions(defaults)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeRsyncOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if len(defaults.Extras) != 1 {
+		t.Errorf("mergeRsyncOptions() modified the controller options")
+	}
+}
+
+func TestTransferSpec_InvalidRsyncOptions(t *testing.T) {
+	bwLimit := -2
+	spec := &migapi.TransferSpec{
+		Rsync: &migapi.RsyncOptions{
+			BwLimit: &bwLimit,
+			Info:    "PROGRESS2;",
+			Extras:  []string{"--sparse", "--rsh=sh -c id", "--compress"},
+		},
+	}
+	want := []string{"--bwlimit=-2", "--info=PROGRESS2;", "--rsh=sh -c id"}
+	if got := spec.InvalidRsyncOptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("InvalidRsyncOptions() = %v, want %v", got, want)
+	}
+	var unset *migapi.TransferSpec
+	if got := unset.InvalidRsyncOptions(); len(got) != 0 {
+		t.Errorf("InvalidRsyncOptions() = %v, want none", got)
+	}
+}
+
+func Test_applyTransferScheduling(t *testing.T) {
+	scheduling := &migapi.TransferScheduling{
+		NodeSelector: map[string]string{"node-role": "transfer"},
+		Tolerations: []corev1.Toleration{
+			{Key: "transfer", Operator: corev1.TolerationOpExists},
+		},
+		PriorityClassName: "migration",
+	}
+	spec := corev1.PodSpec{}
+	applyTransferScheduling(&spec, scheduling)
+	if !reflect.DeepEqual(spec.NodeSelector, scheduling.NodeSelector) ||
+		!reflect.DeepEqual(spec.Tolerations, scheduling.Tolerations) ||
+		spec.PriorityClassName != "migration" {
+		t.Errorf("applyTransferScheduling() = %v", spec)
+	}
+	assigned := corev1.PodSpec{NodeName: "node-1"}
+	applyTransferScheduling(&assigned, scheduling)
+	if assigned.NodeSelector != nil {
+		t.Errorf("applyTransferScheduling() node selector set on assigned pod")
+	}
+}
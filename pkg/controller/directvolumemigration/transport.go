@@ -0,0 +1,410 @@
+package directvolumemigration
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/compat"
+	"github.com/konveyor/mig-controller/pkg/pods"
+	migsettings "github.com/konveyor/mig-controller/pkg/settings"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Ingress API.
+const (
+	IngressAPIVersion = "networking.k8s.io/v1"
+	IngressKind       = "Ingress"
+	IngressListKind   = "IngressList"
+)
+
+// Port of the stunnel listener of the rsync transfer pods.
+const rsyncTransferPort = 2222
+
+// The endpoint reached by the stunnel client pods.
+// Host - The host (or IP) connected.
+// Port - The port connected.
+// SNI - The (optional) TLS server name indication.
+type rsyncEndpoint struct {
+	Host string
+	Port int32
+	SNI  string
+}
+
+// The endpoint in <host>:<port> format.
+func (r rsyncEndpoint) String() string {
+	return net.JoinHostPort(r.Host, strconv.Itoa(int(r.Port)))
+}
+
+// Get the transport of the transfer spec.
+func (t *Task) transport() migapi.TransferTransport {
+	return t.transferSpec().GetTransport()
+}
+
+// Build the service exposing the rsync transfer pods for the transport.
+func (t *Task) buildRsyncTransferSvc(namespace string) corev1.Service {
+	dvmLabels := t.buildDVMLabels()
+	dvmLabels["purpose"] = DirectVolumeMigrationRsync
+	transport := t.transport()
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DirectVolumeMigrationRsyncTransferSvc,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": DirectVolumeMigrationRsyncTransfer,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       DirectVolumeMigrationStunnel,
+					Protocol:   corev1.ProtocolTCP,
+					Port:       int32(rsyncTransferPort),
+					TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: rsyncTransferPort},
+				},
+			},
+			Selector: dvmLabels,
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+	switch transport.Type {
+	case migapi.TransportLoadBalancer:
+		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+		svc.Annotations = transport.Annotations
+	case migapi.TransportNodePort:
+		svc.Spec.Type = corev1.ServiceTypeNodePort
+	}
+	return svc
+}
+
+// Build the Ingress exposing the rsync transfer service with TLS passthrough.
+// The class and the annotations enabling the passthrough default to the
+// controller settings and are overridden by the transport.
+func (t *Task) buildRsyncTransferIngress(namespace string) *unstructured.Unstructured {
+	transport := t.transport()
+	annotations := map[string]string{}
+	for k, v := range migsettings.Settings.IngressAnnotations {
+		annotations[k] = v
+	}
+	for k, v := range transport.Annotations {
+		annotations[k] = v
+	}
+	className := migsettings.Settings.IngressClassName
+	if transport.IngressClassName != "" {
+		className = transport.IngressClassName
+	}
+	ingress := &unstructured.Unstructured{}
+	ingress.SetAPIVersion(IngressAPIVersion)
+	ingress.SetKind(IngressKind)
+	ingress.SetName(DirectVolumeMigrationRsyncTransferRoute)
+	ingress.SetNamespace(namespace)
+	ingress.SetLabels(map[string]string{
+		"app": DirectVolumeMigrationRsyncTransfer,
+	})
+	ingress.SetAnnotations(annotations)
+	spec := map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{
+				"host": ingressHost(namespace, transport.IngressDomain),
+				"http": map[string]interface{}{
+					"paths": []interface{}{
+						map[string]interface{}{
+							"path":     "/",
+							"pathType": "Prefix",
+							"backend": map[string]interface{}{
+								"service": map[string]interface{}{
+									"name": DirectVolumeMigrationRsyncTransferSvc,
+									"port": map[string]interface{}{
+										"number": int64(rsyncTransferPort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if className != "" {
+		spec["ingressClassName"] = className
+	}
+	ingress.Object["spec"] = spec
+	return ingress
+}
+
+// Get the Ingress host of a namespace.
+// The host label must not exceed 63 chars.
+func ingressHost(namespace, domain string) string {
+	prefix := fmt.Sprintf("%s-%s", DirectVolumeMigrationRsyncTransferRoute, getMD5Hash(namespace))
+	if len(prefix) > 63 {
+		prefix = prefix[0:63]
+	}
+	return fmt.Sprintf("%s.%s", prefix, domain)
+}
+
+// Create the rsync transport on the destination cluster.
+// Routes are created by createRsyncTransferRoute(), other transports
+// expose the rsync transfer service directly or through an Ingress.
+func (t *Task) createRsyncTransport() error {
+	transport := t.transport()
+	if transport.Type == migapi.TransportRoute {
+		return t.createRsyncTransferRoute()
+	}
+	destClient, err := t.getDestinationClient()
+	if err != nil {
+		return err
+	}
+	pvcMap := t.getPVCNamespaceMap()
+	for ns, _ := range pvcMap {
+		svc := t.buildRsyncTransferSvc(ns)
+		err = destClient.Create(context.TODO(), &svc)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Rsync transfer svc already exists on destination", "namespace", ns)
+		} else if err != nil {
+			return err
+		}
+		if transport.Type != migapi.TransportIngress {
+			continue
+		}
+		ingress := t.buildRsyncTransferIngress(ns)
+		err = destClient.Create(context.TODO(), ingress)
+		if k8serror.IsAlreadyExists(err) {
+			t.Log.Info("Rsync transfer ingress already exists on destination", "namespace", ns)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get whether the rsync transport is ready.
+// Routes are ready when admitted and LoadBalancer services are ready
+// when an address is assigned. Other transports are ready when created.
+// Returns the reasons the transport is not ready.
+func (t *Task) isRsyncTransportReady() (bool, []string, error) {
+	transport := t.transport()
+	switch transport.Type {
+	case migapi.TransportRoute:
+		return t.areRsyncRoutesAdmitted()
+	case migapi.TransportLoadBalancer:
+		destClient, err := t.getDestinationClient()
+		if err != nil {
+			return false, nil, err
+		}
+		messages := []string{}
+		for ns, _ := range t.getPVCNamespaceMap() {
+			svc, err := t.getRsyncTransferSvc(destClient, ns)
+			if err != nil {
+				return false, nil, err
+			}
+			if len(svc.Status.LoadBalancer.Ingress) == 0 {
+				messages = append(
+					messages,
+					fmt.Sprintf("no load balancer address assigned to service %s/%s", svc.Namespace, svc.Name))
+			}
+		}
+		return len(messages) == 0, messages, nil
+	}
+	return true, []string{}, nil
+}
+
+// Get the rsync transfer service on the destination cluster.
+func (t *Task) getRsyncTransferSvc(client compat.Client, namespace string) (*corev1.Service, error) {
+	svc := corev1.Service{}
+	key := types.NamespacedName{Name: DirectVolumeMigrationRsyncTransferSvc, Namespace: namespace}
+	err := client.Get(context.TODO(), key, &svc)
+	if err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
+
+// Get the endpoint reached by the stunnel client pod of a namespace.
+func (t *Task) getRsyncEndpoint(namespace string) (*rsyncEndpoint, error) {
+	transport := t.transport()
+	switch transport.Type {
+	case migapi.TransportRoute:
+		host, err := t.getRsyncRoute(namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &rsyncEndpoint{Host: host, Port: 443}, nil
+	case migapi.TransportIngress:
+		host := ingressHost(namespace, transport.IngressDomain)
+		endpoint := &rsyncEndpoint{Host: host, Port: 443, SNI: host}
+		if transport.IngressAddress != "" {
+			endpoint.Host = transport.IngressAddress
+		}
+		return endpoint, nil
+	case migapi.TransportEndpoint:
+		return parseRsyncEndpoint(transport.Endpoints[namespace])
+	}
+	destClient, err := t.getDestinationClient()
+	if err != nil {
+		return nil, err
+	}
+	svc, err := t.getRsyncTransferSvc(destClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if transport.Type == migapi.TransportNodePort {
+		address := transport.NodeAddress
+		if address == "" {
+			address, err = t.getDestinationNodeAddress(destClient)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return svcNodePortEndpoint(svc, address)
+	}
+	return svcLoadBalancerEndpoint(svc)
+}
+
+// Parse a <host>:<port> endpoint.
+func parseRsyncEndpoint(endpoint string) (*rsyncEndpoint, error) {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	return &rsyncEndpoint{Host: host, Port: int32(n)}, nil
+}
+
+// Get the endpoint of a LoadBalancer service.
+func svcLoadBalancerEndpoint(svc *corev1.Service) (*rsyncEndpoint, error) {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		host := ingress.IP
+		if host == "" {
+			host = ingress.Hostname
+		}
+		if host != "" {
+			return &rsyncEndpoint{Host: host, Port: int32(rsyncTransferPort)}, nil
+		}
+	}
+	return nil, fmt.Errorf("no load balancer address assigned to service %s/%s", svc.Namespace, svc.Name)
+}
+
+// Get the endpoint of a NodePort service.
+func svcNodePortEndpoint(svc *corev1.Service, address string) (*rsyncEndpoint, error) {
+	for _, port := range svc.Spec.Ports {
+		if port.NodePort != 0 {
+			return &rsyncEndpoint{Host: address, Port: port.NodePort}, nil
+		}
+	}
+	return nil, fmt.Errorf("no node port assigned to service %s/%s", svc.Namespace, svc.Name)
+}
+
+// Get the address of a ready destination node.
+// The external address is preferred over the internal address.
+func (t *Task) getDestinationNodeAddress(client compat.Client) (string, error) {
+	nodes := corev1.NodeList{}
+	err := client.List(context.TODO(), &k8sclient.ListOptions{}, &nodes)
+	if err != nil {
+		return "", err
+	}
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP} {
+		for _, node := range nodes.Items {
+			if !isNodeReady(&node) {
+				continue
+			}
+			for _, address := range node.Status.Addresses {
+				if address.Type == addressType && address.Address != "" {
+					return address.Address, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("no ready node with an address found on the destination cluster")
+}
+
+// Get whether a node is ready.
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Get whether the rsync daemons can be reached through the
+// stunnel client pods on the source cluster.
+// The rsync modules are listed from the stunnel client pods.
+// Returns the reasons the rsync daemons cannot be reached.
+func (t *Task) isRsyncTransportReachable() (bool, []string, error) {
+	srcClient, err := t.getSourceClient()
+	if err != nil {
+		return false, nil, err
+	}
+	messages := []string{}
+	for ns, _ := range t.getPVCNamespaceMap() {
+		pod := corev1.Pod{}
+		key := types.NamespacedName{Name: DirectVolumeMigrationStunnelTransfer, Namespace: ns}
+		err = srcClient.Get(context.TODO(), key, &pod)
+		if err != nil {
+			return false, nil, err
+		}
+		command := pods.PodCommand{
+			RestCfg:   srcClient.RestConfig(),
+			Pod:       &pod,
+			Container: DirectVolumeMigrationStunnel,
+			Args: []string{
+				"rsync",
+				"--contimeout=10",
+				"--timeout=10",
+				fmt.Sprintf("rsync://localhost:%d/", rsyncTransferPort),
+			},
+		}
+		err = command.Run()
+		if err != nil {
+			messages = append(
+				messages,
+				fmt.Sprintf("namespace %s: %s %s", ns, err.Error(), strings.TrimSpace(command.Err.String())))
+		}
+	}
+	return len(messages) == 0, messages, nil
+}
+
+// Get the rsync transfer Ingresses.
+// No Ingresses are found when the API is not served by the cluster.
+func listRsyncTransferIngresses(client compat.Client, options *k8sclient.ListOptions) ([]unstructured.Unstructured, error) {
+	list := unstructured.UnstructuredList{}
+	list.SetAPIVersion(IngressAPIVersion)
+	list.SetKind(IngressListKind)
+	err := client.List(context.TODO(), options, &list)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// Get the rsync transfer Routes.
+// No Routes are found when the API is not served by the cluster.
+func listRsyncTransferRoutes(client compat.Client, options *k8sclient.ListOptions) ([]routev1.Route, error) {
+	list := routev1.RouteList{}
+	err := client.List(context.TODO(), options, &list)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -0,0 +1,227 @@
+package directvolumemigration
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	migsettings "github.com/konveyor/mig-controller/pkg/settings"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestStunnelClientConfigTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config stunnelConfig
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "route",
+			config: stunnelConfig{StunnelPort: 2222, Endpoint: "dvm-ns.apps.example.com:443"},
+			want:   []string{"    connect = dvm-ns.apps.example.com:443\n    verify = 2\n"},
+			absent: []string{"sni"},
+		},
+		{
+			name: "ingress",
+			config: stunnelConfig{
+				StunnelPort: 2222,
+				Endpoint:    "10.0.0.1:443",
+				SNI:         "dvm-ns.example.com",
+			},
+			want: []string{"    connect = 10.0.0.1:443\n    sni = dvm-ns.example.com\n    verify = 2\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl := template.Must(template.New("config").Parse(stunnelClientConfigTemplate))
+			out := bytes.Buffer{}
+			if err := tpl.Execute(&out, tt.config); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("config = %s, want %q", out.String(), want)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(out.String(), absent) {
+					t.Errorf("config = %s, unexpected %q", out.String(), absent)
+				}
+			}
+		})
+	}
+}
+
+func TestTransferSpec_InvalidTransport(t *testing.T) {
+	namespaces := []string{"app", "db"}
+	tests := []struct {
+		name      string
+		transport *migapi.TransferTransport
+		want      []string
+	}{
+		{
+			name: "default route",
+			want: []string{},
+		},
+		{
+			name:      "unknown type",
+			transport: &migapi.TransferTransport{Type: "Tunnel"},
+			want:      []string{"type: Tunnel not supported"},
+		},
+		{
+			name:      "ingress without domain",
+			transport: &migapi.TransferTransport{Type: migapi.TransportIngress},
+			want:      []string{"ingressDomain: not set"},
+		},
+		{
+			name: "endpoints",
+			transport: &migapi.TransferTransport{
+				Type:      migapi.TransportEndpoint,
+				Endpoints: map[string]string{"app": "rsync.example.com"},
+			},
+			want: []string{
+				"endpoints: rsync.example.com not <host>:<port>",
+				"endpoints: db not set",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &migapi.TransferSpec{Transport: tt.transport}
+			if got := spec.InvalidTransport(namespaces); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvalidTransport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_buildRsyncTransferSvc(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport *migapi.TransferTransport
+		want      corev1.ServiceType
+	}{
+		{name: "route", want: corev1.ServiceTypeClusterIP},
+		{
+			name:      "load balancer",
+			transport: &migapi.TransferTransport{Type: migapi.TransportLoadBalancer},
+			want:      corev1.ServiceTypeLoadBalancer,
+		},
+		{
+			name:      "node port",
+			transport: &migapi.TransferTransport{Type: migapi.TransportNodePort},
+			want:      corev1.ServiceTypeNodePort,
+		},
+		{
+			name:      "endpoint",
+			transport: &migapi.TransferTransport{Type: migapi.TransportEndpoint},
+			want:      corev1.ServiceTypeClusterIP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					Spec: migapi.DirectVolumeMigrationSpec{
+						Transfer: &migapi.TransferSpec{Transport: tt.transport},
+					},
+				},
+			}
+			if got := task.buildRsyncTransferSvc("ns").Spec.Type; got != tt.want {
+				t.Errorf("buildRsyncTransferSvc() type = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_buildRsyncTransferIngress(t *testing.T) {
+	settings := migsettings.Settings.DvmOpts
+	defer func() { migsettings.Settings.DvmOpts = settings }()
+	migsettings.Settings.IngressClassName = "nginx"
+	migsettings.Settings.IngressAnnotations = map[string]string{
+		"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+	}
+	tests := []struct {
+		name            string
+		transport       *migapi.TransferTransport
+		wantClass       string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:      "settings",
+			transport: &migapi.TransferTransport{Type: migapi.TransportIngress},
+			wantClass: "nginx",
+			wantAnnotations: map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+			},
+		},
+		{
+			name: "transport overrides",
+			transport: &migapi.TransferTransport{
+				Type:             migapi.TransportIngress,
+				IngressClassName: "haproxy",
+				Annotations: map[string]string{
+					"nginx.ingress.kubernetes.io/ssl-passthrough": "false",
+					"haproxy.org/ssl-passthrough":                 "true",
+				},
+			},
+			wantClass: "haproxy",
+			wantAnnotations: map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-passthrough": "false",
+				"haproxy.org/ssl-passthrough":                 "true",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.DirectVolumeMigration{
+					Spec: migapi.DirectVolumeMigrationSpec{
+						Transfer: &migapi.TransferSpec{Transport: tt.transport},
+					},
+				},
+			}
+			ingress := task.buildRsyncTransferIngress("ns")
+			class, _, _ := unstructured.NestedString(ingress.Object, "spec", "ingressClassName")
+			if class != tt.wantClass {
+				t.Errorf("buildRsyncTransferIngress() class = %v, want %v", class, tt.wantClass)
+			}
+			if !reflect.DeepEqual(ingress.GetAnnotations(), tt.wantAnnotations) {
+				t.Errorf("buildRsyncTransferIngress() annotations = %v, want %v", ingress.GetAnnotations(), tt.wantAnnotations)
+			}
+		})
+	}
+}
+
+func TestSvcEndpoints(t *testing.T) {
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Port: 2222, NodePort: 30222}},
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			},
+		},
+	}
+	endpoint, err := svcNodePortEndpoint(svc, "10.0.0.1")
+	if err != nil || endpoint.String() != "10.0.0.1:30222" {
+		t.Errorf("svcNodePortEndpoint() = %v, %v", endpoint, err)
+	}
+	endpoint, err = svcLoadBalancerEndpoint(svc)
+	if err != nil || endpoint.String() != "lb.example.com:2222" {
+		t.Errorf("svcLoadBalancerEndpoint() = %v, %v", endpoint, err)
+	}
+	if _, err = svcLoadBalancerEndpoint(&corev1.Service{}); err == nil {
+		t.Errorf("svcLoadBalancerEndpoint() error = nil, want error")
+	}
+	endpoint, err = parseRsyncEndpoint("[fd00::1]:2222")
+	if err != nil || endpoint.Host != "fd00::1" || endpoint.String() != "[fd00::1]:2222" {
+		t.Errorf("parseRsyncEndpoint() = %v, %v", endpoint, err)
+	}
+}
@@ -2,9 +2,11 @@ package directvolumemigration
 
 import (
 	"context"
+	"fmt"
 	liberr "github.com/konveyor/controller/pkg/error"
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	migref "github.com/konveyor/mig-controller/pkg/reference"
+	migsettings "github.com/konveyor/mig-controller/pkg/settings"
 	kapi "k8s.io/api/core/v1"
 	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -29,6 +31,20 @@ const (
 	RsyncClientPodsPending          = "RsyncClientPodsPending"
 	Succeeded                       = "Succeeded"
 	SourceToDestinationNetworkError = "SourceToDestinationNetworkError"
+	InvalidTransferSpec             = "InvalidTransferSpec"
+	InvalidTransport                = "InvalidTransport"
+	InvalidTLS                      = "InvalidTLS"
+	InvalidTransferEngine           = "InvalidTransferEngine"
+	InvalidTransferConcurrency      = "InvalidTransferConcurrency"
+	InvalidBandwidthSchedule        = "InvalidBandwidthSchedule"
+	InvalidTransferRetry            = "InvalidTransferRetry"
+	InvalidPVCTargets               = "InvalidPVCTargets"
+	SourceSnapshotsNotReady         = "SourceSnapshotsNotReady"
+	RsyncTransportNotReachable      = "RsyncTransportNotReachable"
+	TransferAgentImageNotFound      = "TransferAgentImageNotFound"
+	VolumeSnapshotAPINotFound       = "VolumeSnapshotAPINotFound"
+	SourceSnapshotsTimedOut         = "SourceSnapshotsTimedOut"
+	LocalTransferNotEnabled         = "LocalTransferNotEnabled"
 )
 
 // Reasons
@@ -51,6 +67,19 @@ const (
 	SourceClusterNotReadyMessage              = "The source cluster is not ready"
 	DestinationClusterNotReadyMessage         = "The destination cluster is not ready"
 	PVCsNotFoundOnSourceClusterMessage        = "The set of pvcs were not found on source cluster"
+	InvalidTransferSpecMessage                = "The rsync options [] of the transfer spec are not valid"
+	InvalidTransportMessage                   = "The transport of the transfer spec is not valid: []"
+	InvalidTLSMessage                         = "The TLS settings of the transfer spec are not valid: []"
+	InvalidTransferEngineMessage              = "The transfer engine of the transfer spec is not valid: []"
+	InvalidTransferConcurrencyMessage         = "The concurrency of the transfer spec is not valid: []"
+	InvalidBandwidthScheduleMessage           = "The bandwidth windows of the transfer spec are not valid: []"
+	InvalidTransferRetryMessage               = "The retry policy of the transfer spec is not valid: []"
+	InvalidPVCTargetsMessage                  = "The destination of the persistent volume claims is not valid: []"
+	SourceSnapshotsNotReadyMessage            = "The snapshots of the source persistent volume claims are not ready: []"
+	TransferAgentImageNotFoundMessage         = "The transfer agent image is not configured on the clusters: []"
+	VolumeSnapshotAPINotFoundMessage          = "The VolumeSnapshot API " + VolumeSnapshotAPIVersion + " is not served by the source cluster"
+	SourceSnapshotsTimedOutMessage            = "The snapshots of the source persistent volume claims are not ready within %s: []"
+	LocalTransferNotEnabledMessage            = "Volumes within a cluster are transferred by a plaintext rsync daemon, which must be enabled by " + migsettings.LocalPlaintext
 	SucceededMessage                          = "The migration has succeeded"
 	FailedMessage                             = "The migration has failed.  See: Errors."
 )
@@ -82,6 +111,102 @@ func (r ReconcileDirectVolumeMigration) validate(direct *migapi.DirectVolumeMigr
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	r.validatePVCTargets(direct)
+	r.validateLocalTransfer(direct)
+	r.validateTransferSpec(direct)
+	err = r.validateTLS(direct)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = r.validateTransferAgentImage(direct)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = r.validateVolumeSnapshotAPI(direct)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	return nil
+}
+
+// Validate the VolumeSnapshot API is served by the source cluster
+// when the volumes are transferred from snapshots.
+func (r ReconcileDirectVolumeMigration) validateVolumeSnapshotAPI(direct *migapi.DirectVolumeMigration) error {
+	if !direct.Spec.Transfer.HasSnapshot() {
+		return nil
+	}
+	if direct.Status.HasBlockerCondition() {
+		return nil
+	}
+	cluster, err := migapi.GetCluster(r, direct.Spec.SrcMigClusterRef)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if cluster == nil {
+		return nil
+	}
+	client, err := cluster.GetClient(r)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	served, err := isVolumeSnapshotAPIServed(client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if !served {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     VolumeSnapshotAPINotFound,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Critical,
+			Message:  VolumeSnapshotAPINotFoundMessage,
+		})
+	}
+	return nil
+}
+
+// Validate the transfer agent image is configured on both clusters
+// when the volumes are transferred by the agent.
+func (r ReconcileDirectVolumeMigration) validateTransferAgentImage(direct *migapi.DirectVolumeMigration) error {
+	if direct.Spec.Transfer.GetEngine() != migapi.TransferEngineAgent || direct.IsIntraCluster() {
+		return nil
+	}
+	if direct.Status.HasBlockerCondition() {
+		return nil
+	}
+	invalid := []string{}
+	for _, ref := range []*kapi.ObjectReference{direct.Spec.SrcMigClusterRef, direct.Spec.DestMigClusterRef} {
+		cluster, err := migapi.GetCluster(r, ref)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if cluster == nil {
+			continue
+		}
+		client, err := cluster.GetClient(r)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		clusterConfig, err := cluster.GetClusterConfigMap(client)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if clusterConfig.Data[migapi.TransferAgentImageKey] == "" {
+			invalid = append(
+				invalid,
+				fmt.Sprintf("%s (%s not set in %s)", cluster.Name, migapi.TransferAgentImageKey, migapi.ClusterConfigMapName))
+		}
+	}
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     TransferAgentImageNotFound,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Critical,
+			Message:  TransferAgentImageNotFoundMessage,
+			Items:    invalid,
+		})
+	}
 	return nil
 }
 
@@ -251,3 +376,126 @@ func (r ReconcileDirectVolumeMigration) validatePVCs(direct *migapi.DirectVolume
 	}
 	return nil
 }
+
+// Validate the destination namespaces and names of the PVCs.
+func (r ReconcileDirectVolumeMigration) validatePVCTargets(direct *migapi.DirectVolumeMigration) {
+	invalid := direct.InvalidTargets()
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidPVCTargets,
+			Status:   True,
+			Reason:   NotDistinct,
+			Category: Critical,
+			Message:  InvalidPVCTargetsMessage,
+			Items:    invalid,
+		})
+	}
+}
+
+// Validate the plaintext transfer within the cluster is enabled.
+func (r ReconcileDirectVolumeMigration) validateLocalTransfer(direct *migapi.DirectVolumeMigration) {
+	if !direct.IsIntraCluster() || migsettings.Settings.DvmOpts.LocalPlaintext {
+		return
+	}
+	direct.Status.SetCondition(migapi.Condition{
+		Type:     LocalTransferNotEnabled,
+		Status:   True,
+		Reason:   NotSet,
+		Category: Critical,
+		Message:  LocalTransferNotEnabledMessage,
+	})
+}
+
+func (r ReconcileDirectVolumeMigration) validateTransferSpec(direct *migapi.DirectVolumeMigration) {
+	invalid := direct.Spec.Transfer.InvalidRsyncOptions()
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferSpec,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  InvalidTransferSpecMessage,
+			Items:    invalid,
+		})
+	}
+	namespaces := []string{}
+	for _, pvc := range direct.Spec.PersistentVolumeClaims {
+		namespaces = append(namespaces, pvc.Namespace)
+	}
+	invalid = direct.Spec.Transfer.InvalidTransport(namespaces)
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransport,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  InvalidTransportMessage,
+			Items:    invalid,
+		})
+	}
+	invalid = direct.Spec.Transfer.InvalidEngine()
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferEngine,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  InvalidTransferEngineMessage,
+			Items:    invalid,
+		})
+	}
+	invalid = direct.Spec.Transfer.InvalidConcurrency()
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferConcurrency,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  InvalidTransferConcurrencyMessage,
+			Items:    invalid,
+		})
+	}
+	invalid = direct.Spec.Transfer.InvalidBandwidth()
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidBandwidthSchedule,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  InvalidBandwidthScheduleMessage,
+			Items:    invalid,
+		})
+	}
+	invalid = direct.Spec.Transfer.InvalidRetry()
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferRetry,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  InvalidTransferRetryMessage,
+			Items:    invalid,
+		})
+	}
+}
+
+// Validate the TLS settings and the referenced CA secret.
+func (r ReconcileDirectVolumeMigration) validateTLS(direct *migapi.DirectVolumeMigration) error {
+	invalid := direct.Spec.Transfer.InvalidTLS()
+	invalidSecret, err := direct.Spec.Transfer.InvalidCASecret(r)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	invalid = append(invalid, invalidSecret...)
+	if len(invalid) > 0 {
+		direct.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTLS,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  InvalidTLSMessage,
+			Items:    invalid,
+		})
+	}
+	return nil
+}
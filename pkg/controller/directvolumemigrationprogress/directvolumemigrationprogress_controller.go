@@ -18,8 +18,10 @@ package directvolumemigrationprogress
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/konveyor/mig-controller/pkg/errorutil"
+	"github.com/konveyor/mig-controller/pkg/transfer"
 	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -68,6 +70,12 @@ const (
 	ContainerCreating = "ContainerCreating"
 )
 
+// Containers transferring the volume.
+const (
+	RsyncClientContainer = "rsync-client"
+	AgentClientContainer = "agent-client"
+)
+
 // Add creates a new DirectVolumeMigrationProgress Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
@@ -149,7 +157,7 @@ func (r *ReconcileDirectVolumeMigrationProgress) Reconcile(request reconcile.Req
 	// Begin staging conditions.
 	pvProgress.Status.BeginStagingConditions()
 
-	err = r.reportContainerStatus(pvProgress, "rsync-client")
+	err = r.reportContainerStatus(pvProgress, clientContainer(pvProgress))
 	if err != nil {
 		return reconcile.Result{Requeue: true}, liberr.Wrap(err)
 	}
@@ -224,6 +232,12 @@ func (r *ReconcileDirectVolumeMigrationProgress) reportContainerStatus(pvProgres
 
 	pod, err := r.Pod(cluster, podRef)
 	switch {
+	case errors.IsNotFound(err) && pvProgress.Status.QueuePosition > 0:
+		// The pod is created when the transfer leaves the DVM queue.
+		pvProgress.Status.PodPhase = ""
+		pvProgress.Status.LogMessage = fmt.Sprintf("The transfer is queued at position %d",
+			pvProgress.Status.QueuePosition)
+		return nil
 	case errors.IsNotFound(err):
 		// handle not found and return
 		pvProgress.Status.SetCondition(migapi.Condition{
@@ -237,6 +251,12 @@ func (r *ReconcileDirectVolumeMigrationProgress) reportContainerStatus(pvProgres
 		return nil
 	case err != nil:
 		return liberr.Wrap(err)
+	case pod.DeletionTimestamp != nil && pvProgress.Status.QueuePosition > 0:
+		// The pod is restarted by the DVM at a bandwidth window boundary.
+		pvProgress.Status.PodPhase = ""
+		pvProgress.Status.LogMessage = fmt.Sprintf("The transfer is restarting, queued at position %d",
+			pvProgress.Status.QueuePosition)
+		return nil
 	}
 
 	var containerStatus *kapi.ContainerStatus
@@ -269,11 +289,24 @@ func (r *ReconcileDirectVolumeMigrationProgress) reportContainerStatus(pvProgres
 		// report pod running and return
 		pvProgress.Status.PodPhase = kapi.PodRunning
 		numberOfLogLines := int64(5)
-		logMessage, err := r.GetPodLogs(cluster, podRef, &numberOfLogLines, false)
+		logMessage, err := r.GetPodLogs(cluster, podRef, containerName, &numberOfLogLines, false)
 		if err != nil {
 			return err
 		}
 		pvProgress.Status.LogMessage = logMessage
+		if pvProgress.Spec.Engine == migapi.TransferEngineAgent {
+			progress, err := r.GetAgentProgress(cluster, podRef)
+			if err != nil {
+				// The progress is reported on the next reconcile.
+				log.Info("Transfer agent progress not available.",
+					"pod", path.Join(podRef.Namespace, podRef.Name),
+					"error", err.Error())
+			} else {
+				setAgentProgress(pvProgress, progress)
+			}
+			pvProgress.Status.ContainerElapsedTime = nil
+			break
+		}
 		percentProgress := r.GetProgressPercent(logMessage)
 		if percentProgress != "" {
 			pvProgress.Status.LastObservedProgressPercent = percentProgress
@@ -339,7 +372,7 @@ func (r *ReconcileDirectVolumeMigrationProgress) Pod(cluster *migapi.MigCluster,
 	return pod, nil
 }
 
-func (r *ReconcileDirectVolumeMigrationProgress) GetPodLogs(cluster *migapi.MigCluster, podReference *kapi.ObjectReference, tailLines *int64, previous bool) (string, error) {
+func (r *ReconcileDirectVolumeMigrationProgress) GetPodLogs(cluster *migapi.MigCluster, podReference *kapi.ObjectReference, containerName string, tailLines *int64, previous bool) (string, error) {
 
 	config, err := cluster.BuildRestConfig(r.Client)
 	if err != nil {
@@ -354,7 +387,7 @@ func (r *ReconcileDirectVolumeMigrationProgress) GetPodLogs(cluster *migapi.MigC
 	req := clientset.CoreV1().Pods(podReference.Namespace).GetLogs(podReference.Name, &kapi.PodLogOptions{
 		TailLines: tailLines,
 		Previous:  previous,
-		Container: "rsync-client",
+		Container: containerName,
 	})
 	readCloser, err := req.Stream()
 	if err != nil {
@@ -366,6 +399,56 @@ func (r *ReconcileDirectVolumeMigrationProgress) GetPodLogs(cluster *migapi.MigC
 	return parseLogs(readCloser)
 }
 
+// Get the progress reported by the transfer agent client pod.
+// The progress API is reached through the API server pod proxy.
+func (r *ReconcileDirectVolumeMigrationProgress) GetAgentProgress(cluster *migapi.MigCluster, podReference *kapi.ObjectReference) (*transfer.Progress, error) {
+	config, err := cluster.BuildRestConfig(r.Client)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	body, err := clientset.CoreV1().RESTClient().Get().
+		Namespace(podReference.Namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", podReference.Name, transfer.ProgressPort)).
+		SubResource("proxy").
+		Suffix(transfer.ProgressPath).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	return parseAgentProgress(body)
+}
+
+// Get the name of the container transferring the volume.
+func clientContainer(pvProgress *migapi.DirectVolumeMigrationProgress) string {
+	if pvProgress.Spec.Engine == migapi.TransferEngineAgent {
+		return AgentClientContainer
+	}
+	return RsyncClientContainer
+}
+
+// Parse the progress reported by the transfer agent.
+func parseAgentProgress(body []byte) (*transfer.Progress, error) {
+	progress := &transfer.Progress{}
+	err := json.Unmarshal(body, progress)
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// Set the status with the progress reported by the transfer agent.
+func setAgentProgress(pvProgress *migapi.DirectVolumeMigrationProgress, progress *transfer.Progress) {
+	pvProgress.Status.LastObservedProgressPercent = progress.Percent()
+	pvProgress.Status.LastObservedTransferRate = progress.Rate()
+	pvProgress.Status.TotalBytes = &progress.TotalBytes
+	pvProgress.Status.TransferredBytes = &progress.TransferredBytes
+}
+
 func (r *ReconcileDirectVolumeMigrationProgress) GetProgressPercent(message string) string {
 	return GetLastMatch(`\d+\%`, message)
 }
@@ -145,3 +145,55 @@ func Test_parseLogs(t *testing.T) {
 		})
 	}
 }
+
+func Test_setAgentProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantPercent string
+		wantRate    string
+		wantErr     bool
+	}{
+		{
+			name:        "running",
+			body:        `{"totalBytes":4000,"transferredBytes":1000,"sentBytes":1000,"bytesPerSecond":2500000}`,
+			wantPercent: "25%",
+			wantRate:    "2.50MB/s",
+		},
+		{
+			name:        "completed empty volume",
+			body:        `{"totalBytes":0,"completed":true}`,
+			wantPercent: "100%",
+			wantRate:    "0.00B/s",
+		},
+		{
+			name:    "not json",
+			body:    `404 page not found`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress, err := parseAgentProgress([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAgentProgress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			pvProgress := &migrationv1alpha1.DirectVolumeMigrationProgress{}
+			setAgentProgress(pvProgress, progress)
+			if pvProgress.Status.LastObservedProgressPercent != tt.wantPercent ||
+				pvProgress.Status.LastObservedTransferRate != tt.wantRate {
+				t.Errorf("setAgentProgress() = %s %s, want %s %s",
+					pvProgress.Status.LastObservedProgressPercent,
+					pvProgress.Status.LastObservedTransferRate,
+					tt.wantPercent,
+					tt.wantRate)
+			}
+			if *pvProgress.Status.TotalBytes != progress.TotalBytes {
+				t.Errorf("setAgentProgress() totalBytes = %d", *pvProgress.Status.TotalBytes)
+			}
+		})
+	}
+}
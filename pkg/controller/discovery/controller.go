@@ -181,6 +181,14 @@ func (r *ReconcileDiscovery) IsValid(cluster *migapi.MigCluster) bool {
 	if cluster.Spec.IsHostCluster {
 		return true
 	}
+	if cluster.UsesKubeconfig() {
+		kubeconfig, err := cluster.GetKubeconfig(r.client)
+		if err != nil {
+			log.Trace(err)
+			return false
+		}
+		return kubeconfig != nil
+	}
 	ref := cluster.Spec.ServiceAccountSecretRef
 	if ref == nil {
 		return false
@@ -225,7 +233,10 @@ func (r ClusterPredicate) Update(e event.UpdateEvent) bool {
 	}
 	changed := o.Spec.URL != n.Spec.URL ||
 		!reflect.DeepEqual(o.Spec.ServiceAccountSecretRef, n.Spec.ServiceAccountSecretRef) ||
-		!reflect.DeepEqual(o.Spec.CABundle, n.Spec.CABundle)
+		!reflect.DeepEqual(o.Spec.CABundle, n.Spec.CABundle) ||
+		!reflect.DeepEqual(o.Spec.KubeconfigSecretRef, n.Spec.KubeconfigSecretRef) ||
+		o.Spec.KubeconfigContext != n.Spec.KubeconfigContext ||
+		o.Spec.ProxyURL != n.Spec.ProxyURL
 	return changed
 }
 
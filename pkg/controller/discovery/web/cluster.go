@@ -60,7 +60,7 @@ func (h *ClusterScoped) Prepare(ctx *gin.Context) int {
 // be simplified.
 func (h *ClusterScoped) getSAR() auth.SelfSubjectAccessReview {
 	var attributes *auth.ResourceAttributes
-	sr := h.cluster.DecodeObject().Spec.ServiceAccountSecretRef
+	sr := h.cluster.DecodeObject().GetCredentialsSecretRef()
 	if sr != nil {
 		attributes = &auth.ResourceAttributes{
 			Group:     "apps",
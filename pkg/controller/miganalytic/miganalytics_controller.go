@@ -30,10 +30,12 @@ import (
 	"github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	"github.com/konveyor/mig-controller/pkg/compat"
 	"github.com/konveyor/mig-controller/pkg/gvk"
+	"github.com/konveyor/mig-controller/pkg/resourcefilter"
 	"github.com/openshift/api/image/docker10"
 	"github.com/openshift/library-go/pkg/image/reference"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -240,6 +242,11 @@ func (r *ReconcileMigAnalytic) analyze(analytic *migapi.MigAnalytic) error {
 		return liberr.Wrap(err)
 	}
 
+	filter, err := resourcefilter.New(plan.Spec.ResourceFilters)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
 	nodeToPVMap := make(map[string][]MigAnalyticPersistentVolumeDetails)
 
 	analytic.Status.Analytics.Plan = plan.Name
@@ -255,23 +262,22 @@ func (r *ReconcileMigAnalytic) analyze(analytic *migapi.MigAnalytic) error {
 		}
 
 		excludedResources := plan.Status.ExcludedResources
-		incompatible := plan.Status.Incompatible
 
 		if analytic.Spec.AnalyzeK8SResources {
-			err := r.analyzeK8SResources(dynamic, resources, &ns, excludedResources, incompatible)
+			err := r.analyzeK8SResources(dynamic, resources, &ns, plan, filter)
 			if err != nil {
 				return liberr.Wrap(err)
 			}
 		}
-		if analytic.Spec.AnalyzeImageCount && !isExcluded("imagestreams", excludedResources) {
+		if analytic.Spec.AnalyzeImageCount && !isExcluded(imageStreamsResource, excludedResources) {
 			err := r.analyzeImages(client, &ns, analytic.Spec.ListImages, analytic.Spec.ListImagesLimit)
 			if err != nil {
 				return liberr.Wrap(err)
 			}
 		}
 
-		if analytic.Spec.AnalyzePVCapacity && !(isExcluded("persistentvolumes", excludedResources) &&
-			isExcluded("persistentvolumeclaims", excludedResources)) {
+		if analytic.Spec.AnalyzePVCapacity && !(isExcluded(pvResource, excludedResources) &&
+			isExcluded(pvcResource, excludedResources)) {
 
 			err := r.analyzePVCapacity(client, &ns)
 			if err != nil {
@@ -395,13 +401,19 @@ func (r *ReconcileMigAnalytic) getNodeToPVCMapForNS(ns *migapi.MigAnalyticNamesp
 	return nodeToPVDetails, nil
 }
 
+// Count the objects in a namespace by resource.
+// Objects are counted as excluded when the resource is excluded by the plan
+// or the object is not selected by the plan resource filters.
 func (r *ReconcileMigAnalytic) analyzeK8SResources(dynamic dynamic.Interface,
 	resources []*metav1.APIResourceList,
 	ns *migapi.MigAnalyticNamespace,
-	excludedResources []string, incompatible v1alpha1.Incompatible) error {
+	plan *migapi.MigPlan,
+	filter *resourcefilter.Filter) error {
 	gvk.SortResources(resources)
 	cohabitatingResources := gvk.NewCohabitatingResources()
 
+	GVRs := []schema.GroupVersionResource{}
+	lists := map[schema.GroupVersionResource][]unstructured.Unstructured{}
 	for _, res := range resources {
 		for _, r := range res.APIResources {
 			// skip resource if we have already handle it via an alternate group
@@ -413,8 +425,6 @@ func (r *ReconcileMigAnalytic) analyzeK8SResources(dynamic dynamic.Interface,
 				cohabitator.Seen = true
 			}
 
-			excluded := false
-			compatible := true
 			gvr := schema.GroupVersionResource{}
 			gv := strings.Split(res.GroupVersion, "/")
 			if len(gv) > 1 {
@@ -438,33 +448,63 @@ func (r *ReconcileMigAnalytic) analyzeK8SResources(dynamic dynamic.Interface,
 
 			// If no resources of this type we won't add it to any lists
 			if len(list.Items) > 0 {
+				GVRs = append(GVRs, gvr)
+				lists[gvr] = list.Items
+			}
+		}
+	}
 
-				// Check if the resource type is on the plan exludedResources list
-				excluded = isExcluded(gvr.Resource, excludedResources)
+	for _, gvr := range GVRs {
+		list := lists[gvr]
 
-				// The lists are mutually exclusive. Only if not excluded check the incompatible GVK list
-				if !excluded {
-					compatible = isCompatible(gvr, ns.Namespace, incompatible.Namespaces)
-				}
+		// Check if the resource type is on the plan exludedResources list
+		excluded := isExcluded(gvr.GroupResource(), plan.Status.ExcludedResources)
+		included := plan.Status.IncludedResources
+		if len(included) > 0 && !resourcefilter.Contains(included, gvr.GroupResource()) {
+			excluded = true
+		}
 
-				NamespaceResource := migapi.MigAnalyticNSResource{
-					Group:   gvr.Group,
-					Version: gvr.Version,
-					Kind:    gvr.Resource,
-					Count:   len(list.Items),
-				}
-				if !compatible {
-					ns.IncompatibleK8SResources = append(ns.IncompatibleK8SResources, NamespaceResource)
-					ns.IncompatibleK8SResourceTotal += len(list.Items)
-				} else if excluded {
-					ns.ExcludedK8SResources = append(ns.ExcludedK8SResources, NamespaceResource)
-					ns.ExcludedK8SResourceTotal += len(list.Items)
-				} else {
-					ns.K8SResources = append(ns.K8SResources, NamespaceResource)
-					ns.K8SResourceTotal += len(list.Items)
+		// The lists are mutually exclusive. Only if not excluded check the incompatible GVK list
+		compatible := true
+		if !excluded {
+			compatible = isCompatible(gvr, ns.Namespace, plan.Status.Incompatible.Namespaces)
+		}
+
+		// Objects not selected by the resource filters are excluded.
+		selected := len(list)
+		if !excluded && compatible {
+			selected = 0
+			for i := range list {
+				if filter.Included(&list[i]) {
+					selected++
 				}
 			}
 		}
+
+		NamespaceResource := migapi.MigAnalyticNSResource{
+			Group:   gvr.Group,
+			Version: gvr.Version,
+			Kind:    gvr.Resource,
+			Count:   len(list),
+		}
+		if !compatible {
+			ns.IncompatibleK8SResources = append(ns.IncompatibleK8SResources, NamespaceResource)
+			ns.IncompatibleK8SResourceTotal += len(list)
+		} else if excluded {
+			ns.ExcludedK8SResources = append(ns.ExcludedK8SResources, NamespaceResource)
+			ns.ExcludedK8SResourceTotal += len(list)
+		} else {
+			if selected < len(list) {
+				NamespaceResource.Count = len(list) - selected
+				ns.ExcludedK8SResources = append(ns.ExcludedK8SResources, NamespaceResource)
+				ns.ExcludedK8SResourceTotal += NamespaceResource.Count
+			}
+			if selected > 0 {
+				NamespaceResource.Count = selected
+				ns.K8SResources = append(ns.K8SResources, NamespaceResource)
+				ns.K8SResourceTotal += selected
+			}
+		}
 	}
 
 	return nil
@@ -674,13 +714,17 @@ func GetRegistryInfo(major, minor int, client compat.Client) (string, error) {
 	}
 }
 
-func isExcluded(name string, excludedResources []string) bool {
-	for _, exclude := range excludedResources {
-		if exclude == name {
-			return true
-		}
-	}
-	return false
+// Resources counted by the analytic.
+var (
+	imageStreamsResource = schema.GroupResource{Group: "image.openshift.io", Resource: "imagestreams"}
+	pvResource           = schema.GroupResource{Resource: "persistentvolumes"}
+	pvcResource          = schema.GroupResource{Resource: "persistentvolumeclaims"}
+)
+
+// Get whether a resource is excluded.
+// The resource is matched with and without the group.
+func isExcluded(gr schema.GroupResource, excludedResources []string) bool {
+	return resourcefilter.Contains(excludedResources, gr)
 }
 
 func isCompatible(gvr schema.GroupVersionResource,
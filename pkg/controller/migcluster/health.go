@@ -0,0 +1,264 @@
+package migcluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/pods"
+	appsv1 "k8s.io/api/apps/v1"
+	kapi "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Health check settings.
+const (
+	// How often the cluster health is checked.
+	HealthCheckInterval = time.Minute * 5
+	// API request latency above which the cluster is reported as slow.
+	APILatencyThreshold = time.Second * 2
+)
+
+// Velero components.
+const (
+	VeleroDeployment = "velero"
+	ResticDaemonSet  = "restic"
+)
+
+// Check the health of the cluster and set the health conditions.
+// The health is checked at most every HealthCheckInterval unless a
+// refresh is requested, the conditions are set from the last check.
+func (r ReconcileMigCluster) checkHealth(cluster *migapi.MigCluster) error {
+	if cluster.Status.HasCriticalCondition() {
+		return nil
+	}
+	if !cluster.Spec.Refresh && !needsHealthCheck(cluster) {
+		r.setHealthConditions(cluster)
+		return nil
+	}
+
+	client, err := cluster.GetClient(r)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	health := &migapi.ClusterHealth{
+		LastCheckTimestamp: &metav1.Time{Time: time.Now()},
+	}
+
+	// API latency
+	start := time.Now()
+	err = client.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Name: migapi.VeleroNamespace},
+		&kapi.Namespace{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return liberr.Wrap(err)
+	}
+	health.APILatency = &metav1.Duration{Duration: time.Since(start).Round(time.Millisecond)}
+
+	// Velero
+	err = r.checkVeleroHealth(client, health)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	// Nodes
+	err = r.checkNodeHealth(client, health)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	// Storage classes
+	storageClasses, err := cluster.GetKubeStorageClasses(client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for _, storageClass := range storageClasses {
+		health.StorageClasses = append(health.StorageClasses, storageClass.Name)
+	}
+
+	// Registry
+	err = r.checkRegistryHealth(cluster, health)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	cluster.Status.Health = health
+	r.setHealthConditions(cluster)
+
+	return nil
+}
+
+// Get the desired and ready velero and restic pods.
+func (r ReconcileMigCluster) checkVeleroHealth(client k8sclient.Client, health *migapi.ClusterHealth) error {
+	deployment := appsv1.Deployment{}
+	err := client.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Namespace: migapi.VeleroNamespace, Name: VeleroDeployment},
+		&deployment)
+	if err != nil && !k8serror.IsNotFound(err) {
+		return liberr.Wrap(err)
+	}
+	if err == nil {
+		health.Velero.Desired = 1
+		if deployment.Spec.Replicas != nil {
+			health.Velero.Desired = int(*deployment.Spec.Replicas)
+		}
+	}
+	daemonSet := appsv1.DaemonSet{}
+	err = client.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Namespace: migapi.VeleroNamespace, Name: ResticDaemonSet},
+		&daemonSet)
+	if err != nil && !k8serror.IsNotFound(err) {
+		return liberr.Wrap(err)
+	}
+	if err == nil {
+		health.Restic.Desired = int(daemonSet.Status.DesiredNumberScheduled)
+	}
+
+	podList, err := pods.FindVeleroPods(client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for _, pod := range podList {
+		if !isPodReady(&pod) {
+			continue
+		}
+		if pod.Labels["name"] == ResticDaemonSet {
+			health.Restic.Ready++
+		} else {
+			health.Velero.Ready++
+		}
+	}
+
+	return nil
+}
+
+// Get the nodes and the capacity allocatable on schedulable nodes.
+func (r ReconcileMigCluster) checkNodeHealth(client k8sclient.Client, health *migapi.ClusterHealth) error {
+	list := kapi.NodeList{}
+	err := client.List(context.TODO(), &k8sclient.ListOptions{}, &list)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	for _, node := range list.Items {
+		health.Nodes++
+		if node.Spec.Unschedulable || !isNodeReady(&node) {
+			continue
+		}
+		health.SchedulableNodes++
+		cpu.Add(node.Status.Allocatable[kapi.ResourceCPU])
+		memory.Add(node.Status.Allocatable[kapi.ResourceMemory])
+	}
+	health.AllocatableCPU = &cpu
+	health.AllocatableMemory = &memory
+
+	return nil
+}
+
+// Get whether the registry is reachable.
+func (r ReconcileMigCluster) checkRegistryHealth(cluster *migapi.MigCluster, health *migapi.ClusterHealth) error {
+	if cluster.Status.RegistryPath == "" {
+		return nil
+	}
+	restConfig, err := cluster.BuildRestConfig(r.Client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	reachable := false
+	if restConfig.BearerToken != "" {
+		statusCode, err := probeRegistry("https://"+cluster.Status.RegistryPath+"/v2/", restConfig.BearerToken)
+		reachable = err == nil && statusCode == http.StatusOK
+	}
+	health.RegistryReachable = &reachable
+
+	return nil
+}
+
+// Set the health conditions.
+func (r ReconcileMigCluster) setHealthConditions(cluster *migapi.MigCluster) {
+	health := cluster.Status.Health
+	if !health.Velero.IsReady() {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     migapi.VeleroNotReady,
+			Status:   True,
+			Reason:   NotReady,
+			Category: Warn,
+			Message: fmt.Sprintf("Velero pods are not ready, %d out of %d ready.",
+				health.Velero.Ready, health.Velero.Desired),
+		})
+	}
+	if !health.Restic.IsReady() {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     migapi.ResticNotReady,
+			Status:   True,
+			Reason:   NotReady,
+			Category: Warn,
+			Message: fmt.Sprintf("Restic pods are not ready, %d out of %d ready.",
+				health.Restic.Ready, health.Restic.Desired),
+		})
+	}
+	if health.SchedulableNodes == 0 {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     migapi.NoSchedulableNodes,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Warn,
+			Message:  fmt.Sprintf("No schedulable node found, %d nodes are not ready or unschedulable.", health.Nodes),
+		})
+	}
+	if health.RegistryReachable != nil && !*health.RegistryReachable {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     migapi.RegistryNotHealthy,
+			Status:   True,
+			Reason:   RouteTestFailed,
+			Category: Warn,
+			Message:  fmt.Sprintf("The registry %s is not reachable.", cluster.Status.RegistryPath),
+		})
+	}
+	if health.APILatency != nil && health.APILatency.Duration > APILatencyThreshold {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     migapi.HighAPILatency,
+			Status:   True,
+			Reason:   SlowResponse,
+			Category: Warn,
+			Message:  fmt.Sprintf("The cluster API responded in %s.", health.APILatency.Duration),
+		})
+	}
+}
+
+// Get whether the health should be checked again.
+func needsHealthCheck(cluster *migapi.MigCluster) bool {
+	health := cluster.Status.Health
+	if health == nil || health.LastCheckTimestamp == nil {
+		return true
+	}
+	return time.Since(health.LastCheckTimestamp.Time) >= HealthCheckInterval
+}
+
+func isPodReady(pod *kapi.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == kapi.PodReady {
+			return condition.Status == kapi.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isNodeReady(node *kapi.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == kapi.NodeReady {
+			return condition.Status == kapi.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package migcluster
+
+import (
+	"testing"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileMigCluster_setHealthConditions(t *testing.T) {
+	unreachable := false
+	tests := []struct {
+		name   string
+		health migapi.ClusterHealth
+		want   []string
+	}{
+		{
+			name: "healthy",
+			health: migapi.ClusterHealth{
+				Velero:           migapi.ComponentHealth{Desired: 1, Ready: 1},
+				Restic:           migapi.ComponentHealth{Desired: 3, Ready: 3},
+				Nodes:            3,
+				SchedulableNodes: 3,
+				APILatency:       &metav1.Duration{Duration: time.Millisecond * 20},
+			},
+			want: []string{},
+		},
+		{
+			name: "unhealthy",
+			health: migapi.ClusterHealth{
+				Velero:            migapi.ComponentHealth{Desired: 1, Ready: 0},
+				Restic:            migapi.ComponentHealth{Desired: 0, Ready: 0},
+				Nodes:             3,
+				RegistryReachable: &unreachable,
+				APILatency:        &metav1.Duration{Duration: time.Second * 5},
+			},
+			want: []string{
+				migapi.VeleroNotReady,
+				migapi.ResticNotReady,
+				migapi.NoSchedulableNodes,
+				migapi.RegistryNotHealthy,
+				migapi.HighAPILatency,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := tt.health
+			cluster := &migapi.MigCluster{}
+			cluster.Status.Health = &health
+			ReconcileMigCluster{}.setHealthConditions(cluster)
+			if len(cluster.Status.Conditions.List) != len(tt.want) {
+				t.Errorf("setHealthConditions() set %d conditions, want %d", len(cluster.Status.Conditions.List), len(tt.want))
+			}
+			for _, cndType := range tt.want {
+				if !cluster.Status.HasCondition(cndType) {
+					t.Errorf("setHealthConditions() condition %s not set", cndType)
+				}
+			}
+		})
+	}
+}
+
+func TestNeedsHealthCheck(t *testing.T) {
+	cluster := &migapi.MigCluster{}
+	if !needsHealthCheck(cluster) {
+		t.Error("needsHealthCheck() = false, want true when never checked")
+	}
+	cluster.Status.Health = &migapi.ClusterHealth{
+		LastCheckTimestamp: &metav1.Time{Time: time.Now()},
+	}
+	if needsHealthCheck(cluster) {
+		t.Error("needsHealthCheck() = true, want false when recently checked")
+	}
+	cluster.Status.Health.LastCheckTimestamp = &metav1.Time{Time: time.Now().Add(-HealthCheckInterval)}
+	if !needsHealthCheck(cluster) {
+		t.Error("needsHealthCheck() = false, want true when stale")
+	}
+}
+
+func TestReconcileMigCluster_checkHealth(t *testing.T) {
+	checked := metav1.Time{Time: time.Now().Add(-time.Minute)}
+	cluster := &migapi.MigCluster{}
+	cluster.Status.Health = &migapi.ClusterHealth{
+		LastCheckTimestamp: &checked,
+		Velero:             migapi.ComponentHealth{Desired: 1, Ready: 0},
+		SchedulableNodes:   3,
+	}
+	// Checked recently, the conditions are set from the last check.
+	err := ReconcileMigCluster{}.checkHealth(cluster)
+	if err != nil {
+		t.Fatalf("checkHealth() error = %v", err)
+	}
+	if !cluster.Status.Health.LastCheckTimestamp.Equal(&checked) {
+		t.Errorf("checkHealth() checked within the interval")
+	}
+	if !cluster.Status.HasCondition(migapi.VeleroNotReady) {
+		t.Errorf("checkHealth() condition %s not set", migapi.VeleroNotReady)
+	}
+}
@@ -159,6 +159,13 @@ func (r *ReconcileMigCluster) Reconcile(request reconcile.Request) (reconcile.Re
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// Set Status.Health
+	err = r.checkHealth(cluster)
+	if err != nil {
+		log.Trace(err)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	// Ready
 	cluster.Status.SetReady(
 		!cluster.Status.HasBlockerCondition(),
@@ -65,6 +65,16 @@ func (r ClusterPredicate) mapRefs(cluster *migapi.MigCluster) {
 			Name:      ref.Name,
 		})
 	}
+
+	// kubeconfig secret
+	ref = cluster.Spec.KubeconfigSecretRef
+	if migref.RefSet(ref) {
+		refMap.Add(refOwner, migref.RefTarget{
+			Kind:      migref.ToKind(kapi.Secret{}),
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		})
+	}
 }
 
 func (r ClusterPredicate) unmapRefs(cluster *migapi.MigCluster) {
@@ -85,4 +95,14 @@ func (r ClusterPredicate) unmapRefs(cluster *migapi.MigCluster) {
 			Name:      ref.Name,
 		})
 	}
+
+	// kubeconfig secret
+	ref = cluster.Spec.KubeconfigSecretRef
+	if migref.RefSet(ref) {
+		refMap.Delete(refOwner, migref.RefTarget{
+			Kind:      migref.ToKind(kapi.Secret{}),
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		})
+	}
 }
@@ -37,7 +37,7 @@ func (r *RemoteClusterSource) Start(
 	return nil
 }
 
-// Run the scheduled connection and version match tests.
+// Run the scheduled connection, version match and health tests.
 func (r *RemoteClusterSource) run() {
 	for {
 		time.Sleep(r.Interval)
@@ -52,6 +52,9 @@ func (r *RemoteClusterSource) run() {
 				InvalidURL,
 				InvalidSaSecretRef,
 				InvalidSaToken,
+				InvalidKubeconfigSecretRef,
+				InvalidKubeconfig,
+				InvalidProxyURL,
 				SaTokenNotPrivileged) {
 				continue
 			}
@@ -76,6 +79,12 @@ func (r *RemoteClusterSource) run() {
 				continue
 			}
 
+			// Enqueue when the health has not been checked recently
+			if needsHealthCheck(&cluster) {
+				r.enqueue(cluster)
+				continue
+			}
+
 		}
 	}
 }
@@ -15,6 +15,8 @@ import (
 	liberr "github.com/konveyor/controller/pkg/error"
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	migref "github.com/konveyor/mig-controller/pkg/reference"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 // Types
@@ -27,6 +29,9 @@ const (
 	SaTokenNotPrivileged           = "SaTokenNotPrivileged"
 	OperatorVersionMismatch        = "OperatorVersionMismatch"
 	ClusterOperatorVersionNotFound = "ClusterOperatorVersionNotFound"
+	InvalidKubeconfigSecretRef     = "InvalidKubeconfigSecretRef"
+	InvalidKubeconfig              = "InvalidKubeconfig"
+	InvalidProxyURL                = "InvalidProxyURL"
 )
 
 // Categories
@@ -46,6 +51,10 @@ const (
 	Unauthorized       = "Unauthorized"
 	VersionCheckFailed = "VersionCheckFailed"
 	VersionNotFound    = "VersionNotFound"
+	ContextNotFound    = "ContextNotFound"
+	FileReference      = "FileReference"
+	NotReady           = "NotReady"
+	SlowResponse       = "SlowResponse"
 )
 
 // Statuses
@@ -63,12 +72,24 @@ func (r ReconcileMigCluster) validate(cluster *migapi.MigCluster) error {
 		return liberr.Wrap(err)
 	}
 
+	// Proxy
+	err = r.validateProxyURL(cluster)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
 	// SA secret
 	err = r.validateSaSecret(cluster)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
 
+	// Kubeconfig secret
+	err = r.validateKubeconfigSecret(cluster)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
 	// Test Connection
 	err = r.testConnection(cluster)
 	if err != nil {
@@ -98,7 +119,7 @@ func (r ReconcileMigCluster) validate(cluster *migapi.MigCluster) error {
 
 func (r ReconcileMigCluster) validateURL(cluster *migapi.MigCluster) error {
 	// Not needed.
-	if cluster.Spec.IsHostCluster {
+	if cluster.Spec.IsHostCluster || cluster.UsesKubeconfig() {
 		return nil
 	}
 
@@ -140,7 +161,7 @@ func (r ReconcileMigCluster) validateSaSecret(cluster *migapi.MigCluster) error
 	ref := cluster.Spec.ServiceAccountSecretRef
 
 	// Not needed.
-	if cluster.Spec.IsHostCluster {
+	if cluster.Spec.IsHostCluster || cluster.UsesKubeconfig() {
 		return nil
 	}
 
@@ -205,6 +226,162 @@ func (r ReconcileMigCluster) validateSaSecret(cluster *migapi.MigCluster) error
 	return nil
 }
 
+func (r ReconcileMigCluster) validateProxyURL(cluster *migapi.MigCluster) error {
+	// Not needed.
+	if cluster.Spec.IsHostCluster || cluster.Spec.ProxyURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(cluster.Spec.ProxyURL)
+	if err != nil {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidProxyURL,
+			Status:   True,
+			Reason:   Malformed,
+			Category: Critical,
+			Message:  "The `spec.proxyURL` is malformed.",
+		})
+		return nil
+	}
+	if u.Scheme != "http" || u.Host == "" {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidProxyURL,
+			Status:   True,
+			Reason:   InvalidScheme,
+			Category: Critical,
+			Message:  "The `spec.proxyURL` is invalid, must be: http://<host>:<port>.",
+		})
+		return nil
+	}
+	return nil
+}
+
+func (r ReconcileMigCluster) validateKubeconfigSecret(cluster *migapi.MigCluster) error {
+	ref := cluster.Spec.KubeconfigSecretRef
+
+	// Not needed.
+	if !cluster.UsesKubeconfig() {
+		return nil
+	}
+
+	// NotSet
+	if !migref.RefSet(ref) {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidKubeconfigSecretRef,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The `kubeconfigSecretRef` must reference a `secret`.",
+		})
+		return nil
+	}
+
+	secret, err := migapi.GetSecret(r, ref)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	// NotFound
+	if secret == nil {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidKubeconfigSecretRef,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Critical,
+			Message: fmt.Sprintf("The `kubeconfigSecretRef` must reference a valid `secret`,"+
+				" subject: %s.", path.Join(ref.Namespace, ref.Name)),
+		})
+		return nil
+	}
+
+	// kubeconfig
+	content, found := secret.Data[migapi.KubeconfigKey]
+	if !found || len(content) == 0 {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidKubeconfig,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Critical,
+			Message: fmt.Sprintf("The `%s` not found in `kubeconfigSecretRef` secret,"+
+				" subject: %s.", migapi.KubeconfigKey, path.Join(ref.Namespace, ref.Name)),
+		})
+		return nil
+	}
+	kubeconfig, err := clientcmd.Load(content)
+	if err != nil {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidKubeconfig,
+			Status:   True,
+			Reason:   Malformed,
+			Category: Critical,
+			Message: fmt.Sprintf("The `%s` found in `kubeconfigSecretRef` secret is malformed,"+
+				" subject: %s.", migapi.KubeconfigKey, path.Join(ref.Namespace, ref.Name)),
+			Items: []string{err.Error()},
+		})
+		return nil
+	}
+
+	// Context
+	contextName := cluster.Spec.KubeconfigContext
+	if contextName == "" {
+		contextName = kubeconfig.CurrentContext
+	}
+	if _, found := kubeconfig.Contexts[contextName]; !found {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidKubeconfig,
+			Status:   True,
+			Reason:   ContextNotFound,
+			Category: Critical,
+			Message: fmt.Sprintf("The kubeconfig context `%s` not found in `kubeconfigSecretRef` secret,"+
+				" subject: %s.", contextName, path.Join(ref.Namespace, ref.Name)),
+		})
+		return nil
+	}
+
+	// File references would be resolved on the controller filesystem.
+	references := kubeconfigFileReferences(kubeconfig, contextName)
+	if len(references) > 0 {
+		cluster.Status.SetCondition(migapi.Condition{
+			Type:     InvalidKubeconfig,
+			Status:   True,
+			Reason:   FileReference,
+			Category: Critical,
+			Message: fmt.Sprintf("The kubeconfig context `%s` references files, the certificates, keys and"+
+				" tokens must be embedded, subject: %s.", contextName, path.Join(ref.Namespace, ref.Name)),
+			Items: references,
+		})
+		return nil
+	}
+
+	return nil
+}
+
+// Get the fields referencing files used by a kubeconfig context.
+func kubeconfigFileReferences(kubeconfig *clientcmdapi.Config, contextName string) []string {
+	references := []string{}
+	kubeContext := kubeconfig.Contexts[contextName]
+	if kubeContext == nil {
+		return references
+	}
+	if cluster, found := kubeconfig.Clusters[kubeContext.Cluster]; found {
+		if cluster.CertificateAuthority != "" {
+			references = append(references, "certificate-authority")
+		}
+	}
+	if user, found := kubeconfig.AuthInfos[kubeContext.AuthInfo]; found {
+		if user.ClientCertificate != "" {
+			references = append(references, "client-certificate")
+		}
+		if user.ClientKey != "" {
+			references = append(references, "client-key")
+		}
+		if user.TokenFile != "" {
+			references = append(references, "tokenFile")
+		}
+	}
+	return references
+}
+
 // Test the connection.
 func (r ReconcileMigCluster) testConnection(cluster *migapi.MigCluster) error {
 	if cluster.Spec.IsHostCluster {
@@ -219,7 +396,7 @@ func (r ReconcileMigCluster) testConnection(cluster *migapi.MigCluster) error {
 	err := cluster.TestConnection(r.Client, timeout)
 	if err != nil {
 		helpText := ""
-		if strings.Contains(err.Error(), "x509") &&
+		if strings.Contains(err.Error(), "x509") && !cluster.UsesKubeconfig() &&
 			len(cluster.Spec.CABundle) == 0 && !cluster.Spec.Insecure {
 			helpText = "The `caBundle` is required for self-signed API server certificates."
 		}
@@ -237,6 +414,41 @@ func (r ReconcileMigCluster) testConnection(cluster *migapi.MigCluster) error {
 	return nil
 }
 
+// Send an authenticated GET request to a registry URL.
+// Returns the response status code.
+func probeRegistry(url, token string) (int, error) {
+	// Construct transport using default values from http lib
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	transport := &http.Transport{
+		Proxy:                 defaultTransport.Proxy,
+		DialContext:           defaultTransport.DialContext,
+		MaxIdleConns:          defaultTransport.MaxIdleConns,
+		IdleConnTimeout:       defaultTransport.IdleConnTimeout,
+		TLSHandshakeTimeout:   defaultTransport.TLSHandshakeTimeout,
+		ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	}
+
+	client := &http.Client{Transport: transport, Timeout: time.Second * 30}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Authorization", "bearer "+token)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+
+	return res.StatusCode, nil
+}
+
 // Validate the Exposed registry route
 func (r ReconcileMigCluster) validateRegistryRoute(cluster *migapi.MigCluster) error {
 
@@ -247,33 +459,22 @@ func (r ReconcileMigCluster) validateRegistryRoute(cluster *migapi.MigCluster) e
 	if cluster.Spec.ExposedRegistryPath != "" {
 		url := "https://" + cluster.Spec.ExposedRegistryPath + "/v2/"
 		restConfig, err := cluster.BuildRestConfig(r.Client)
-		token := restConfig.BearerToken
-
-		// Construct transport using default values from http lib
-		defaultTransport := http.DefaultTransport.(*http.Transport)
-		transport := &http.Transport{
-			Proxy:                 defaultTransport.Proxy,
-			DialContext:           defaultTransport.DialContext,
-			MaxIdleConns:          defaultTransport.MaxIdleConns,
-			IdleConnTimeout:       defaultTransport.IdleConnTimeout,
-			TLSHandshakeTimeout:   defaultTransport.TLSHandshakeTimeout,
-			ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		}
-
-		client := &http.Client{Transport: transport}
-
-		req, err := http.NewRequest("GET", url, nil)
-
 		if err != nil {
 			return liberr.Wrap(err)
 		}
+		token := restConfig.BearerToken
+		if token == "" {
+			cluster.Status.SetCondition(migapi.Condition{
+				Type:     InvalidRegistryRoute,
+				Status:   True,
+				Reason:   NotSet,
+				Category: Critical,
+				Message:  "Exposed registry route requires a bearer token, the `kubeconfig` user must specify a `token`.",
+			})
+			return nil
+		}
 
-		req.Header.Set("Authorization", "bearer "+token)
-
-		res, err := client.Do(req)
+		statusCode, err := probeRegistry(url, token)
 		if err != nil {
 			cluster.Status.SetCondition(migapi.Condition{
 				Type:     InvalidRegistryRoute,
@@ -286,13 +487,13 @@ func (r ReconcileMigCluster) validateRegistryRoute(cluster *migapi.MigCluster) e
 			return nil
 		}
 
-		if res.StatusCode != 200 {
+		if statusCode != http.StatusOK {
 			cluster.Status.SetCondition(migapi.Condition{
 				Type:     InvalidRegistryRoute,
 				Status:   True,
 				Reason:   RouteTestFailed,
 				Category: Critical,
-				Message:  fmt.Sprintf("Exposed registry route connection test failed, Response code received: %#v", res.StatusCode),
+				Message:  fmt.Sprintf("Exposed registry route connection test failed, Response code received: %#v", statusCode),
 			})
 			return nil
 		}
@@ -347,12 +548,16 @@ func (r *ReconcileMigCluster) validateSaTokenPrivileges(cluster *migapi.MigClust
 	}
 
 	if !migrationSar.Status.Allowed || !veleroSar.Status.Allowed {
+		message := "The `saToken` has insufficient privileges."
+		if cluster.UsesKubeconfig() {
+			message = "The `kubeconfig` credentials have insufficient privileges."
+		}
 		cluster.Status.SetCondition(migapi.Condition{
 			Type:     SaTokenNotPrivileged,
 			Status:   True,
 			Reason:   Unauthorized,
 			Category: Critical,
-			Message:  fmt.Sprintf("The `saToken` has insufficient privileges."),
+			Message:  message,
 		})
 	}
 	return nil
@@ -18,6 +18,8 @@ package mighook
 
 import (
 	"context"
+	"time"
+
 	"github.com/konveyor/mig-controller/pkg/errorutil"
 
 	"github.com/konveyor/controller/pkg/logging"
@@ -35,6 +37,9 @@ import (
 
 var log = logging.WithName("hook")
 
+// Interval between attempts to resolve a hook source.
+const SourceResolveRetryInterval = time.Minute
+
 // Add creates a new MigHook Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
@@ -133,6 +138,11 @@ func (r *ReconcileMigHook) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// Retry resolving the source.
+	if hook.Status.HasCondition(SourceNotResolved) {
+		return reconcile.Result{RequeueAfter: SourceResolveRetryInterval}, nil
+	}
+
 	// Done
 	return reconcile.Result{}, nil
 }
@@ -2,9 +2,12 @@ package mighook
 
 import (
 	"encoding/base64"
+	"time"
 
 	liberr "github.com/konveyor/controller/pkg/error"
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/hooksource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Types
@@ -14,6 +17,8 @@ const (
 	InvalidPlaybookData  = "InvalidPlaybookData"
 	InvalidAnsibleHook   = "InvalidAnsibleHook"
 	InvalidCustomHook    = "InvalidCustomHook"
+	InvalidHookSource    = "InvalidHookSource"
+	SourceNotResolved    = "SourceNotResolved"
 )
 
 // Categories
@@ -55,6 +60,10 @@ func (r ReconcileMigHook) validate(hook *migapi.MigHook) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	err = r.validateSource(hook)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	return nil
 }
 
@@ -108,14 +117,139 @@ func (r ReconcileMigHook) validateCustom(hook *migapi.MigHook) error {
 			Category: Critical,
 			Message:  "An Ansible Playbook must not be specified when spec.custom is true.",
 		})
-	} else if !hook.Spec.Custom && hook.Spec.Playbook == "" {
+	} else if !hook.Spec.Custom && hook.Spec.Playbook == "" && hook.Spec.Source == nil {
 		hook.Status.SetCondition(migapi.Condition{
 			Type:     InvalidAnsibleHook,
 			Status:   True,
 			Reason:   NotSet,
 			Category: Critical,
-			Message:  "An Ansible Playbook must be specified when spec.custom is false.",
+			Message:  "An Ansible Playbook or a source must be specified when spec.custom is false.",
 		})
 	}
 	return nil
 }
+
+// Validate the hook source and pin it to a revision.
+// The source is resolved once per generation so that migrations run
+// the same revision until the hook is changed.
+func (r ReconcileMigHook) validateSource(hook *migapi.MigHook) error {
+	source := hook.Spec.Source
+	if source == nil {
+		hook.Status.Source = nil
+		return nil
+	}
+	if hook.Spec.Playbook != "" {
+		hook.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookSource,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Critical,
+			Message:  "An Ansible Playbook must not be specified with spec.source.",
+		})
+		return nil
+	}
+	if !validSource(source) {
+		hook.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookSource,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message: "Exactly one of spec.source.git and spec.source.oci must be specified with a path," +
+				" the git url must be HTTPS and the oci reference must be valid.",
+		})
+		return nil
+	}
+	credentials, err := r.getSourceCredentials(hook)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if credentials == nil && source.CredentialsSecretRef != nil {
+		return nil
+	}
+	if hook.SourceResolved() {
+		return nil
+	}
+	revision := ""
+	switch {
+	case source.Git != nil:
+		revision, err = hooksource.ResolveGit(source.Git.URL, source.Git.Ref, credentials)
+	case source.OCI != nil:
+		revision, err = hooksource.ResolveOCI(source.OCI.Reference, credentials)
+	}
+	if err != nil {
+		log.Info("Hook source not resolved.", "error", err.Error())
+		hook.Status.SetCondition(migapi.Condition{
+			Type:     SourceNotResolved,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Critical,
+			Message:  "The hook source could not be resolved: " + err.Error(),
+		})
+		return nil
+	}
+	hook.Status.Source = &migapi.ResolvedHookSource{
+		Revision:          revision,
+		Generation:        hook.Generation,
+		ResolvedTimestamp: &metav1.Time{Time: time.Now()},
+	}
+
+	return nil
+}
+
+// Get whether the source specifies exactly one valid location.
+func validSource(source *migapi.HookSource) bool {
+	switch {
+	case source.Git != nil && source.OCI != nil:
+		return false
+	case source.Git != nil:
+		return hooksource.ValidateGitURL(source.Git.URL) == nil && source.Git.Path != ""
+	case source.OCI != nil:
+		return ReferenceRegexp.MatchString(source.OCI.Reference) && source.OCI.Path != ""
+	}
+	return false
+}
+
+// Get the credentials used to fetch the source.
+// Returns nil when no secret is referenced or the secret is invalid, in
+// which case a condition is set.
+func (r ReconcileMigHook) getSourceCredentials(hook *migapi.MigHook) (*hooksource.Credentials, error) {
+	ref := hook.Spec.Source.CredentialsSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+	if ref.Namespace != hook.Namespace {
+		hook.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookSource,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Critical,
+			Message:  "The secret referenced by spec.source.credentialsSecretRef must be in the namespace of the hook.",
+		})
+		return nil, nil
+	}
+	secret, err := migapi.GetSecret(r, ref)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	if secret == nil {
+		hook.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookSource,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Critical,
+			Message:  "The secret referenced by spec.source.credentialsSecretRef was not found.",
+		})
+		return nil, nil
+	}
+	credentials := hooksource.SecretCredentials(secret)
+	if credentials == nil {
+		hook.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookSource,
+			Status:   True,
+			Reason:   KeyError,
+			Category: Critical,
+			Message:  "The secret referenced by spec.source.credentialsSecretRef must contain the `username` and `password` keys.",
+		})
+	}
+	return credentials, nil
+}
@@ -0,0 +1,111 @@
+package mighook
+
+import (
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_validSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source migapi.HookSource
+		want   bool
+	}{
+		{
+			name: "git",
+			source: migapi.HookSource{
+				Git: &migapi.GitHookSource{URL: "https://github.com/konveyor/hooks.git", Ref: "main", Path: "playbook.yml"},
+			},
+			want: true,
+		},
+		{
+			name: "git ssh",
+			source: migapi.HookSource{
+				Git: &migapi.GitHookSource{URL: "git@github.com:konveyor/hooks.git", Path: "playbook.yml"},
+			},
+			want: false,
+		},
+		{
+			name: "git http",
+			source: migapi.HookSource{
+				Git: &migapi.GitHookSource{URL: "http://github.com/konveyor/hooks.git", Path: "playbook.yml"},
+			},
+			want: false,
+		},
+		{
+			name: "git without path",
+			source: migapi.HookSource{
+				Git: &migapi.GitHookSource{URL: "https://github.com/konveyor/hooks.git"},
+			},
+			want: false,
+		},
+		{
+			name: "oci",
+			source: migapi.HookSource{
+				OCI: &migapi.OCIHookSource{Reference: "quay.io/konveyor/hooks:v1", Path: "playbook.yml"},
+			},
+			want: true,
+		},
+		{
+			name: "oci invalid reference",
+			source: migapi.HookSource{
+				OCI: &migapi.OCIHookSource{Reference: "Quay.io/Hooks::v1", Path: "playbook.yml"},
+			},
+			want: false,
+		},
+		{
+			name: "git and oci",
+			source: migapi.HookSource{
+				Git: &migapi.GitHookSource{URL: "https://github.com/konveyor/hooks.git", Path: "playbook.yml"},
+				OCI: &migapi.OCIHookSource{Reference: "quay.io/konveyor/hooks:v1", Path: "playbook.yml"},
+			},
+			want: false,
+		},
+		{
+			name:   "none",
+			source: migapi.HookSource{},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validSource(&tt.source); got != tt.want {
+				t.Errorf("validSource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileMigHook_getSourceCredentials(t *testing.T) {
+	hook := &migapi.MigHook{
+		ObjectMeta: metav1.ObjectMeta{Name: "hook", Namespace: migapi.OpenshiftMigrationNamespace},
+		Spec: migapi.MigHookSpec{
+			Source: &migapi.HookSource{
+				Git:                  &migapi.GitHookSource{URL: "https://github.com/konveyor/hooks.git", Path: "playbook.yml"},
+				CredentialsSecretRef: &corev1.ObjectReference{Name: "credentials", Namespace: "other"},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "other"},
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("user"),
+			corev1.BasicAuthPasswordKey: []byte("secret"),
+		},
+	}
+	r := ReconcileMigHook{Client: fake.NewFakeClient(secret)}
+	credentials, err := r.getSourceCredentials(hook)
+	if err != nil {
+		t.Fatalf("getSourceCredentials() error = %v", err)
+	}
+	if credentials != nil {
+		t.Errorf("getSourceCredentials() = %v, want nil", credentials)
+	}
+	if !hook.Status.HasCondition(InvalidHookSource) {
+		t.Errorf("getSourceCredentials() condition %s not set", InvalidHookSource)
+	}
+}
@@ -43,8 +43,12 @@ func (t *Task) ensureInitialBackup() (*velero.Backup, error) {
 	newBackup.Labels[MigPlanDebugLabel] = t.Owner.Spec.MigPlanRef.Name
 	newBackup.Labels[MigMigrationLabel] = string(t.Owner.UID)
 	newBackup.Labels[MigPlanLabel] = string(t.PlanResources.MigPlan.UID)
-	newBackup.Spec.IncludedResources = toStringSlice(settings.IncludedInitialResources.Difference(toSet(t.PlanResources.MigPlan.Status.ExcludedResources)))
+	includedResources := settings.IncludedInitialResources.Union(toSet(t.PlanResources.MigPlan.Status.IncludedResources))
+	newBackup.Spec.IncludedResources = toStringSlice(includedResources.Difference(toSet(t.PlanResources.MigPlan.Status.ExcludedResources)))
 	newBackup.Spec.ExcludedResources = toStringSlice(settings.ExcludedInitialResources.Union(toSet(t.PlanResources.MigPlan.Status.ExcludedResources)))
+	if filters := t.PlanResources.MigPlan.Spec.ResourceFilters; filters != nil {
+		newBackup.Spec.LabelSelector = filters.LabelSelector
+	}
 	delete(newBackup.Annotations, QuiesceAnnotation)
 	err = client.Create(context.TODO(), newBackup)
 	if err != nil {
@@ -0,0 +1,224 @@
+package migmigration
+
+import (
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/clusterresource"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+)
+
+// Get whether cluster-scoped resources are migrated.
+func (t *Task) hasClusterResources() bool {
+	return t.PlanResources.MigPlan.Spec.ClusterResources.Enabled()
+}
+
+// Get the labels set on the migrated cluster-scoped resources.
+func (t *Task) clusterResourceLabels() labels.Set {
+	return labels.Set{
+		MigPlanLabel:      string(t.PlanResources.MigPlan.UID),
+		MigMigrationLabel: string(t.Owner.UID),
+	}
+}
+
+// Migrate the cluster-scoped dependencies of the namespaces.
+// Resources are discovered on the source cluster and created on the
+// destination cluster. SCCs found on the destination cluster are updated
+// with the users and groups of the namespaces. Resources found on the
+// destination cluster with a different content are not migrated and are
+// reported by the ClusterResourceConflict condition.
+// Returns `true` when the created resources are established.
+func (t *Task) migrateClusterResources() (bool, error) {
+	srcClient, err := t.dynamicClient(t.PlanResources.SrcMigCluster)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	destClient, err := t.dynamicClient(t.PlanResources.DestMigCluster)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	discovery := clusterresource.Discovery{
+		Client:     srcClient,
+		Options:    t.PlanResources.MigPlan.Spec.ClusterResources,
+		Namespaces: t.sourceNamespaces(),
+	}
+	resources, err := discovery.Run()
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	namespaces := t.PlanResources.MigPlan.GetNamespaceMapping()
+	owner := labels.Set{MigPlanLabel: string(t.PlanResources.MigPlan.UID)}
+	established := true
+	conflicts := []string{}
+	for _, resource := range resources {
+		desired, err := resource.Desired(namespaces)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		existing, err := resource.Existing(destClient)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		switch {
+		case existing == nil:
+			created, err := t.createClusterResource(destClient, resource, desired)
+			if err != nil {
+				return false, liberr.Wrap(err)
+			}
+			established = established && clusterresource.Established(created)
+		case clusterresource.Owned(existing, owner):
+			established = established && clusterresource.Established(existing)
+		case clusterresource.Conflicts(desired, existing):
+			conflicts = append(conflicts, resource.String())
+		case resource.GVR() == clusterresource.SCCResource:
+			added := clusterresource.MergeSubjects(desired, existing)
+			if len(added) == 0 {
+				continue
+			}
+			err = clusterresource.RecordSubjects(existing, string(t.PlanResources.MigPlan.UID), added)
+			if err != nil {
+				return false, liberr.Wrap(err)
+			}
+			_, err = destClient.Resource(resource.GVR()).Update(existing, metav1.UpdateOptions{})
+			if err != nil {
+				return false, liberr.Wrap(err)
+			}
+			log.Info(
+				"SCC updated with the migrated service accounts.",
+				"name",
+				resource.Name)
+		}
+	}
+	if len(conflicts) > 0 {
+		t.Owner.Status.SetCondition(migapi.Condition{
+			Type:     ClusterResourceConflict,
+			Status:   True,
+			Category: migapi.Warn,
+			Message: "Cluster-scoped resources [] exist on the destination cluster with a different content" +
+				" and were not migrated.",
+			Items:   conflicts,
+			Durable: true,
+		})
+	}
+
+	return established, nil
+}
+
+// Create a cluster-scoped resource on the destination cluster.
+func (t *Task) createClusterResource(
+	client dynamic.Interface,
+	resource *clusterresource.Resource,
+	desired *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	objectLabels := desired.GetLabels()
+	if objectLabels == nil {
+		objectLabels = map[string]string{}
+	}
+	for k, v := range t.clusterResourceLabels() {
+		objectLabels[k] = v
+	}
+	desired.SetLabels(objectLabels)
+	created, err := client.Resource(resource.GVR()).Create(desired, metav1.CreateOptions{})
+	if err != nil {
+		if k8serror.IsAlreadyExists(err) {
+			return desired, nil
+		}
+		return nil, liberr.Wrap(err)
+	}
+	log.Info(
+		"Cluster-scoped resource migrated.",
+		"resource",
+		resource.String(),
+		"reason",
+		resource.Reason)
+	return created, nil
+}
+
+// Delete the cluster-scoped resources created on the destination cluster
+// and revert the updates of the SCCs found on the destination cluster.
+func (t *Task) deleteMigratedClusterResources() error {
+	client, err := t.dynamicClient(t.PlanResources.DestMigCluster)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	options := metav1.ListOptions{
+		LabelSelector: labels.Set{
+			MigPlanLabel: string(t.PlanResources.MigPlan.UID),
+		}.String(),
+	}
+	for _, gvr := range clusterresource.Resources {
+		list, err := client.Resource(gvr).List(options)
+		if err != nil {
+			if k8serror.IsNotFound(err) || k8serror.IsMethodNotSupported(err) {
+				continue
+			}
+			return liberr.Wrap(err)
+		}
+		for _, object := range list.Items {
+			err = client.Resource(gvr).Delete(object.GetName(), nil)
+			if err != nil {
+				if k8serror.IsNotFound(err) {
+					continue
+				}
+				return liberr.Wrap(err)
+			}
+			log.Info(
+				"Deleted cluster-scoped resource from destination cluster.",
+				"resource",
+				gvr.GroupResource().String(),
+				"name",
+				object.GetName())
+		}
+	}
+
+	return liberr.Wrap(t.revertMergedSubjects(client))
+}
+
+// Remove the users and groups merged by the plan into the SCCs found on the destination cluster.
+func (t *Task) revertMergedSubjects(client dynamic.Interface) error {
+	list, err := client.Resource(clusterresource.SCCResource).List(metav1.ListOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) || k8serror.IsMethodNotSupported(err) {
+			return nil
+		}
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		object := &list.Items[i]
+		reverted, err := clusterresource.RevertSubjects(object, string(t.PlanResources.MigPlan.UID))
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if !reverted {
+			continue
+		}
+		_, err = client.Resource(clusterresource.SCCResource).Update(object, metav1.UpdateOptions{})
+		if err != nil {
+			if k8serror.IsNotFound(err) {
+				continue
+			}
+			return liberr.Wrap(err)
+		}
+		log.Info(
+			"SCC reverted, removed the migrated service accounts.",
+			"name",
+			object.GetName())
+	}
+
+	return nil
+}
+
+// Get a dynamic client for a cluster.
+func (t *Task) dynamicClient(cluster *migapi.MigCluster) (dynamic.Interface, error) {
+	restCfg, err := cluster.BuildRestConfig(t.Client)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	client, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	return client, nil
+}
@@ -38,6 +38,16 @@ var PhaseDescriptions = map[string]string{
 	PostBackupHooksFailed:                 "Migration failed while running user-defined post-backup hooks.",
 	PreRestoreHooksFailed:                 "Migration failed while running user-defined pre-restore hooks.",
 	PostRestoreHooksFailed:                "Migration failed while running user-defined post-restore hooks.",
+	PreQuiesceHooks:                       "Waiting for user-defined pre-quiesce hooks to complete.",
+	PostQuiesceHooks:                      "Waiting for user-defined post-quiesce hooks to complete.",
+	PreVolumeCopyHooks:                    "Waiting for user-defined pre-volume-copy hooks to complete.",
+	PostVolumeCopyHooks:                   "Waiting for user-defined post-volume-copy hooks to complete.",
+	PostVerifyHooks:                       "Waiting for user-defined post-verify hooks to complete.",
+	PreQuiesceHooksFailed:                 "Migration failed while running user-defined pre-quiesce hooks.",
+	PostQuiesceHooksFailed:                "Migration failed while running user-defined post-quiesce hooks.",
+	PreVolumeCopyHooksFailed:              "Migration failed while running user-defined pre-volume-copy hooks.",
+	PostVolumeCopyHooksFailed:             "Migration failed while running user-defined post-volume-copy hooks.",
+	PostVerifyHooksFailed:                 "Migration failed while running user-defined post-verify hooks.",
 	EnsureInitialBackup:                   "Creating initial Velero backup.",
 	InitialBackupCreated:                  "Waiting for initial Velero backup to complete.",
 	InitialBackupFailed:                   "Migration failed during initial Velero backup.",
@@ -63,10 +73,15 @@ var PhaseDescriptions = map[string]string{
 	EnsureStageRestore:                    "Creating a stage Velero restore including OpenShift resources and PVCs.",
 	StageRestoreCreated:                   "Waiting for stage Velero restore to complete.",
 	StageRestoreFailed:                    "Migration failed during stage Velero restore.",
+	MigrateClusterResources:               "Migrating the cluster-scoped resources the namespaces depend on.",
 	EnsureFinalRestore:                    "Creating final Velero restore.",
 	FinalRestoreCreated:                   "Waiting for final Velero restore to complete.",
 	FinalRestoreFailed:                    "Migration failed during final Velero restore.",
+	RewriteImageReferences:                "Rewriting image references in migrated Deployments, DeploymentConfigs, StatefulSets and CronJobs to the destination image registry.",
 	Verification:                          "Verifying health of migrated Pods.",
+	RunVerificationChecks:                 "Running the verification checks on the target cluster.",
+	VerificationChecksFailed:              "Migration failed while running the verification checks.",
+	CompareResources:                      "Comparing the migrated resources on the source and target clusters.",
 	Rollback:                              "Starting rollback",
 	CreateDirectImageMigration:            "Creating Direct Image Migration",
 	WaitForDirectImageMigrationToComplete: "Waiting for Direct Image Migration to complete.",
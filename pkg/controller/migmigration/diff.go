@@ -0,0 +1,207 @@
+package migmigration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/diff"
+	"github.com/konveyor/mig-controller/pkg/gvk"
+	"github.com/konveyor/mig-controller/pkg/resourcefilter"
+	v1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Resource comparison.
+const (
+	// The report key in the ConfigMap.
+	ResourceDiffReportKey = "report.json"
+	// The maximum number of differences in the report.
+	MaxResourceDifferences = 1000
+)
+
+// Get whether the migrated resources are compared.
+func (t *Task) compareResources() bool {
+	return t.Owner.Spec.CompareResources
+}
+
+// Compare the migrated resources on the source and destination clusters.
+// The differences are reported in a ConfigMap in the migration namespace.
+// The comparison is informational and errors do not fail the migration.
+func (t *Task) compareMigratedResources() {
+	report, err := t.diffResources()
+	if err == nil {
+		err = t.ensureResourceDiffReport(report)
+	}
+	if err != nil {
+		t.Log.Info("Resource comparison failed.", "error", err.Error())
+		t.Owner.Status.SetCondition(migapi.Condition{
+			Type:     ResourceComparisonFailed,
+			Status:   True,
+			Category: migapi.Warn,
+			Message:  fmt.Sprintf("The migrated resources could not be compared: %s", err.Error()),
+			Durable:  true,
+		})
+		return
+	}
+	if len(report.Differences) > 0 {
+		t.Owner.Status.SetCondition(migapi.Condition{
+			Type:     ResourceDifferencesFound,
+			Status:   True,
+			Category: migapi.Warn,
+			Message: fmt.Sprintf(
+				"The migrated resources differ from the source resources, see ConfigMap %s/%s.",
+				t.Owner.Namespace,
+				t.resourceDiffReportName()),
+			Durable: true,
+		})
+	}
+}
+
+// Compare the resources in the migrated namespaces.
+func (t *Task) diffResources() (*diff.Report, error) {
+	srcClient, GVRs, err := gvk.GetPreferredNamespacedGVRsForCluster(t.PlanResources.SrcMigCluster, t.Client)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	restCfg, err := t.PlanResources.DestMigCluster.BuildRestConfig(t.Client)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	destClient, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	filter, err := t.resourceFilter()
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	srcNamespaces := t.sourceNamespaces()
+	destNamespaces := t.destinationNamespaces()
+	options := &diff.Options{
+		NamespaceMapping: map[string]string{},
+	}
+	for i := range srcNamespaces {
+		options.NamespaceMapping[srcNamespaces[i]] = destNamespaces[i]
+	}
+	report := &diff.Report{}
+	for _, gvr := range GVRs {
+		if !t.backupIncludesResource(gvr.GroupResource()) {
+			continue
+		}
+		for i := range srcNamespaces {
+			src, err := listResources(srcClient, gvr, srcNamespaces[i])
+			if err != nil {
+				return nil, liberr.Wrap(err)
+			}
+			dest, err := listResources(destClient, gvr, destNamespaces[i])
+			if err != nil {
+				return nil, liberr.Wrap(err)
+			}
+			report.Add(diff.Compare(gvr, destNamespaces[i], filtered(filter, src), filtered(filter, dest), options))
+		}
+	}
+
+	return report, nil
+}
+
+// List the resources in a namespace.
+// Resources not served by the cluster are reported as empty.
+func listResources(client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) || k8serror.IsMethodNotSupported(err) {
+			return nil, nil
+		}
+		return nil, liberr.Wrap(err)
+	}
+	return list.Items, nil
+}
+
+// Get the objects selected by the resource filter.
+func filtered(filter *resourcefilter.Filter, objects []unstructured.Unstructured) []unstructured.Unstructured {
+	if filter == nil {
+		return objects
+	}
+	selected := []unstructured.Unstructured{}
+	for i := range objects {
+		if filter.Included(&objects[i]) {
+			selected = append(selected, objects[i])
+		}
+	}
+	return selected
+}
+
+// Get the name of the resource comparison report.
+func (t *Task) resourceDiffReportName() string {
+	return t.Owner.Name + "-diff"
+}
+
+// Create or update the ConfigMap containing the comparison report and
+// summarize the report in the migration status.
+func (t *Task) ensureResourceDiffReport(report *diff.Report) error {
+	status := &migapi.ResourceDiff{
+		ReportRef: &v1.ObjectReference{
+			Namespace: t.Owner.Namespace,
+			Name:      t.resourceDiffReportName(),
+		},
+		Compared: report.Compared,
+		Missing:  report.Count(diff.Missing),
+		Extra:    report.Count(diff.Extra),
+		Changed:  report.Count(diff.Changed),
+	}
+	if len(report.Differences) > MaxResourceDifferences {
+		report.Differences = report.Differences[:MaxResourceDifferences]
+		status.Truncated = true
+	}
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	configMap := v1.ConfigMap{}
+	err = t.Client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Namespace: t.Owner.Namespace,
+			Name:      t.resourceDiffReportName(),
+		},
+		&configMap)
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return liberr.Wrap(err)
+		}
+		configMap = v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: t.Owner.Namespace,
+				Name:      t.resourceDiffReportName(),
+				Labels:    t.Owner.GetCorrelationLabels(),
+			},
+			Data: map[string]string{
+				ResourceDiffReportKey: string(content),
+			},
+		}
+		migapi.SetOwnerReference(t.Owner, t.Owner, &configMap)
+		err = t.Client.Create(context.TODO(), &configMap)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	} else {
+		configMap.Data = map[string]string{
+			ResourceDiffReportKey: string(content),
+		}
+		err = t.Client.Update(context.TODO(), &configMap)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+	t.Owner.Status.ResourceDiff = status
+
+	return nil
+}
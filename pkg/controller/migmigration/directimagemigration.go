@@ -67,6 +67,7 @@ func (t *Task) buildDirectImageMigration() *migapi.DirectImageMigration {
 			SrcMigClusterRef:  t.PlanResources.MigPlan.Spec.SrcMigClusterRef,
 			DestMigClusterRef: t.PlanResources.MigPlan.Spec.DestMigClusterRef,
 			Namespaces:        t.PlanResources.MigPlan.Spec.Namespaces,
+			DestImageRegistry: t.PlanResources.MigPlan.Spec.DestImageRegistry,
 		},
 	}
 	migapi.SetOwnerReference(t.Owner, t.Owner, dim)
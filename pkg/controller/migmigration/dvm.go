@@ -11,11 +11,13 @@ import (
 	liberr "github.com/konveyor/controller/pkg/error"
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	dvmc "github.com/konveyor/mig-controller/pkg/controller/directvolumemigration"
+	migref "github.com/konveyor/mig-controller/pkg/reference"
 	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// The suffix of the destination PVCs of volumes migrated within their namespace.
 func (t *Task) createDirectVolumeMigration() error {
 	existingDvm, err := t.getDirectVolumeMigration()
 	if err != nil {
@@ -53,6 +55,7 @@ func (t *Task) buildDirectVolumeMigration() *migapi.DirectVolumeMigration {
 			DestMigClusterRef:           t.PlanResources.DestMigCluster.GetObjectReference(),
 			PersistentVolumeClaims:      *pvcList,
 			CreateDestinationNamespaces: true,
+			Transfer:                    t.PlanResources.MigPlan.Spec.Transfer,
 		},
 	}
 	migapi.SetOwnerReference(t.Owner, t.Owner, dvm)
@@ -173,8 +176,13 @@ func (t *Task) getDVMPodProgress(pods []*migapi.PodProgress, state string) []str
 }
 
 func (t *Task) getDirectVolumeClaimList() *[]migapi.PVCToMigrate {
+	plan := t.PlanResources.MigPlan
+	// Within a cluster, the volumes are transferred to the mapped namespaces.
+	// The plan validation rejects volumes copied within their own namespace.
+	intraCluster := migref.RefEquals(plan.Spec.SrcMigClusterRef, plan.Spec.DestMigClusterRef)
+	nsMapping := plan.GetNamespaceMapping()
 	pvcList := []migapi.PVCToMigrate{}
-	for _, pv := range t.PlanResources.MigPlan.Spec.PersistentVolumes.List {
+	for _, pv := range plan.Spec.PersistentVolumes.List {
 		if pv.Selection.Action != migapi.PvCopyAction || pv.Selection.CopyMethod != migapi.PvFilesystemCopyMethod {
 			continue
 		}
@@ -184,6 +192,10 @@ func (t *Task) getDirectVolumeClaimList() *[]migapi.PVCToMigrate {
 		if pv.Selection.AccessMode != "" {
 			accessModes = []kapi.PersistentVolumeAccessMode{pv.Selection.AccessMode}
 		}
+		targetNamespace := ""
+		if intraCluster && nsMapping[pv.PVC.Namespace] != pv.PVC.Namespace {
+			targetNamespace = nsMapping[pv.PVC.Namespace]
+		}
 		pvcList = append(pvcList, migapi.PVCToMigrate{
 			ObjectReference: &kapi.ObjectReference{
 				Name:      pv.PVC.Name,
@@ -192,6 +204,7 @@ func (t *Task) getDirectVolumeClaimList() *[]migapi.PVCToMigrate {
 			TargetStorageClass: pv.Selection.StorageClass,
 			TargetAccessModes:  accessModes,
 			Verify:             pv.Selection.Verify,
+			TargetNamespace:    targetNamespace,
 		})
 	}
 	if len(pvcList) > 0 {
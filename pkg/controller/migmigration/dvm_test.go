@@ -112,3 +112,42 @@ func TestTask_hasDirectVolumeMigrationCompleted(t1 *testing.T) {
 		})
 	}
 }
+
+func TestTask_getDirectVolumeClaimList(t *testing.T) {
+	host := &v1.ObjectReference{Namespace: "openshift-migration", Name: "host"}
+	pv := func(ns, name string) migapi.PV {
+		return migapi.PV{
+			PVC: migapi.PVC{Namespace: ns, Name: name},
+			Selection: migapi.Selection{
+				Action:     migapi.PvCopyAction,
+				CopyMethod: migapi.PvFilesystemCopyMethod,
+			},
+		}
+	}
+	plan := &migapi.MigPlan{}
+	plan.Spec.SrcMigClusterRef = host
+	plan.Spec.DestMigClusterRef = host
+	plan.Spec.Namespaces = []string{"ns1:ns4", "ns2:ns3"}
+	plan.Spec.PersistentVolumes.List = []migapi.PV{
+		pv("ns1", "data"),
+		pv("ns2", "data"),
+	}
+	task := &Task{PlanResources: &migapi.PlanResources{MigPlan: plan}}
+	list := task.getDirectVolumeClaimList()
+	if list == nil || len(*list) != 2 {
+		t.Fatalf("getDirectVolumeClaimList() = %v", list)
+	}
+	want := []string{
+		"ns4/data",
+		"ns3/data",
+	}
+	for i, pvc := range *list {
+		got := pvc.GetTargetNamespace() + "/" + pvc.GetTargetName()
+		if got != want[i] {
+			t.Errorf("getDirectVolumeClaimList()[%d] target = %v, want %v", i, got, want[i])
+		}
+		if !pvc.IsRenamed() {
+			t.Errorf("getDirectVolumeClaimList()[%d] not renamed", i)
+		}
+	}
+}
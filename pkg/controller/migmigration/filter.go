@@ -0,0 +1,28 @@
+package migmigration
+
+import (
+	liberr "github.com/konveyor/controller/pkg/error"
+	"github.com/konveyor/mig-controller/pkg/resourcefilter"
+	"github.com/konveyor/mig-controller/pkg/settings"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Get the plan resource filter.
+func (t *Task) resourceFilter() (*resourcefilter.Filter, error) {
+	filter, err := resourcefilter.New(t.PlanResources.MigPlan.Spec.ResourceFilters)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	return filter, nil
+}
+
+// Get whether the objects of a resource are included in the initial backup.
+func (t *Task) backupIncludesResource(gr schema.GroupResource) bool {
+	plan := t.PlanResources.MigPlan
+	if settings.ExcludedInitialResources.Contains(gr.Resource) ||
+		resourcefilter.Contains(plan.Status.ExcludedResources, gr) {
+		return false
+	}
+	included := plan.Status.IncludedResources
+	return len(included) == 0 || resourcefilter.Contains(included, gr)
+}
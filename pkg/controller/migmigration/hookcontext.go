@@ -0,0 +1,200 @@
+package migmigration
+
+import (
+	"encoding/json"
+	"strings"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Hook context.
+const (
+	// The context document key in the hook ConfigMap.
+	HookContextKey = "context.json"
+	// Where the context document is mounted in the hook container.
+	HookContextDir = "/tmp/hook-context"
+	// Where the hook writes the result document.
+	// The content is reported by the kubelet as the container termination message.
+	HookResultPath = "/dev/termination-log"
+)
+
+// Migration type of rollback migrations.
+// See StageMigration and FinalMigration.
+const RollbackMigration = "rollback"
+
+// HookContext describes the migration running a hook.
+// Mounted as a JSON document in the hook container.
+type HookContext struct {
+	Migration          string                 `json:"migration"`
+	Namespace          string                 `json:"namespace"`
+	Plan               string                 `json:"plan"`
+	Type               string                 `json:"type"`
+	Phase              string                 `json:"phase"`
+	SourceCluster      string                 `json:"sourceCluster"`
+	DestinationCluster string                 `json:"destinationCluster"`
+	Backup             string                 `json:"backup,omitempty"`
+	Namespaces         []HookContextNamespace `json:"namespaces"`
+	PersistentVolumes  []HookContextPV        `json:"persistentVolumes,omitempty"`
+}
+
+// HookContextNamespace a migrated namespace.
+type HookContextNamespace struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// HookContextPV a persistent volume and the selected action.
+type HookContextPV struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Claim        string `json:"claim"`
+	Action       string `json:"action,omitempty"`
+	CopyMethod   string `json:"copyMethod,omitempty"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// Get the migration type.
+func (t *Task) migrationType() string {
+	switch {
+	case t.rollback():
+		return RollbackMigration
+	case t.stage():
+		return StageMigration
+	default:
+		return FinalMigration
+	}
+}
+
+// Build the context of a hook.
+func (t *Task) hookContext(hook migapi.MigPlanHook, backup string) *HookContext {
+	plan := t.PlanResources.MigPlan
+	hookContext := &HookContext{
+		Migration:          t.Owner.Name,
+		Namespace:          t.Owner.Namespace,
+		Plan:               plan.Name,
+		Type:               t.migrationType(),
+		Phase:              hook.Phase,
+		SourceCluster:      t.PlanResources.SrcMigCluster.Name,
+		DestinationCluster: t.PlanResources.DestMigCluster.Name,
+		Backup:             backup,
+		Namespaces:         []HookContextNamespace{},
+	}
+	srcNamespaces := plan.GetSourceNamespaces()
+	destNamespaces := plan.GetDestinationNamespaces()
+	for i := range srcNamespaces {
+		hookContext.Namespaces = append(
+			hookContext.Namespaces,
+			HookContextNamespace{
+				Source:      srcNamespaces[i],
+				Destination: destNamespaces[i],
+			})
+	}
+	for _, pv := range plan.Spec.PersistentVolumes.List {
+		hookContext.PersistentVolumes = append(
+			hookContext.PersistentVolumes,
+			HookContextPV{
+				Name:         pv.Name,
+				Namespace:    pv.PVC.Namespace,
+				Claim:        pv.PVC.Name,
+				Action:       pv.Selection.Action,
+				CopyMethod:   pv.Selection.CopyMethod,
+				StorageClass: pv.Selection.StorageClass,
+			})
+	}
+
+	return hookContext
+}
+
+// Get the name of the velero backup of the migration.
+// Returns an empty string when the backup has not been created yet.
+func (t *Task) hookBackupName() (string, error) {
+	if t.rollback() {
+		return "", nil
+	}
+	backup, err := t.getInitialBackup()
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	if t.stage() || backup == nil {
+		backup, err = t.getStageBackup()
+		if err != nil {
+			return "", liberr.Wrap(err)
+		}
+	}
+	if backup == nil {
+		return "", nil
+	}
+	return backup.Name, nil
+}
+
+// Get the environment variables describing the hook context.
+func (r *HookContext) Env() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "MIGRATION_NAME", Value: r.Migration},
+		{Name: "MIGRATION_TYPE", Value: r.Type},
+		{Name: "MIGRATION_HOOK_PHASE", Value: r.Phase},
+		{Name: "MIGRATION_SOURCE_CLUSTER", Value: r.SourceCluster},
+		{Name: "MIGRATION_DESTINATION_CLUSTER", Value: r.DestinationCluster},
+		{Name: "MIGRATION_BACKUP_NAME", Value: r.Backup},
+		{Name: "MIGRATION_CONTEXT", Value: HookContextDir + "/" + HookContextKey},
+		{Name: "MIGRATION_RESULT", Value: HookResultPath},
+	}
+}
+
+// Encode the hook context as JSON.
+func (r *HookContext) Encode() (string, error) {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	return string(content), nil
+}
+
+// Provide the hook context to the job.
+// The context document is mounted from the hook ConfigMap and described
+// by environment variables.
+func addHookContext(job *batchv1.Job, hookContext *HookContext, configMap string) {
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(
+		podSpec.Volumes,
+		corev1.Volume{
+			Name: "hook-context",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: configMap,
+					},
+					Items: []corev1.KeyToPath{
+						{Key: HookContextKey, Path: HookContextKey},
+					},
+				},
+			},
+		})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(
+		container.VolumeMounts,
+		corev1.VolumeMount{
+			Name:      "hook-context",
+			MountPath: HookContextDir,
+		})
+	container.Env = append(container.Env, hookContext.Env()...)
+	container.TerminationMessagePath = HookResultPath
+	container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+}
+
+// Get the result document written by the hook.
+// Returns an empty string when the hook container has not terminated.
+func hookResult(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return strings.TrimSpace(status.State.Terminated.Message)
+		}
+		if status.LastTerminationState.Terminated != nil {
+			return strings.TrimSpace(status.LastTerminationState.Terminated.Message)
+		}
+	}
+	return ""
+}
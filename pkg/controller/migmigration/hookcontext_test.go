@@ -0,0 +1,145 @@
+package migmigration
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTask_hookContext(t *testing.T) {
+	task := &Task{
+		Owner: &migapi.MigMigration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-migration", Name: "migration"},
+			Spec:       migapi.MigMigrationSpec{Stage: true},
+		},
+		PlanResources: &migapi.PlanResources{
+			MigPlan: &migapi.MigPlan{
+				ObjectMeta: metav1.ObjectMeta{Name: "plan"},
+				Spec: migapi.MigPlanSpec{
+					Namespaces: []string{"src:dest", "same"},
+					PersistentVolumes: migapi.PersistentVolumes{
+						List: []migapi.PV{
+							{
+								Name: "pv1",
+								PVC:  migapi.PVC{Namespace: "src", Name: "data"},
+								Selection: migapi.Selection{
+									Action:     migapi.PvCopyAction,
+									CopyMethod: migapi.PvFilesystemCopyMethod,
+								},
+							},
+						},
+					},
+				},
+			},
+			SrcMigCluster:  &migapi.MigCluster{ObjectMeta: metav1.ObjectMeta{Name: "source"}},
+			DestMigCluster: &migapi.MigCluster{ObjectMeta: metav1.ObjectMeta{Name: "host"}},
+		},
+	}
+	hook := migapi.MigPlanHook{Phase: migapi.PreQuiesceHookPhase}
+	want := &HookContext{
+		Migration:          "migration",
+		Namespace:          "openshift-migration",
+		Plan:               "plan",
+		Type:               StageMigration,
+		Phase:              migapi.PreQuiesceHookPhase,
+		SourceCluster:      "source",
+		DestinationCluster: "host",
+		Backup:             "backup",
+		Namespaces: []HookContextNamespace{
+			{Source: "src", Destination: "dest"},
+			{Source: "same", Destination: "same"},
+		},
+		PersistentVolumes: []HookContextPV{
+			{
+				Name:       "pv1",
+				Namespace:  "src",
+				Claim:      "data",
+				Action:     migapi.PvCopyAction,
+				CopyMethod: migapi.PvFilesystemCopyMethod,
+			},
+		},
+	}
+	if got := task.hookContext(hook, "backup"); !reflect.DeepEqual(got, want) {
+		t.Errorf("hookContext() = %v, want %v", got, want)
+	}
+}
+
+func Test_addHookContext(t *testing.T) {
+	job := &batchv1.Job{
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "hook"}},
+				},
+			},
+		},
+	}
+	hookContext := &HookContext{Migration: "migration", Type: FinalMigration}
+	addHookContext(job, hookContext, "context")
+
+	podSpec := job.Spec.Template.Spec
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].ConfigMap.Name != "context" {
+		t.Errorf("addHookContext() volumes = %v", podSpec.Volumes)
+	}
+	container := podSpec.Containers[0]
+	if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != HookContextDir {
+		t.Errorf("addHookContext() volume mounts = %v", container.VolumeMounts)
+	}
+	env := map[string]string{}
+	for _, v := range container.Env {
+		env[v.Name] = v.Value
+	}
+	if env["MIGRATION_NAME"] != "migration" ||
+		env["MIGRATION_TYPE"] != FinalMigration ||
+		env["MIGRATION_CONTEXT"] != HookContextDir+"/"+HookContextKey ||
+		env["MIGRATION_RESULT"] != container.TerminationMessagePath {
+		t.Errorf("addHookContext() env = %v", env)
+	}
+}
+
+func Test_hookResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		status corev1.ContainerStatus
+		want   string
+	}{
+		{
+			name:   "running",
+			status: corev1.ContainerStatus{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			want:   "",
+		},
+		{
+			name: "terminated",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Message: "{\"flushed\": true}\n"},
+				},
+			},
+			want: "{\"flushed\": true}",
+		},
+		{
+			name: "restarted",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Message: "failed"},
+				},
+			},
+			want: "failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{tt.status}},
+			}
+			if got := hookResult(pod); got != tt.want {
+				t.Errorf("hookResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,12 @@ package migmigration
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 
@@ -12,66 +16,377 @@ import (
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const HookJobFailedLimit = 6
 const BackoffLimitExceededError = "BackoffLimitExceeded"
 
+// The number of log lines of a hook job pod recorded in the hook status.
+const HookLogsTailLines = 20
+
+// Run the hooks of a phase.
+// Hooks are run by ascending order and after their dependencies succeeded.
+// A hook is skipped when a dependency did not succeed. Failed hooks with
+// the Ignore policy are reported in a warning condition.
+// Returns `true` when all hooks completed, and an error when a hook with
+// the Fail or Rollback policy failed.
 func (t *Task) runHooks(hookPhase string) (bool, error) {
-	hook := migapi.MigPlanHook{}
-	var client k8sclient.Client
-	var err error
+	hooks := []migapi.MigPlanHook{}
+	for _, hook := range t.PlanResources.MigPlan.Spec.Hooks {
+		if hook.Phase == hookPhase && hook.Reference != nil {
+			hooks = append(hooks, hook)
+		}
+	}
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].Order < hooks[j].Order
+	})
+	for _, hook := range hooks {
+		t.Owner.Status.GetHook(hook.GetName(), hook.Phase)
+	}
 
-	for _, h := range t.PlanResources.MigPlan.Spec.Hooks {
-		if h.Phase == hookPhase {
-			hook = h
+	completed := true
+	var blockedOrder *int
+	for i := range hooks {
+		hook := hooks[i]
+		status := t.Owner.Status.FindHook(hook.GetName(), hook.Phase)
+		if status.Completed() {
+			continue
+		}
+		completed = false
+		if blockedOrder != nil && hook.Order > *blockedOrder {
+			continue
+		}
+		blockedOrder = &hook.Order
+		ready, skipped := t.hookDependenciesCompleted(hook)
+		if skipped != "" {
+			status.Message = fmt.Sprintf("Dependency %s did not succeed.", skipped)
+			status.MarkCompleted(migapi.HookSkipped)
+			continue
+		}
+		if !ready {
+			continue
+		}
+		_, err := t.runHook(hook)
+		if err == nil {
+			continue
+		}
+		status = t.Owner.Status.FindHook(hook.GetName(), hook.Phase)
+		if !status.Completed() {
+			return false, liberr.Wrap(err)
+		}
+		switch hook.GetFailurePolicy() {
+		case migapi.HookFailurePolicyIgnore:
+			status.Ignored = true
+			t.setIgnoredHookFailures()
+		case migapi.HookFailurePolicyRollback:
+			rbErr := t.ensureRollbackMigration()
+			if rbErr != nil {
+				return false, liberr.Wrap(rbErr)
+			}
+			return false, liberr.Wrap(err)
+		default:
+			return false, liberr.Wrap(err)
 		}
 	}
+	t.setHookProgress(hookPhase)
 
-	migHook := migapi.MigHook{}
+	return completed, nil
+}
 
-	if hook.Reference != nil {
-		err = t.Client.Get(
-			context.TODO(),
-			types.NamespacedName{
-				Name:      hook.Reference.Name,
-				Namespace: hook.Reference.Namespace,
+// Get whether the dependencies of a hook have completed.
+// A dependency without status has not started and is not completed.
+// Returns: `true` when all dependencies have completed and the name of
+// the first dependency which did not succeed.
+func (t *Task) hookDependenciesCompleted(hook migapi.MigPlanHook) (bool, string) {
+	completed := true
+	for _, name := range hook.DependsOn {
+		status := t.Owner.Status.FindHook(name, hook.Phase)
+		if status == nil || !status.Completed() {
+			completed = false
+			continue
+		}
+		if status.State != migapi.HookSucceeded {
+			return false, name
+		}
+	}
+	return completed, ""
+}
+
+// Report the state of the hooks of a phase in the step progress.
+func (t *Task) setHookProgress(hookPhase string) {
+	progress := []string{}
+	for _, status := range t.Owner.Status.Hooks {
+		if status.Phase != hookPhase {
+			continue
+		}
+		line := fmt.Sprintf("Hook %s: %s", status.Name, status.State)
+		if status.Job != nil {
+			line = fmt.Sprintf("Hook %s: Job %s/%s: %s", status.Name, status.Job.Namespace, status.Job.Name, status.State)
+		}
+		if status.Attempts > 1 {
+			line += fmt.Sprintf(" (attempt %d)", status.Attempts)
+		}
+		progress = append(progress, line)
+	}
+	t.setProgress(progress)
+}
+
+// Set the warning condition listing the failed hooks that were ignored.
+func (t *Task) setIgnoredHookFailures() {
+	ignored := []string{}
+	for _, status := range t.Owner.Status.Hooks {
+		if status.Ignored {
+			ignored = append(ignored, status.Phase+"/"+status.Name)
+		}
+	}
+	t.Owner.Status.SetCondition(migapi.Condition{
+		Type:     HookFailuresIgnored,
+		Status:   True,
+		Category: migapi.Warn,
+		Message:  "The hooks [] failed, the failures were ignored. See: status.hooks for details.",
+		Items:    ignored,
+		Durable:  true,
+	})
+}
+
+// Create a rollback migration for the plan.
+// Used when a hook with the Rollback policy failed. The rollback does not
+// have the correlation labels of the failed migration.
+func (t *Task) ensureRollbackMigration() error {
+	migration := migapi.MigMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: t.Owner.Namespace,
+			Name:      t.Owner.Name + "-rollback",
+			Labels: map[string]string{
+				migapi.PartOfLabel: migapi.Application,
 			},
-			&migHook)
+		},
+		Spec: migapi.MigMigrationSpec{
+			MigPlanRef: t.Owner.Spec.MigPlanRef,
+			Rollback:   true,
+		},
+	}
+	err := t.Client.Create(context.TODO(), &migration)
+	if err != nil && !k8serror.IsAlreadyExists(err) {
+		return liberr.Wrap(err)
+	}
+	t.Log.Info("Created rollback migration.", "name", migration.Name)
+
+	return nil
+}
+
+// Run the hook.
+// A failed hook job is created again, after a backoff, until the hook retries are exhausted.
+// Returns `true` when the hook job has succeeded or no hook is referenced.
+func (t *Task) runHook(hook migapi.MigPlanHook) (bool, error) {
+	if hook.Reference == nil {
+		return true, nil
+	}
+	status := t.Owner.Status.GetHook(hook.GetName(), hook.Phase)
+	switch status.State {
+	case migapi.HookSucceeded, migapi.HookSkipped:
+		return true, nil
+	case migapi.HookFailed:
+		return false, liberr.Wrap(errors.New(status.Message))
+	}
+
+	migHook := migapi.MigHook{}
+	err := t.Client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      hook.Reference.Name,
+			Namespace: hook.Reference.Namespace,
+		},
+		&migHook)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+
+	client, err := t.getHookClient(migHook)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+
+	svc := corev1.ServiceAccount{}
+	ref := types.NamespacedName{
+		Namespace: hook.ExecutionNamespace,
+		Name:      hook.ServiceAccount,
+	}
+	err = client.Get(context.TODO(), ref, &svc)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+
+	runningJob, err := migHook.GetPhaseJob(client, hook.Phase, string(t.Owner.UID))
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	if runningJob == nil {
+		if status.LastFailureTimestamp != nil &&
+			time.Since(status.LastFailureTimestamp.Time) < hook.GetRetryBackoff(status.Attempts) {
+			return false, nil
+		}
+		job, err := t.prepareJob(hook, migHook, client)
 		if err != nil {
 			return false, liberr.Wrap(err)
 		}
-
-		client, err = t.getHookClient(migHook)
+		err = client.Create(context.TODO(), job)
 		if err != nil {
 			return false, liberr.Wrap(err)
 		}
+		if status.StartTimestamp == nil {
+			status.StartTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		status.Attempts++
+		status.State = migapi.HookRunning
+		return false, nil
+	}
+	if runningJob.DeletionTimestamp != nil {
+		// Failed attempt being deleted.
+		return false, nil
+	}
+	status.Job = &corev1.ObjectReference{
+		Namespace: runningJob.Namespace,
+		Name:      runningJob.Name,
+	}
 
-		svc := corev1.ServiceAccount{}
-		ref := types.NamespacedName{
-			Namespace: hook.ExecutionNamespace,
-			Name:      hook.ServiceAccount,
+	switch {
+	case hookJobFailed(runningJob):
+		t.recordHookOutput(status, migHook, client, runningJob)
+		status.LastFailureTimestamp = &metav1.Time{Time: time.Now()}
+		if status.Attempts <= hook.Retries {
+			t.Log.Info("Hook job failed, retrying.",
+				"hook", hook.GetName(),
+				"job", runningJob.Name,
+				"attempts", status.Attempts)
+			err = client.Delete(context.TODO(), runningJob,
+				k8sclient.PropagationPolicy(metav1.DeletePropagationForeground))
+			if err != nil && !k8serror.IsNotFound(err) {
+				return false, liberr.Wrap(err)
+			}
+			status.State = migapi.HookRetrying
+			return false, nil
 		}
-		err = client.Get(context.TODO(), ref, &svc)
+		err = deleteHookSourceSecret(client, migHook, hook.Phase, string(t.Owner.UID))
 		if err != nil {
 			return false, liberr.Wrap(err)
 		}
-
-		job, err := t.prepareJob(hook, migHook, client)
+		status.Message = fmt.Sprintf("Hook job %s failed.", runningJob.Name)
+		status.MarkCompleted(migapi.HookFailed)
+		return false, liberr.Wrap(errors.New(status.Message))
+	case runningJob.Status.Succeeded > 0:
+		t.recordHookOutput(status, migHook, client, runningJob)
+		err = deleteHookSourceSecret(client, migHook, hook.Phase, string(t.Owner.UID))
 		if err != nil {
 			return false, liberr.Wrap(err)
 		}
+		status.Message = ""
+		status.MarkCompleted(migapi.HookSucceeded)
+		return true, nil
+	default:
+		status.State = migapi.HookRunning
+		return false, nil
+	}
+}
 
-		result, err := t.ensureJob(job, hook, migHook, client)
-		if err != nil {
-			return false, liberr.Wrap(err)
+// Get whether the hook job has failed.
+func hookJobFailed(job *batchv1.Job) bool {
+	if job.Status.Failed >= HookJobFailedLimit {
+		return true
+	}
+	for _, cnd := range job.Status.Conditions {
+		if cnd.Type == batchv1.JobFailed && cnd.Status == corev1.ConditionTrue {
+			return true
 		}
+		if cnd.Reason == BackoffLimitExceededError {
+			return true
+		}
+	}
+	return false
+}
 
-		return result, nil
+// Record the result document and the last log lines of the latest pod of a hook job.
+func (t *Task) recordHookOutput(status *migapi.HookStatus, migHook migapi.MigHook, client k8sclient.Client, job *batchv1.Job) {
+	pod, err := hookJobPod(client, job)
+	if err != nil {
+		t.Log.Info("Hook pod not found.", "job", job.Name, "error", err.Error())
+		return
 	}
-	return true, nil
+	if pod == nil {
+		return
+	}
+	status.Result = hookResult(pod)
+	status.LogsTail = t.hookLogsTail(migHook, pod)
+}
+
+// Get the latest pod of a hook job.
+func hookJobPod(client k8sclient.Client, job *batchv1.Job) (*corev1.Pod, error) {
+	list := corev1.PodList{}
+	err := client.List(
+		context.TODO(),
+		&k8sclient.ListOptions{
+			Namespace:     job.Namespace,
+			LabelSelector: k8slabels.SelectorFromSet(k8slabels.Set{"job-name": job.Name}),
+		},
+		&list)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	pod := &list.Items[0]
+	for i := range list.Items {
+		if list.Items[i].CreationTimestamp.After(pod.CreationTimestamp.Time) {
+			pod = &list.Items[i]
+		}
+	}
+	return pod, nil
+}
+
+// Get the last lines logged by a hook pod.
+// Failures are logged and an empty string returned since the logs are informational.
+func (t *Task) hookLogsTail(migHook migapi.MigHook, pod *corev1.Pod) string {
+	cluster := t.PlanResources.SrcMigCluster
+	if migHook.Spec.TargetCluster == "destination" {
+		cluster = t.PlanResources.DestMigCluster
+	}
+	restCfg, err := cluster.BuildRestConfig(t.Client)
+	if err != nil {
+		t.Log.Info("Hook logs not collected.", "error", err.Error())
+		return ""
+	}
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		t.Log.Info("Hook logs not collected.", "error", err.Error())
+		return ""
+	}
+	tailLines := int64(HookLogsTailLines)
+	logs, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		TailLines: &tailLines,
+	}).DoRaw()
+	if err != nil {
+		t.Log.Info("Hook logs not collected.", "pod", pod.Name, "error", err.Error())
+		return ""
+	}
+	return string(logs)
+}
+
+// Get the hooks referenced by the plan, including the quiesce group drain hooks.
+func (t *Task) planHooks() []migapi.MigPlanHook {
+	hooks := append([]migapi.MigPlanHook{}, t.PlanResources.MigPlan.Spec.Hooks...)
+	for i := range t.PlanResources.MigPlan.Spec.QuiesceGroups {
+		hook := t.PlanResources.MigPlan.Spec.QuiesceGroups[i].GetDrainHook()
+		if hook != nil {
+			hooks = append(hooks, *hook)
+		}
+	}
+	return hooks
 }
 
 func (t *Task) stopHookJobs() (bool, error) {
@@ -79,7 +394,7 @@ func (t *Task) stopHookJobs() (bool, error) {
 	var err error
 
 	migHook := migapi.MigHook{}
-	for _, hook := range t.PlanResources.MigPlan.Spec.Hooks {
+	for _, hook := range t.planHooks() {
 		if hook.Reference == nil {
 			continue
 		}
@@ -91,6 +406,9 @@ func (t *Task) stopHookJobs() (bool, error) {
 				Namespace: hook.Reference.Namespace,
 			},
 			&migHook)
+		if k8serror.IsNotFound(err) {
+			continue
+		}
 		if err != nil {
 			return false, liberr.Wrap(err)
 		}
@@ -102,7 +420,10 @@ func (t *Task) stopHookJobs() (bool, error) {
 
 		// Get the job for the hook and kill it.
 		runningJob, err := migHook.GetPhaseJob(client, hook.Phase, string(t.Owner.UID))
-		if runningJob == nil && err == nil {
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		if runningJob == nil {
 			// No active Job for hook
 			continue
 		} else {
@@ -117,60 +438,50 @@ func (t *Task) stopHookJobs() (bool, error) {
 	return true, nil
 }
 
-func (t *Task) ensureJob(job *batchv1.Job, hook migapi.MigPlanHook, migHook migapi.MigHook, client k8sclient.Client) (bool, error) {
-	runningJob, err := migHook.GetPhaseJob(client, hook.Phase, string(t.Owner.UID))
-	if runningJob == nil && err == nil {
-		err = client.Create(context.TODO(), job)
-		if err != nil {
-			return false, err
-		}
-		return false, nil
-	} else if err != nil {
-		return false, err
-	} else if runningJob.Status.Failed >= HookJobFailedLimit {
-		err := fmt.Errorf("Hook job %s failed.", runningJob.Name)
-		t.setProgress([]string{
-			fmt.Sprintf("Job %s/%s: Failed", runningJob.Namespace, runningJob.Name)})
-		return false, err
-	} else if len(runningJob.Status.Conditions) > 0 && runningJob.Status.Conditions[0].Reason == BackoffLimitExceededError {
-		err := fmt.Errorf("Hook job %s failed.", runningJob.Name)
-		t.setProgress([]string{
-			fmt.Sprintf("Job %s/%s: Failed", runningJob.Namespace, runningJob.Name)})
-		return false, err
-	} else if runningJob.Status.Succeeded == 1 {
-		t.setProgress([]string{
-			fmt.Sprintf("Job %s/%s: Succeeded", runningJob.Namespace, runningJob.Name)})
-		return true, nil
-	} else {
-		t.setProgress([]string{
-			fmt.Sprintf("Job %s/%s: Running", runningJob.Namespace, runningJob.Name)})
-		return false, nil
-	}
-}
-
 func (t *Task) prepareJob(hook migapi.MigPlanHook, migHook migapi.MigHook, client k8sclient.Client) (*batchv1.Job, error) {
 	job := &batchv1.Job{}
 
-	if migHook.Spec.Custom {
-		job = t.baseJobTemplate(hook, migHook)
-	} else {
+	err := validateHookSource(migHook)
+	if err != nil {
+		return nil, err
+	}
 
-		configMap, err := t.configMapTemplate(hook, migHook)
+	backup, err := t.hookBackupName()
+	if err != nil {
+		return nil, err
+	}
+	hookContext := t.hookContext(hook, backup)
+
+	configMap, err := migHook.GetPhaseConfigMap(client, hook.Phase, string(t.Owner.UID))
+	if configMap == nil && err == nil {
+		configMap, err = t.configMapTemplate(hook, migHook, hookContext)
 		if err != nil {
 			return nil, err
 		}
+		err = client.Create(context.TODO(), configMap)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
 
-		phaseConfigMap, err := migHook.GetPhaseConfigMap(client, hook.Phase, string(t.Owner.UID))
-		if phaseConfigMap == nil && err == nil {
+	if migHook.Spec.Custom {
+		job = t.baseJobTemplate(hook, migHook)
+	} else {
+		job = t.playbookJobTemplate(hook, migHook, configMap.Name)
+	}
+	addHookContext(job, hookContext, configMap.Name)
 
-			err = client.Create(context.TODO(), configMap)
+	if migHook.Spec.Source != nil {
+		secret := ""
+		if migHook.Spec.Source.Git != nil {
+			secret, err = t.ensureHookSourceSecret(hook, migHook, client)
 			if err != nil {
 				return nil, err
 			}
-		} else if err != nil {
-			return nil, err
 		}
-		job = t.playbookJobTemplate(hook, migHook, configMap.Name)
+		addHookSource(job, migHook, configMap.Name, secret)
 	}
 
 	return job, nil
@@ -198,16 +509,36 @@ func (t *Task) getHookClient(migHook migapi.MigHook) (k8sclient.Client, error) {
 	return client, nil
 }
 
-func (t *Task) configMapTemplate(hook migapi.MigPlanHook, migHook migapi.MigHook) (*corev1.ConfigMap, error) {
+func (t *Task) configMapTemplate(hook migapi.MigPlanHook, migHook migapi.MigHook, hookContext *HookContext) (*corev1.ConfigMap, error) {
 
 	labels := migHook.GetCorrelationLabels()
 	labels[migapi.HookPhaseLabel] = hook.Phase
 	labels[migapi.HookOwnerLabel] = string(t.Owner.UID)
 
-	playbookData, err := base64.StdEncoding.DecodeString(migHook.Spec.Playbook)
+	contextData, err := hookContext.Encode()
 	if err != nil {
 		return nil, err
 	}
+	data := map[string]string{
+		HookContextKey: contextData,
+	}
+
+	switch {
+	case migHook.Spec.Source != nil:
+		if migHook.Spec.Source.OCI != nil {
+			content, err := t.fetchHookSource(migHook)
+			if err != nil {
+				return nil, err
+			}
+			data[HookSourceKey] = content
+		}
+	case !migHook.Spec.Custom:
+		playbookData, err := base64.StdEncoding.DecodeString(migHook.Spec.Playbook)
+		if err != nil {
+			return nil, err
+		}
+		data["playbook.yml"] = string(playbookData)
+	}
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -215,24 +546,32 @@ func (t *Task) configMapTemplate(hook migapi.MigPlanHook, migHook migapi.MigHook
 			GenerateName: strings.ToLower(t.PlanResources.MigPlan.Name + "-" + hook.Phase + "-"),
 			Labels:       labels,
 		},
-		Data: map[string]string{
-			"playbook.yml": string(playbookData),
-		},
+		Data: data,
 	}, nil
 }
 
 func (t *Task) playbookJobTemplate(hook migapi.MigPlanHook, migHook migapi.MigHook, configMap string) *batchv1.Job {
 	jobTemplate := t.baseJobTemplate(hook, migHook)
 
+	playbook := "/tmp/playbook/playbook.yml"
+	if migHook.Spec.Source != nil {
+		playbook = hookSourcePath(migHook.Spec.Source)
+	}
+
 	jobTemplate.Spec.Template.Spec.Containers[0].Command = []string{
 		"/bin/entrypoint",
 		"ansible-runner",
 		"-p",
-		"/tmp/playbook/playbook.yml",
+		playbook,
 		"run",
 		"/tmp/runner",
 	}
 
+	// The playbook is mounted with the hook source.
+	if migHook.Spec.Source != nil {
+		return jobTemplate
+	}
+
 	jobTemplate.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
 		{
 			Name:      "playbook",
@@ -248,6 +587,9 @@ func (t *Task) playbookJobTemplate(hook migapi.MigPlanHook, migHook migapi.MigHo
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: configMap,
 					},
+					Items: []corev1.KeyToPath{
+						{Key: "playbook.yml", Path: "playbook.yml"},
+					},
 				},
 			},
 		},
@@ -262,6 +604,9 @@ func (t *Task) baseJobTemplate(hook migapi.MigPlanHook, migHook migapi.MigHook)
 	if migHook.Spec.ActiveDeadlineSeconds != 0 {
 		deadlineSeconds = migHook.Spec.ActiveDeadlineSeconds
 	}
+	if hook.Timeout != nil {
+		deadlineSeconds = int64(math.Ceil(hook.Timeout.Seconds()))
+	}
 
 	labels := migHook.GetCorrelationLabels()
 	labels[migapi.HookPhaseLabel] = hook.Phase
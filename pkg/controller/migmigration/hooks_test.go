@@ -0,0 +1,183 @@
+package migmigration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_hookJobFailed(t *testing.T) {
+	tests := []struct {
+		name   string
+		status batchv1.JobStatus
+		want   bool
+	}{
+		{
+			name:   "running",
+			status: batchv1.JobStatus{Active: 1, Failed: 2},
+			want:   false,
+		},
+		{
+			name:   "failed limit",
+			status: batchv1.JobStatus{Failed: HookJobFailedLimit},
+			want:   true,
+		},
+		{
+			name: "deadline exceeded",
+			status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded"},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "backoff limit exceeded",
+			status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Reason: BackoffLimitExceededError},
+				},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hookJobFailed(&batchv1.Job{Status: tt.status}); got != tt.want {
+				t.Errorf("hookJobFailed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_hookDependenciesCompleted(t *testing.T) {
+	phase := migapi.PreBackupHookPhase
+	task := &Task{
+		Owner: &migapi.MigMigration{
+			Status: migapi.MigMigrationStatus{
+				Hooks: []migapi.HookStatus{
+					{Name: "succeeded", Phase: phase, State: migapi.HookSucceeded},
+					{Name: "failed", Phase: phase, State: migapi.HookFailed, Ignored: true},
+					{Name: "running", Phase: phase, State: migapi.HookRunning},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		dependsOn   []string
+		wantReady   bool
+		wantSkipped string
+	}{
+		{name: "none", wantReady: true},
+		{name: "succeeded", dependsOn: []string{"succeeded"}, wantReady: true},
+		{name: "running", dependsOn: []string{"succeeded", "running"}, wantReady: false},
+		{name: "failed", dependsOn: []string{"running", "failed"}, wantReady: false, wantSkipped: "failed"},
+		{name: "not started", dependsOn: []string{"succeeded", "pending"}, wantReady: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := migapi.MigPlanHook{Phase: phase, DependsOn: tt.dependsOn}
+			ready, skipped := task.hookDependenciesCompleted(hook)
+			if ready != tt.wantReady || skipped != tt.wantSkipped {
+				t.Errorf("hookDependenciesCompleted() = %v, %q, want %v, %q", ready, skipped, tt.wantReady, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestMigPlanHook_GetRetryBackoff(t *testing.T) {
+	hook := migapi.MigPlanHook{}
+	tests := []struct {
+		attempts int
+		want     string
+	}{
+		{attempts: 1, want: "30s"},
+		{attempts: 2, want: "1m0s"},
+		{attempts: 3, want: "2m0s"},
+	}
+	for _, tt := range tests {
+		if got := hook.GetRetryBackoff(tt.attempts).String(); got != tt.want {
+			t.Errorf("GetRetryBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestTask_baseJobTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout *metav1.Duration
+		want    int64
+	}{
+		{
+			name: "mighook deadline",
+			want: 600,
+		},
+		{
+			name:    "timeout",
+			timeout: &metav1.Duration{Duration: time.Minute},
+			want:    60,
+		},
+		{
+			name:    "timeout rounded up",
+			timeout: &metav1.Duration{Duration: time.Millisecond * 1500},
+			want:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{
+				Owner: &migapi.MigMigration{},
+				PlanResources: &migapi.PlanResources{
+					MigPlan: &migapi.MigPlan{},
+				},
+			}
+			hook := migapi.MigPlanHook{Phase: migapi.PreBackupHookPhase, Timeout: tt.timeout}
+			migHook := migapi.MigHook{Spec: migapi.MigHookSpec{ActiveDeadlineSeconds: 600}}
+			job := task.baseJobTemplate(hook, migHook)
+			got := job.Spec.Template.Spec.ActiveDeadlineSeconds
+			if got == nil || *got != tt.want {
+				t.Errorf("baseJobTemplate() activeDeadlineSeconds = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_ensureRollbackMigration(t *testing.T) {
+	owner := &migapi.MigMigration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-migration", Name: "final", UID: "uid-0"},
+		Spec: migapi.MigMigrationSpec{
+			MigPlanRef: &corev1.ObjectReference{Namespace: "openshift-migration", Name: "plan"},
+		},
+	}
+	s := runtime.NewScheme()
+	if err := migapi.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	client := fake.NewFakeClientWithScheme(s)
+	task := &Task{Log: log.WithName("test_ensureRollbackMigration"), Client: client, Owner: owner}
+	err := task.ensureRollbackMigration()
+	if err != nil {
+		t.Fatalf("ensureRollbackMigration() error = %v", err)
+	}
+	rollback := migapi.MigMigration{}
+	err = client.Get(context.TODO(), types.NamespacedName{Namespace: "openshift-migration", Name: "final-rollback"}, &rollback)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !rollback.Spec.Rollback {
+		t.Errorf("ensureRollbackMigration() not a rollback")
+	}
+	key, _ := owner.GetCorrelationLabel()
+	if _, found := rollback.Labels[key]; found {
+		t.Errorf("ensureRollbackMigration() labels = %v", rollback.Labels)
+	}
+}
@@ -0,0 +1,291 @@
+package migmigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/hooksource"
+	"github.com/konveyor/mig-controller/pkg/settings"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Hook source.
+// The source is fetched in a volume mounted in the hook container.
+// OCI artifacts are fetched by the controller and stored in the hook
+// configMap. Git repositories are cloned at the pinned commit by an init
+// container using the hook source image from the settings.
+const (
+	HookSourceKey    = "source"
+	HookSourceDir    = "/tmp/hook-source"
+	HookSourceGitDir = "/tmp/hook-source/git"
+	HookSourceVolume = "hook-source"
+)
+
+// Clone the repository and checkout the pinned commit.
+// The credentials (when set) are provided by a credential helper.
+const gitCloneScript = `set -e
+git -c credential.helper='!f() { test -n "$GIT_USERNAME" && echo "username=$GIT_USERNAME" && echo "password=$GIT_PASSWORD"; }; f' \
+  clone --quiet --no-checkout "$GIT_URL" ` + HookSourceGitDir + `
+cd ` + HookSourceGitDir + `
+git checkout --quiet "$GIT_REVISION"
+test -f "$GIT_PATH"
+`
+
+// Get the path of the hook source file in the hook container.
+func hookSourcePath(source *migapi.HookSource) string {
+	if source.Git != nil {
+		return path.Join(HookSourceGitDir, source.Git.Path)
+	}
+	return path.Join(HookSourceDir, path.Base(source.OCI.Path))
+}
+
+// Validate the hook source has been resolved.
+// Hooks are run using the pinned revision only.
+func validateHookSource(migHook migapi.MigHook) error {
+	if migHook.Spec.Source == nil {
+		return nil
+	}
+	if !migHook.SourceResolved() {
+		return liberr.Wrap(
+			fmt.Errorf(
+				"source of hook %s/%s not resolved",
+				migHook.Namespace,
+				migHook.Name))
+	}
+	return nil
+}
+
+// Get the credentials used to fetch the hook source.
+func (t *Task) hookSourceCredentials(migHook migapi.MigHook) (*hooksource.Credentials, error) {
+	ref := migHook.Spec.Source.CredentialsSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+	if ref.Namespace != migHook.Namespace {
+		return nil, liberr.Wrap(errors.New("hook source credentials secret not in the hook namespace"))
+	}
+	secret, err := migapi.GetSecret(t.Client, ref)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	if secret == nil {
+		return nil, liberr.Wrap(errors.New("hook source credentials secret not found"))
+	}
+	credentials := hooksource.SecretCredentials(secret)
+	if credentials == nil {
+		return nil, liberr.Wrap(errors.New("hook source credentials secret not valid"))
+	}
+	return credentials, nil
+}
+
+// Fetch the file of an OCI hook source at the pinned digest.
+func (t *Task) fetchHookSource(migHook migapi.MigHook) (string, error) {
+	source := migHook.Spec.Source
+	credentials, err := t.hookSourceCredentials(migHook)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	content, err := hooksource.FetchOCI(
+		source.OCI.Reference,
+		migHook.Status.Source.Revision,
+		source.OCI.Path,
+		credentials)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	return string(content), nil
+}
+
+// Ensure the Git credentials secret exists in the execution namespace.
+// Returns the secret name or "" when no credentials are needed.
+func (t *Task) ensureHookSourceSecret(hook migapi.MigPlanHook, migHook migapi.MigHook, client k8sclient.Client) (string, error) {
+	if migHook.Spec.Source.CredentialsSecretRef == nil {
+		return "", nil
+	}
+	secret, err := migHook.GetPhaseSecret(client, hook.Phase, string(t.Owner.UID))
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	if secret != nil {
+		return secret.Name, nil
+	}
+	credentials, err := t.hookSourceCredentials(migHook)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	labels := migHook.GetCorrelationLabels()
+	labels[migapi.HookPhaseLabel] = hook.Phase
+	labels[migapi.HookOwnerLabel] = string(t.Owner.UID)
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    hook.ExecutionNamespace,
+			GenerateName: strings.ToLower(t.PlanResources.MigPlan.Name + "-" + hook.Phase + "-"),
+			Labels:       labels,
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		StringData: map[string]string{
+			corev1.BasicAuthUsernameKey: credentials.Username,
+			corev1.BasicAuthPasswordKey: credentials.Password,
+		},
+	}
+	err = client.Create(context.TODO(), secret)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	return secret.Name, nil
+}
+
+// Delete the Git credentials secret created for a hook phase by a migration.
+func deleteHookSourceSecret(client k8sclient.Client, migHook migapi.MigHook, phase, owner string) error {
+	secret, err := migHook.GetPhaseSecret(client, phase, owner)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if secret == nil {
+		return nil
+	}
+	err = client.Delete(context.TODO(), secret)
+	if err != nil && !k8serror.IsNotFound(err) {
+		return liberr.Wrap(err)
+	}
+	return nil
+}
+
+// Delete the Git credentials secrets of the plan hooks.
+// The secrets left by any migration of the plan are deleted.
+func (t *Task) deleteHookSourceSecrets() error {
+	owners := []string{string(t.Owner.UID)}
+	migrations, err := t.PlanResources.MigPlan.ListMigrations(t.Client)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for _, migration := range migrations {
+		if migration.UID != t.Owner.UID {
+			owners = append(owners, string(migration.UID))
+		}
+	}
+	for _, hook := range t.planHooks() {
+		if hook.Reference == nil {
+			continue
+		}
+		migHook := migapi.MigHook{}
+		err = t.Client.Get(
+			context.TODO(),
+			types.NamespacedName{
+				Name:      hook.Reference.Name,
+				Namespace: hook.Reference.Namespace,
+			},
+			&migHook)
+		if k8serror.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if migHook.Spec.Source == nil || migHook.Spec.Source.Git == nil {
+			continue
+		}
+		client, err := t.getHookClient(migHook)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		for _, owner := range owners {
+			err = deleteHookSourceSecret(client, migHook, hook.Phase, owner)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+		}
+	}
+	return nil
+}
+
+// Add the hook source volume to the job.
+// The MIGRATION_HOOK_SOURCE env var is set to the path of the source file.
+func addHookSource(job *batchv1.Job, migHook migapi.MigHook, configMap, secret string) {
+	source := migHook.Spec.Source
+	podSpec := &job.Spec.Template.Spec
+	container := &podSpec.Containers[0]
+	volume := corev1.Volume{Name: HookSourceVolume}
+	if source.Git != nil {
+		volume.VolumeSource = corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		}
+		podSpec.InitContainers = append(
+			podSpec.InitContainers,
+			gitCloneContainer(migHook, secret))
+	} else {
+		volume.VolumeSource = corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMap,
+				},
+				Items: []corev1.KeyToPath{
+					{Key: HookSourceKey, Path: path.Base(source.OCI.Path)},
+				},
+			},
+		}
+	}
+	podSpec.Volumes = append(podSpec.Volumes, volume)
+	container.VolumeMounts = append(
+		container.VolumeMounts,
+		corev1.VolumeMount{
+			Name:      HookSourceVolume,
+			MountPath: HookSourceDir,
+		})
+	container.Env = append(
+		container.Env,
+		corev1.EnvVar{
+			Name:  "MIGRATION_HOOK_SOURCE",
+			Value: hookSourcePath(source),
+		})
+}
+
+// Build the init container cloning the Git hook source.
+func gitCloneContainer(migHook migapi.MigHook, secret string) corev1.Container {
+	source := migHook.Spec.Source.Git
+	env := []corev1.EnvVar{
+		{Name: "GIT_URL", Value: source.URL},
+		{Name: "GIT_REVISION", Value: migHook.Status.Source.Revision},
+		{Name: "GIT_PATH", Value: source.Path},
+	}
+	if secret != "" {
+		for _, key := range []string{
+			corev1.BasicAuthUsernameKey,
+			corev1.BasicAuthPasswordKey,
+		} {
+			env = append(env, corev1.EnvVar{
+				Name: "GIT_" + strings.ToUpper(key),
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: secret,
+						},
+						Key: key,
+					},
+				},
+			})
+		}
+	}
+	return corev1.Container{
+		Name:    "git-clone",
+		Image:   settings.Settings.Hook.SourceImage,
+		Command: []string{"/bin/sh", "-c", gitCloneScript},
+		Env:     env,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      HookSourceVolume,
+				MountPath: HookSourceDir,
+			},
+		},
+	}
+}
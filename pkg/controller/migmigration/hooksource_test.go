@@ -0,0 +1,132 @@
+package migmigration
+
+import (
+	"context"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/settings"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_addHookSource(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         *migapi.HookSource
+		secret         string
+		wantPath       string
+		wantInit       bool
+		wantSecretEnvs int
+	}{
+		{
+			name: "oci",
+			source: &migapi.HookSource{
+				OCI: &migapi.OCIHookSource{Reference: "quay.io/konveyor/hooks:v1", Path: "hooks/playbook.yml"},
+			},
+			wantPath: "/tmp/hook-source/playbook.yml",
+		},
+		{
+			name: "git",
+			source: &migapi.HookSource{
+				Git: &migapi.GitHookSource{URL: "https://github.com/konveyor/hooks.git", Path: "hooks/playbook.yml"},
+			},
+			wantPath: "/tmp/hook-source/git/hooks/playbook.yml",
+			wantInit: true,
+		},
+		{
+			name: "git with credentials",
+			source: &migapi.HookSource{
+				Git: &migapi.GitHookSource{URL: "https://github.com/konveyor/hooks.git", Path: "run.sh"},
+			},
+			secret:         "credentials",
+			wantPath:       "/tmp/hook-source/git/run.sh",
+			wantInit:       true,
+			wantSecretEnvs: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migHook := migapi.MigHook{
+				Spec: migapi.MigHookSpec{Image: "hook-runner", Source: tt.source},
+				Status: migapi.MigHookStatus{
+					Source: &migapi.ResolvedHookSource{Revision: "1111111111111111111111111111111111111111"},
+				},
+			}
+			job := &batchv1.Job{}
+			job.Spec.Template.Spec.Containers = []corev1.Container{{Name: "hook"}}
+			addHookSource(job, migHook, "hook-config", tt.secret)
+
+			podSpec := job.Spec.Template.Spec
+			if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].Name != HookSourceVolume {
+				t.Fatalf("addHookSource() volumes = %v", podSpec.Volumes)
+			}
+			env := map[string]string{}
+			for _, v := range podSpec.Containers[0].Env {
+				env[v.Name] = v.Value
+			}
+			if env["MIGRATION_HOOK_SOURCE"] != tt.wantPath {
+				t.Errorf("addHookSource() source path = %v, want %v", env["MIGRATION_HOOK_SOURCE"], tt.wantPath)
+			}
+			if (len(podSpec.InitContainers) == 1) != tt.wantInit {
+				t.Fatalf("addHookSource() init containers = %v, want %v", len(podSpec.InitContainers), tt.wantInit)
+			}
+			if !tt.wantInit {
+				return
+			}
+			if podSpec.InitContainers[0].Image != settings.Settings.Hook.SourceImage {
+				t.Errorf("addHookSource() init image = %v, want %v", podSpec.InitContainers[0].Image, settings.Settings.Hook.SourceImage)
+			}
+			secretEnvs := 0
+			for _, v := range podSpec.InitContainers[0].Env {
+				if v.ValueFrom != nil && v.ValueFrom.SecretKeyRef.Name == tt.secret {
+					secretEnvs++
+				}
+			}
+			if secretEnvs != tt.wantSecretEnvs {
+				t.Errorf("addHookSource() secret env vars = %v, want %v", secretEnvs, tt.wantSecretEnvs)
+			}
+		})
+	}
+}
+
+func Test_deleteHookSourceSecret(t *testing.T) {
+	migHook := migapi.MigHook{
+		ObjectMeta: metav1.ObjectMeta{Name: "hook", Namespace: migapi.OpenshiftMigrationNamespace, UID: "hook-uid"},
+	}
+	secret := func(name, phase, owner string) *corev1.Secret {
+		labels := migHook.GetCorrelationLabels()
+		labels[migapi.HookPhaseLabel] = phase
+		labels[migapi.HookOwnerLabel] = owner
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "hooks", Labels: labels},
+		}
+	}
+	client := fake.NewFakeClient(
+		secret("pre-backup", migapi.PreBackupHookPhase, "migration-1"),
+		secret("post-backup", migapi.PostBackupHookPhase, "migration-1"),
+		secret("other-migration", migapi.PreBackupHookPhase, "migration-2"))
+
+	err := deleteHookSourceSecret(client, migHook, migapi.PreBackupHookPhase, "migration-1")
+	if err != nil {
+		t.Fatalf("deleteHookSourceSecret() error = %v", err)
+	}
+	// Deleting a removed secret is not an error.
+	err = deleteHookSourceSecret(client, migHook, migapi.PreBackupHookPhase, "migration-1")
+	if err != nil {
+		t.Fatalf("deleteHookSourceSecret() error = %v", err)
+	}
+	for name, want := range map[string]bool{
+		"pre-backup":      false,
+		"post-backup":     true,
+		"other-migration": true,
+	} {
+		err = client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "hooks"}, &corev1.Secret{})
+		if found := err == nil; found != want {
+			t.Errorf("deleteHookSourceSecret() secret %s found = %v, want %v", name, found, want)
+		}
+	}
+}
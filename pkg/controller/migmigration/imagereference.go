@@ -0,0 +1,209 @@
+package migmigration
+
+import (
+	"context"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	ocappsv1 "github.com/openshift/api/apps/v1"
+	"github.com/openshift/library-go/pkg/image/reference"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Rewrite image references in migrated workloads on the destination cluster
+// to the registry the images have been migrated to by the DirectImageMigration.
+func (t *Task) rewriteImageReferences() error {
+	dim, err := t.getDirectImageMigration()
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if dim == nil || len(dim.Status.ImageMappings) == 0 {
+		return nil
+	}
+	client, err := t.getDestinationClient()
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	mappings := dim.Status.ImageMappings
+	for _, ns := range t.destinationNamespaces() {
+		err = t.rewriteDeploymentImages(client, ns, mappings)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		err = t.rewriteDeploymentConfigImages(client, ns, mappings)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		err = t.rewriteStatefulSetImages(client, ns, mappings)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		err = t.rewriteCronJobImages(client, ns, mappings)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Rewrite image references in Deployments.
+func (t *Task) rewriteDeploymentImages(client k8sclient.Client, ns string, mappings []migapi.ImageMapping) error {
+	list := appsv1.DeploymentList{}
+	err := client.List(context.TODO(), k8sclient.InNamespace(ns), &list)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		deployment := &list.Items[i]
+		err = updatePodSpecImages(client, deployment, mappings, func() *v1.PodSpec {
+			return &deployment.Spec.Template.Spec
+		})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Rewrite image references in DeploymentConfigs.
+// Skipped when the destination cluster does not serve DeploymentConfigs.
+func (t *Task) rewriteDeploymentConfigImages(client k8sclient.Client, ns string, mappings []migapi.ImageMapping) error {
+	list := ocappsv1.DeploymentConfigList{}
+	err := client.List(context.TODO(), k8sclient.InNamespace(ns), &list)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		dc := &list.Items[i]
+		err = updatePodSpecImages(client, dc, mappings, func() *v1.PodSpec {
+			if dc.Spec.Template == nil {
+				return nil
+			}
+			return &dc.Spec.Template.Spec
+		})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Rewrite image references in StatefulSets.
+func (t *Task) rewriteStatefulSetImages(client k8sclient.Client, ns string, mappings []migapi.ImageMapping) error {
+	list := appsv1.StatefulSetList{}
+	err := client.List(context.TODO(), k8sclient.InNamespace(ns), &list)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		set := &list.Items[i]
+		err = updatePodSpecImages(client, set, mappings, func() *v1.PodSpec {
+			return &set.Spec.Template.Spec
+		})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Rewrite image references in CronJobs.
+func (t *Task) rewriteCronJobImages(client k8sclient.Client, ns string, mappings []migapi.ImageMapping) error {
+	list := batchv1beta.CronJobList{}
+	err := client.List(context.TODO(), k8sclient.InNamespace(ns), &list)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range list.Items {
+		cronJob := &list.Items[i]
+		err = updatePodSpecImages(client, cronJob, mappings, func() *v1.PodSpec {
+			return &cronJob.Spec.JobTemplate.Spec.Template.Spec
+		})
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Rewrite the images of the pod spec of a workload and update it.
+// The workload is read again and the images rewritten on the latest
+// version on conflict.
+func updatePodSpecImages(
+	client k8sclient.Client,
+	object runtime.Object,
+	mappings []migapi.ImageMapping,
+	podSpec func() *v1.PodSpec) error {
+	objectMeta, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+	key := types.NamespacedName{Namespace: objectMeta.GetNamespace(), Name: objectMeta.GetName()}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := client.Get(context.TODO(), key, object)
+		if err != nil {
+			return err
+		}
+		spec := podSpec()
+		if spec == nil || !rewritePodSpecImages(spec, mappings) {
+			return nil
+		}
+		return client.Update(context.TODO(), object)
+	})
+}
+
+// Rewrite the container and init container images of a pod spec.
+// Returns whether any image has been rewritten.
+func rewritePodSpecImages(spec *v1.PodSpec, mappings []migapi.ImageMapping) bool {
+	rewritten := false
+	for _, containers := range [][]v1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			image, found := rewriteImage(containers[i].Image, mappings)
+			if found {
+				containers[i].Image = image
+				rewritten = true
+			}
+		}
+	}
+	return rewritten
+}
+
+// Get the image reference pointing to the migrated repository.
+// The tag or digest of the original reference is preserved.
+// Returns the image and whether a mapping has been found.
+func rewriteImage(image string, mappings []migapi.ImageMapping) (string, bool) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return image, false
+	}
+	repository := ref.AsRepository().Exact()
+	defaulted := ref.DockerClientDefaults().AsRepository().Exact()
+	for _, mapping := range mappings {
+		if mapping.Source != repository && mapping.Source != defaulted {
+			continue
+		}
+		destination, err := reference.Parse(mapping.Destination)
+		if err != nil {
+			return image, false
+		}
+		destination.Tag = ref.Tag
+		destination.ID = ref.ID
+		return destination.Exact(), true
+	}
+	return image, false
+}
@@ -0,0 +1,115 @@
+package migmigration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_rewriteImage(t *testing.T) {
+	mappings := []migapi.ImageMapping{
+		{
+			Source:      "image-registry.openshift-image-registry.svc:5000/app/frontend",
+			Destination: "harbor.example.com/app/frontend",
+		},
+		{
+			Source:      "docker.io/library/redis",
+			Destination: "harbor.example.com/app/redis",
+		},
+	}
+	tests := []struct {
+		name      string
+		image     string
+		want      string
+		rewritten bool
+	}{
+		{
+			name:      "internal image by digest",
+			image:     "image-registry.openshift-image-registry.svc:5000/app/frontend@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			want:      "harbor.example.com/app/frontend@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			rewritten: true,
+		},
+		{
+			name:      "internal image by tag",
+			image:     "image-registry.openshift-image-registry.svc:5000/app/frontend:v1",
+			want:      "harbor.example.com/app/frontend:v1",
+			rewritten: true,
+		},
+		{
+			name:      "docker hub short name",
+			image:     "redis:6",
+			want:      "harbor.example.com/app/redis:6",
+			rewritten: true,
+		},
+		{
+			name:      "unmapped image",
+			image:     "quay.io/org/backend:latest",
+			want:      "quay.io/org/backend:latest",
+			rewritten: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rewritten := rewriteImage(tt.image, mappings)
+			if got != tt.want || rewritten != tt.rewritten {
+				t.Errorf("rewriteImage() = %v, %v, want %v, %v", got, rewritten, tt.want, tt.rewritten)
+			}
+		})
+	}
+}
+
+// A client returning a conflict on the first updates.
+type conflictClient struct {
+	k8sclient.Client
+	conflicts int
+}
+
+func (c *conflictClient) Update(ctx context.Context, obj runtime.Object) error {
+	if c.conflicts > 0 {
+		c.conflicts--
+		return k8serror.NewConflict(schema.GroupResource{}, "", errors.New("conflict"))
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func Test_updatePodSpecImages(t *testing.T) {
+	mappings := []migapi.ImageMapping{
+		{Source: "docker.io/library/redis", Destination: "harbor.example.com/app/redis"},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "redis"},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "redis", Image: "redis:6"}},
+				},
+			},
+		},
+	}
+	client := &conflictClient{Client: fake.NewFakeClient(deployment.DeepCopy()), conflicts: 2}
+	err := updatePodSpecImages(client, deployment, mappings, func() *v1.PodSpec {
+		return &deployment.Spec.Template.Spec
+	})
+	if err != nil {
+		t.Fatalf("updatePodSpecImages() error = %v", err)
+	}
+	got := appsv1.Deployment{}
+	err = client.Get(context.TODO(), types.NamespacedName{Namespace: "ns", Name: "redis"}, &got)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if image := got.Spec.Template.Spec.Containers[0].Image; image != "harbor.example.com/app/redis:6" {
+		t.Errorf("updatePodSpecImages() image = %s", image)
+	}
+}
@@ -21,31 +21,47 @@ func (t *Task) quiesceApplications() error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.quiesceCronJobs(client)
+	err = t.quiesceWorkloads(client, allWorkloads)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.quiesceDeploymentConfigs(client)
+
+	return nil
+}
+
+// Quiesce the selected workloads on source cluster
+func (t *Task) quiesceWorkloads(client k8sclient.Client, selected workloadSelector) error {
+	// Resources managed by operators are quiesced first so the
+	// operators do not scale the workloads back up.
+	err := t.quiesceByPolicy(client, t.sourceNamespaces(), selected)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = t.quiesceCronJobs(client, selected)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = t.quiesceDeploymentConfigs(client, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.quiesceDeployments(client)
+	err = t.quiesceDeployments(client, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.quiesceStatefulSets(client)
+	err = t.quiesceStatefulSets(client, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.quiesceReplicaSets(client)
+	err = t.quiesceReplicaSets(client, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.quiesceDaemonSets(client)
+	err = t.quiesceDaemonSets(client, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.quiesceJobs(client)
+	err = t.quiesceJobs(client, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
@@ -58,52 +74,74 @@ func (t *Task) unQuiesceSrcApplications() error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.unQuiesceApplications(srcClient, t.sourceNamespaces())
+	if t.hasQuiesceGroups() {
+		// Restored in order without waiting for the workloads
+		// to be ready, not to block the rollback.
+		_, err = t.unQuiesceGroups(srcClient, t.sourceNamespaces(), false)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		return nil
+	}
+	err = t.unQuiesceApplications(srcClient, t.sourceNamespaces(), allWorkloads)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
 	return nil
 }
 
-func (t *Task) unQuiesceDestApplications() error {
+// Unquiesce applications on destination cluster.
+// Returns: `true` when the applications have been started.
+func (t *Task) unQuiesceDestApplications() (bool, error) {
 	destClient, err := t.getDestinationClient()
 	if err != nil {
-		return liberr.Wrap(err)
+		return false, liberr.Wrap(err)
 	}
-	err = t.unQuiesceApplications(destClient, t.destinationNamespaces())
+	if t.hasQuiesceGroups() {
+		started, err := t.unQuiesceGroups(destClient, t.destinationNamespaces(), true)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		return started, nil
+	}
+	err = t.unQuiesceApplications(destClient, t.destinationNamespaces(), allWorkloads)
 	if err != nil {
-		return liberr.Wrap(err)
+		return false, liberr.Wrap(err)
 	}
-	return nil
+	return true, nil
 }
 
 // Unquiesce applications using client and namespace list given
-func (t *Task) unQuiesceApplications(client k8sclient.Client, namespaces []string) error {
-	err := t.unQuiesceCronJobs(client, namespaces)
+func (t *Task) unQuiesceApplications(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
+	err := t.unQuiesceCronJobs(client, namespaces, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.unQuiesceDeploymentConfigs(client, namespaces)
+	err = t.unQuiesceDeploymentConfigs(client, namespaces, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.unQuiesceDeployments(client, namespaces)
+	err = t.unQuiesceDeployments(client, namespaces, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.unQuiesceStatefulSets(client, namespaces)
+	err = t.unQuiesceStatefulSets(client, namespaces, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.unQuiesceReplicaSets(client, namespaces)
+	err = t.unQuiesceReplicaSets(client, namespaces, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.unQuiesceDaemonSets(client, namespaces)
+	err = t.unQuiesceDaemonSets(client, namespaces, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	err = t.unQuiesceJobs(client, namespaces)
+	err = t.unQuiesceJobs(client, namespaces, selected)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = t.unQuiesceByPolicy(client, namespaces, selected)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
@@ -112,7 +150,7 @@ func (t *Task) unQuiesceApplications(client k8sclient.Client, namespaces []strin
 }
 
 // Scales down DeploymentConfig on source cluster
-func (t *Task) quiesceDeploymentConfigs(client k8sclient.Client) error {
+func (t *Task) quiesceDeploymentConfigs(client k8sclient.Client, selected workloadSelector) error {
 	for _, ns := range t.sourceNamespaces() {
 		list := ocappsv1.DeploymentConfigList{}
 		options := k8sclient.InNamespace(ns)
@@ -124,6 +162,9 @@ func (t *Task) quiesceDeploymentConfigs(client k8sclient.Client) error {
 			return liberr.Wrap(err)
 		}
 		for _, dc := range list.Items {
+			if !selected(dc.Labels) {
+				continue
+			}
 			if dc.Annotations == nil {
 				dc.Annotations = make(map[string]string)
 			}
@@ -143,7 +184,7 @@ func (t *Task) quiesceDeploymentConfigs(client k8sclient.Client) error {
 }
 
 // Scales DeploymentConfig back up on source cluster
-func (t *Task) unQuiesceDeploymentConfigs(client k8sclient.Client, namespaces []string) error {
+func (t *Task) unQuiesceDeploymentConfigs(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
 	for _, ns := range namespaces {
 		list := ocappsv1.DeploymentConfigList{}
 		options := k8sclient.InNamespace(ns)
@@ -155,6 +196,9 @@ func (t *Task) unQuiesceDeploymentConfigs(client k8sclient.Client, namespaces []
 			return liberr.Wrap(err)
 		}
 		for _, dc := range list.Items {
+			if !selected(dc.Labels) {
+				continue
+			}
 			if dc.Annotations == nil {
 				continue
 			}
@@ -182,7 +226,7 @@ func (t *Task) unQuiesceDeploymentConfigs(client k8sclient.Client, namespaces []
 }
 
 // Scales down all Deployments
-func (t *Task) quiesceDeployments(client k8sclient.Client) error {
+func (t *Task) quiesceDeployments(client k8sclient.Client, selected workloadSelector) error {
 	zero := int32(0)
 	for _, ns := range t.sourceNamespaces() {
 		list := appsv1.DeploymentList{}
@@ -195,6 +239,9 @@ func (t *Task) quiesceDeployments(client k8sclient.Client) error {
 			return liberr.Wrap(err)
 		}
 		for _, deployment := range list.Items {
+			if !selected(deployment.Labels) {
+				continue
+			}
 			if deployment.Annotations == nil {
 				deployment.Annotations = make(map[string]string)
 			}
@@ -214,7 +261,7 @@ func (t *Task) quiesceDeployments(client k8sclient.Client) error {
 }
 
 // Scales all Deployments back up
-func (t *Task) unQuiesceDeployments(client k8sclient.Client, namespaces []string) error {
+func (t *Task) unQuiesceDeployments(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
 	for _, ns := range namespaces {
 		list := appsv1.DeploymentList{}
 		options := k8sclient.InNamespace(ns)
@@ -226,6 +273,9 @@ func (t *Task) unQuiesceDeployments(client k8sclient.Client, namespaces []string
 			return liberr.Wrap(err)
 		}
 		for _, deployment := range list.Items {
+			if !selected(deployment.Labels) {
+				continue
+			}
 			if deployment.Annotations == nil {
 				deployment.Annotations = make(map[string]string)
 			}
@@ -254,7 +304,7 @@ func (t *Task) unQuiesceDeployments(client k8sclient.Client, namespaces []string
 }
 
 // Scales down all StatefulSets.
-func (t *Task) quiesceStatefulSets(client k8sclient.Client) error {
+func (t *Task) quiesceStatefulSets(client k8sclient.Client, selected workloadSelector) error {
 	zero := int32(0)
 	for _, ns := range t.sourceNamespaces() {
 		list := appsv1.StatefulSetList{}
@@ -267,6 +317,9 @@ func (t *Task) quiesceStatefulSets(client k8sclient.Client) error {
 			return liberr.Wrap(err)
 		}
 		for _, set := range list.Items {
+			if !selected(set.Labels) {
+				continue
+			}
 			if set.Annotations == nil {
 				set.Annotations = make(map[string]string)
 			}
@@ -285,7 +338,7 @@ func (t *Task) quiesceStatefulSets(client k8sclient.Client) error {
 }
 
 // Scales all StatefulSets back up
-func (t *Task) unQuiesceStatefulSets(client k8sclient.Client, namespaces []string) error {
+func (t *Task) unQuiesceStatefulSets(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
 	for _, ns := range namespaces {
 		list := appsv1.StatefulSetList{}
 		options := k8sclient.InNamespace(ns)
@@ -297,6 +350,9 @@ func (t *Task) unQuiesceStatefulSets(client k8sclient.Client, namespaces []strin
 			return liberr.Wrap(err)
 		}
 		for _, set := range list.Items {
+			if !selected(set.Labels) {
+				continue
+			}
 			if set.Annotations == nil {
 				continue
 			}
@@ -324,7 +380,7 @@ func (t *Task) unQuiesceStatefulSets(client k8sclient.Client, namespaces []strin
 }
 
 // Scales down all ReplicaSets.
-func (t *Task) quiesceReplicaSets(client k8sclient.Client) error {
+func (t *Task) quiesceReplicaSets(client k8sclient.Client, selected workloadSelector) error {
 	zero := int32(0)
 	for _, ns := range t.sourceNamespaces() {
 		list := appsv1.ReplicaSetList{}
@@ -337,6 +393,9 @@ func (t *Task) quiesceReplicaSets(client k8sclient.Client) error {
 			return liberr.Wrap(err)
 		}
 		for _, set := range list.Items {
+			if !selected(set.Labels) {
+				continue
+			}
 			if len(set.OwnerReferences) > 0 {
 				t.Log.Info("Quiesce skipping ReplicaSet, has OwnerReferences", "name", set.Name)
 				continue
@@ -359,7 +418,7 @@ func (t *Task) quiesceReplicaSets(client k8sclient.Client) error {
 }
 
 // Scales all ReplicaSets back up
-func (t *Task) unQuiesceReplicaSets(client k8sclient.Client, namespaces []string) error {
+func (t *Task) unQuiesceReplicaSets(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
 	for _, ns := range namespaces {
 		list := appsv1.ReplicaSetList{}
 		options := k8sclient.InNamespace(ns)
@@ -371,6 +430,9 @@ func (t *Task) unQuiesceReplicaSets(client k8sclient.Client, namespaces []string
 			return liberr.Wrap(err)
 		}
 		for _, set := range list.Items {
+			if !selected(set.Labels) {
+				continue
+			}
 			if len(set.OwnerReferences) > 0 {
 				t.Log.Info("Unquiesce skipping ReplicaSet, has OwnerReferences", "name", set.Name)
 				continue
@@ -402,7 +464,7 @@ func (t *Task) unQuiesceReplicaSets(client k8sclient.Client, namespaces []string
 }
 
 // Scales down all DaemonSets.
-func (t *Task) quiesceDaemonSets(client k8sclient.Client) error {
+func (t *Task) quiesceDaemonSets(client k8sclient.Client, selected workloadSelector) error {
 	for _, ns := range t.sourceNamespaces() {
 		list := appsv1.DaemonSetList{}
 		options := k8sclient.InNamespace(ns)
@@ -414,6 +476,9 @@ func (t *Task) quiesceDaemonSets(client k8sclient.Client) error {
 			return liberr.Wrap(err)
 		}
 		for _, set := range list.Items {
+			if !selected(set.Labels) {
+				continue
+			}
 			if set.Annotations == nil {
 				set.Annotations = make(map[string]string)
 			}
@@ -438,7 +503,7 @@ func (t *Task) quiesceDaemonSets(client k8sclient.Client) error {
 }
 
 // Scales all DaemonSets back up
-func (t *Task) unQuiesceDaemonSets(client k8sclient.Client, namespaces []string) error {
+func (t *Task) unQuiesceDaemonSets(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
 	for _, ns := range namespaces {
 		list := appsv1.DaemonSetList{}
 		options := k8sclient.InNamespace(ns)
@@ -450,6 +515,9 @@ func (t *Task) unQuiesceDaemonSets(client k8sclient.Client, namespaces []string)
 			return liberr.Wrap(err)
 		}
 		for _, set := range list.Items {
+			if !selected(set.Labels) {
+				continue
+			}
 			if set.Annotations == nil {
 				continue
 			}
@@ -479,7 +547,7 @@ func (t *Task) unQuiesceDaemonSets(client k8sclient.Client, namespaces []string)
 }
 
 // Suspends all CronJobs
-func (t *Task) quiesceCronJobs(client k8sclient.Client) error {
+func (t *Task) quiesceCronJobs(client k8sclient.Client, selected workloadSelector) error {
 	for _, ns := range t.sourceNamespaces() {
 		list := batchv1beta.CronJobList{}
 		options := k8sclient.InNamespace(ns)
@@ -488,6 +556,9 @@ func (t *Task) quiesceCronJobs(client k8sclient.Client) error {
 			return liberr.Wrap(err)
 		}
 		for _, r := range list.Items {
+			if !selected(r.Labels) {
+				continue
+			}
 			if r.Annotations == nil {
 				r.Annotations = make(map[string]string)
 			}
@@ -507,7 +578,7 @@ func (t *Task) quiesceCronJobs(client k8sclient.Client) error {
 }
 
 // Undo quiescence on all CronJobs
-func (t *Task) unQuiesceCronJobs(client k8sclient.Client, namespaces []string) error {
+func (t *Task) unQuiesceCronJobs(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
 	for _, ns := range namespaces {
 		list := batchv1beta.CronJobList{}
 		options := k8sclient.InNamespace(ns)
@@ -516,6 +587,9 @@ func (t *Task) unQuiesceCronJobs(client k8sclient.Client, namespaces []string) e
 			return liberr.Wrap(err)
 		}
 		for _, r := range list.Items {
+			if !selected(r.Labels) {
+				continue
+			}
 			if r.Annotations == nil {
 				continue
 			}
@@ -536,7 +610,7 @@ func (t *Task) unQuiesceCronJobs(client k8sclient.Client, namespaces []string) e
 }
 
 // Scales down all Jobs
-func (t *Task) quiesceJobs(client k8sclient.Client) error {
+func (t *Task) quiesceJobs(client k8sclient.Client, selected workloadSelector) error {
 	zero := int32(0)
 	for _, ns := range t.sourceNamespaces() {
 		list := batchv1.JobList{}
@@ -549,6 +623,9 @@ func (t *Task) quiesceJobs(client k8sclient.Client) error {
 			return liberr.Wrap(err)
 		}
 		for _, job := range list.Items {
+			if !selected(job.Labels) {
+				continue
+			}
 			if job.Annotations == nil {
 				job.Annotations = make(map[string]string)
 			}
@@ -568,7 +645,7 @@ func (t *Task) quiesceJobs(client k8sclient.Client) error {
 }
 
 // Scales all Jobs back up
-func (t *Task) unQuiesceJobs(client k8sclient.Client, namespaces []string) error {
+func (t *Task) unQuiesceJobs(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
 	for _, ns := range namespaces {
 		list := batchv1.JobList{}
 		options := k8sclient.InNamespace(ns)
@@ -580,6 +657,9 @@ func (t *Task) unQuiesceJobs(client k8sclient.Client, namespaces []string) error
 			return liberr.Wrap(err)
 		}
 		for _, job := range list.Items {
+			if !selected(job.Labels) {
+				continue
+			}
 			if job.Annotations == nil {
 				continue
 			}
@@ -650,6 +730,15 @@ func (t *Task) ensureQuiescedPodsTerminated() (bool, error) {
 			}
 		}
 	}
+	// Pods of the resources quiesced by policy.
+	policies, err := t.getQuiescePolicies()
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	terminated, err := t.policyPodsTerminated(client, t.sourceNamespaces(), policies, skippedPhases)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
 
-	return true, nil
+	return terminated, nil
 }
@@ -0,0 +1,275 @@
+package migmigration
+
+import (
+	"context"
+	"fmt"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	ocappsv1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Selects workloads by labels.
+type workloadSelector func(map[string]string) bool
+
+// Selects all workloads.
+func allWorkloads(map[string]string) bool {
+	return true
+}
+
+// Select the workloads matched by the label selector.
+func selectedBy(selector labels.Selector) workloadSelector {
+	return func(l map[string]string) bool {
+		return selector.Matches(labels.Set(l))
+	}
+}
+
+// Get whether the workloads are quiesced in groups.
+func (t *Task) hasQuiesceGroups() bool {
+	return t.quiesce() && len(t.PlanResources.MigPlan.Spec.QuiesceGroups) > 0
+}
+
+// Get the label selector of a quiesce group.
+func groupSelector(group *migapi.QuiesceGroup) (labels.Selector, error) {
+	if group.Selector == nil {
+		return labels.Nothing(), nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(group.Selector)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	return selector, nil
+}
+
+// Select the workloads not matched by any quiesce group.
+func (t *Task) ungroupedWorkloads() (workloadSelector, error) {
+	selectors := []labels.Selector{}
+	groups := t.PlanResources.MigPlan.Spec.QuiesceGroups
+	for i := range groups {
+		selector, err := groupSelector(&groups[i])
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		selectors = append(selectors, selector)
+	}
+	return func(l map[string]string) bool {
+		for _, selector := range selectors {
+			if selector.Matches(labels.Set(l)) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// Quiesce the groups on source cluster in order.
+// The drain hook of a group is run before the group is quiesced and the
+// pods of a group must be terminated before the next group is quiesced.
+// Returns: `true` when all groups are quiesced.
+func (t *Task) quiesceGroups() (bool, error) {
+	if !t.hasQuiesceGroups() {
+		return true, nil
+	}
+	client, err := t.getSourceClient()
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	progress := []string{}
+	quiesced := true
+	groups := t.PlanResources.MigPlan.Spec.QuiesceGroups
+	for i := range groups {
+		group := &groups[i]
+		if !quiesced {
+			progress = append(progress, fmt.Sprintf("Group %s: Pending", group.Name))
+			continue
+		}
+		if hook := group.GetDrainHook(); hook != nil {
+			drained, err := t.runHook(*hook)
+			if err != nil {
+				return false, liberr.Wrap(err)
+			}
+			if !drained {
+				progress = append(progress, fmt.Sprintf("Group %s: Draining", group.Name))
+				quiesced = false
+				continue
+			}
+		}
+		selector, err := groupSelector(group)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		err = t.quiesceWorkloads(client, selectedBy(selector))
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		terminated, err := t.groupPodsTerminated(client, t.sourceNamespaces(), selector)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		if !terminated {
+			progress = append(progress, fmt.Sprintf("Group %s: Waiting for pods to terminate", group.Name))
+			quiesced = false
+			continue
+		}
+		progress = append(progress, fmt.Sprintf("Group %s: Quiesced", group.Name))
+	}
+	t.setProgress(progress)
+
+	return quiesced, nil
+}
+
+// Unquiesce the groups in the reverse order.
+// The workloads not matched by any group are unquiesced first.
+// When waiting, the workloads of a group must be ready before the
+// next group is unquiesced.
+// Returns: `true` when all groups are unquiesced.
+func (t *Task) unQuiesceGroups(client k8sclient.Client, namespaces []string, wait bool) (bool, error) {
+	ungrouped, err := t.ungroupedWorkloads()
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	err = t.unQuiesceApplications(client, namespaces, ungrouped)
+	if err != nil {
+		return false, liberr.Wrap(err)
+	}
+	progress := []string{}
+	started := true
+	groups := t.PlanResources.MigPlan.Spec.QuiesceGroups
+	for i := len(groups) - 1; i >= 0; i-- {
+		group := &groups[i]
+		if !started {
+			progress = append(progress, fmt.Sprintf("Group %s: Pending", group.Name))
+			continue
+		}
+		selector, err := groupSelector(group)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		err = t.unQuiesceApplications(client, namespaces, selectedBy(selector))
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		if wait {
+			ready, err := t.groupWorkloadsReady(client, namespaces, selector)
+			if err != nil {
+				return false, liberr.Wrap(err)
+			}
+			if !ready {
+				progress = append(progress, fmt.Sprintf("Group %s: Waiting for workloads to be ready", group.Name))
+				started = false
+				continue
+			}
+		}
+		progress = append(progress, fmt.Sprintf("Group %s: Started", group.Name))
+	}
+	t.setProgress(progress)
+
+	return started, nil
+}
+
+// Get whether the pods matched by the selector have terminated.
+func (t *Task) groupPodsTerminated(client k8sclient.Client, namespaces []string, selector labels.Selector) (bool, error) {
+	skippedPhases := map[v1.PodPhase]bool{
+		v1.PodSucceeded: true,
+		v1.PodFailed:    true,
+		v1.PodUnknown:   true,
+	}
+	for _, ns := range namespaces {
+		list := v1.PodList{}
+		options := &k8sclient.ListOptions{Namespace: ns, LabelSelector: selector}
+		err := client.List(context.TODO(), options, &list)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		for _, pod := range list.Items {
+			if _, found := skippedPhases[pod.Status.Phase]; found {
+				continue
+			}
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Get whether the workloads matched by the selector are ready.
+// The workloads must have observed their latest generation and
+// report the desired number of ready replicas.
+func (t *Task) groupWorkloadsReady(client k8sclient.Client, namespaces []string, selector labels.Selector) (bool, error) {
+	for _, ns := range namespaces {
+		options := &k8sclient.ListOptions{Namespace: ns, LabelSelector: selector}
+		dcList := ocappsv1.DeploymentConfigList{}
+		err := client.List(context.TODO(), options, &dcList)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		for _, dc := range dcList.Items {
+			if dc.Status.ObservedGeneration < dc.Generation ||
+				dc.Status.ReadyReplicas < dc.Spec.Replicas {
+				return false, nil
+			}
+		}
+		deploymentList := appsv1.DeploymentList{}
+		err = client.List(context.TODO(), options, &deploymentList)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		for _, deployment := range deploymentList.Items {
+			if deployment.Status.ObservedGeneration < deployment.Generation ||
+				deployment.Status.ReadyReplicas < desiredReplicas(deployment.Spec.Replicas) {
+				return false, nil
+			}
+		}
+		statefulSetList := appsv1.StatefulSetList{}
+		err = client.List(context.TODO(), options, &statefulSetList)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		for _, set := range statefulSetList.Items {
+			if set.Status.ObservedGeneration < set.Generation ||
+				set.Status.ReadyReplicas < desiredReplicas(set.Spec.Replicas) {
+				return false, nil
+			}
+		}
+		replicaSetList := appsv1.ReplicaSetList{}
+		err = client.List(context.TODO(), options, &replicaSetList)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		for _, set := range replicaSetList.Items {
+			if len(set.OwnerReferences) > 0 {
+				continue
+			}
+			if set.Status.ObservedGeneration < set.Generation ||
+				set.Status.ReadyReplicas < desiredReplicas(set.Spec.Replicas) {
+				return false, nil
+			}
+		}
+		daemonSetList := appsv1.DaemonSetList{}
+		err = client.List(context.TODO(), options, &daemonSetList)
+		if err != nil {
+			return false, liberr.Wrap(err)
+		}
+		for _, set := range daemonSetList.Items {
+			if set.Status.ObservedGeneration < set.Generation ||
+				set.Status.NumberReady < set.Status.DesiredNumberScheduled {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Get the desired replicas, defaulted to 1 when not set.
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
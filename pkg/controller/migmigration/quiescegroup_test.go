@@ -0,0 +1,159 @@
+package migmigration
+
+import (
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	ocappsv1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	_ = ocappsv1.AddToScheme(scheme.Scheme)
+}
+
+func TestTask_ungroupedWorkloads(t *testing.T) {
+	task := &Task{
+		PlanResources: &migapi.PlanResources{
+			MigPlan: &migapi.MigPlan{
+				Spec: migapi.MigPlanSpec{
+					QuiesceGroups: []migapi.QuiesceGroup{
+						{
+							Name:     "clients",
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+						},
+						{
+							Name: "databases",
+							Selector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"postgres", "mysql"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ungrouped, err := task.ungroupedWorkloads()
+	if err != nil {
+		t.Fatalf("ungroupedWorkloads() error = %v", err)
+	}
+	tests := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{labels: map[string]string{"tier": "frontend"}, want: false},
+		{labels: map[string]string{"app": "mysql"}, want: false},
+		{labels: map[string]string{"app": "redis"}, want: true},
+		{labels: nil, want: true},
+	}
+	for _, tt := range tests {
+		if got := ungrouped(tt.labels); got != tt.want {
+			t.Errorf("ungroupedWorkloads()(%v) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestTask_groupPodsTerminated(t *testing.T) {
+	selector := labels.SelectorFromSet(labels.Set{"app": "db"})
+	pod := func(name string, l map[string]string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: l},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+	}
+	tests := []struct {
+		name string
+		objs []runtime.Object
+		want bool
+	}{
+		{
+			name: "no pods",
+			want: true,
+		},
+		{
+			name: "other pods running",
+			objs: []runtime.Object{pod("web", map[string]string{"app": "web"}, v1.PodRunning)},
+			want: true,
+		},
+		{
+			name: "group pod running",
+			objs: []runtime.Object{pod("db", map[string]string{"app": "db"}, v1.PodRunning)},
+			want: false,
+		},
+		{
+			name: "group pod succeeded",
+			objs: []runtime.Object{pod("db", map[string]string{"app": "db"}, v1.PodSucceeded)},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{}
+			got, err := task.groupPodsTerminated(fake.NewFakeClient(tt.objs...), []string{"ns"}, selector)
+			if err != nil {
+				t.Fatalf("groupPodsTerminated() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("groupPodsTerminated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_groupWorkloadsReady(t *testing.T) {
+	selector := labels.SelectorFromSet(labels.Set{"app": "db"})
+	three := int32(3)
+	deployment := func(name string, l map[string]string, generation, observed int64, ready int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: l, Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: &three},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: observed, ReadyReplicas: ready},
+		}
+	}
+	tests := []struct {
+		name string
+		objs []runtime.Object
+		want bool
+	}{
+		{
+			name: "ready",
+			objs: []runtime.Object{deployment("db", map[string]string{"app": "db"}, 2, 2, 3)},
+			want: true,
+		},
+		{
+			name: "scale up not observed",
+			objs: []runtime.Object{deployment("db", map[string]string{"app": "db"}, 2, 1, 3)},
+			want: false,
+		},
+		{
+			name: "replicas not ready",
+			objs: []runtime.Object{deployment("db", map[string]string{"app": "db"}, 2, 2, 1)},
+			want: false,
+		},
+		{
+			name: "other workload not ready",
+			objs: []runtime.Object{deployment("web", map[string]string{"app": "web"}, 2, 2, 0)},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{}
+			got, err := task.groupWorkloadsReady(fake.NewFakeClient(tt.objs...), []string{"ns"}, selector)
+			if err != nil {
+				t.Fatalf("groupWorkloadsReady() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("groupWorkloadsReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package migmigration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Quiesce policy ConfigMap.
+// Each key of the ConfigMap holds a (YAML or JSON) QuiescePolicy.
+const (
+	QuiescePolicyConfigMap   = "migration-quiesce-policies"
+	QuiescePolicyAnnotation  = "migration.openshift.io/preQuiesceValue"
+	quiescePolicyPathSep     = "."
+	quiescePolicyDocumentMax = 4096
+)
+
+// QuiescePolicy describes how resources of a kind (usually custom resources
+// managed by an operator) are quiesced and unquiesced.
+// The field found at the dot separated path (e.g. `spec.replicas` or `spec.paused`)
+// is set to the quiesced value (e.g. `0` or `true`) and the original value is
+// stored in the annotation, `migration.openshift.io/preQuiesceValue` by default.
+// The pods of the resources are owned by the workloads created by the operator,
+// not by the resources. When the optional pod selector path is set (e.g. `spec.selector`),
+// the pods matched by the label selector (or the map of labels) found at the path
+// are expected to be terminated once quiesced.
+type QuiescePolicy struct {
+	Group           string      `json:"group"`
+	Version         string      `json:"version"`
+	Kind            string      `json:"kind"`
+	Path            string      `json:"path"`
+	QuiescedValue   interface{} `json:"quiescedValue"`
+	Annotation      string      `json:"annotation,omitempty"`
+	PodSelectorPath string      `json:"podSelectorPath,omitempty"`
+}
+
+// Get the GVK.
+func (r *QuiescePolicy) GVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   r.Group,
+		Version: r.Version,
+		Kind:    r.Kind,
+	}
+}
+
+// Get the fields of the path.
+func (r *QuiescePolicy) fields() []string {
+	return strings.Split(r.Path, quiescePolicyPathSep)
+}
+
+// Get the fields of the pod selector path.
+func (r *QuiescePolicy) podSelectorFields() []string {
+	return strings.Split(r.PodSelectorPath, quiescePolicyPathSep)
+}
+
+// Get the annotation storing the original value.
+func (r *QuiescePolicy) annotation() string {
+	if r.Annotation != "" {
+		return r.Annotation
+	}
+	return QuiescePolicyAnnotation
+}
+
+// Validate the policy.
+func (r *QuiescePolicy) Validate() error {
+	if r.Version == "" || r.Kind == "" {
+		return fmt.Errorf("quiesce policy `version` and `kind` are required")
+	}
+	if r.Path == "" {
+		return fmt.Errorf("quiesce policy for %s: `path` is required", r.GVK())
+	}
+	for _, field := range r.fields() {
+		if field == "" {
+			return fmt.Errorf("quiesce policy for %s: `path` %q is malformed", r.GVK(), r.Path)
+		}
+	}
+	if r.QuiescedValue == nil {
+		return fmt.Errorf("quiesce policy for %s: `quiescedValue` is required", r.GVK())
+	}
+	if r.PodSelectorPath != "" {
+		for _, field := range r.podSelectorFields() {
+			if field == "" {
+				return fmt.Errorf("quiesce policy for %s: `podSelectorPath` %q is malformed", r.GVK(), r.PodSelectorPath)
+			}
+		}
+	}
+	return nil
+}
+
+// Get the selector of the pods of the resource.
+// The value found at the pod selector path is either a label selector
+// (with `matchLabels` or `matchExpressions`) or a map of labels.
+// Returns false when the path is not set or no (empty) selector is found.
+func (r *QuiescePolicy) PodSelector(object *unstructured.Unstructured) (labels.Selector, bool, error) {
+	if r.PodSelectorPath == "" {
+		return nil, false, nil
+	}
+	value, found, err := unstructured.NestedMap(object.Object, r.podSelectorFields()...)
+	if err != nil || !found || len(value) == 0 {
+		return nil, false, err
+	}
+	_, hasLabels := value["matchLabels"]
+	_, hasExpressions := value["matchExpressions"]
+	if hasLabels || hasExpressions {
+		labelSelector := metav1.LabelSelector{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(value, &labelSelector)
+		if err != nil {
+			return nil, false, err
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+		if err != nil {
+			return nil, false, err
+		}
+		return selector, !selector.Empty(), nil
+	}
+	set, _, err := unstructured.NestedStringMap(object.Object, r.podSelectorFields()...)
+	if err != nil {
+		return nil, false, err
+	}
+	return labels.SelectorFromSet(set), true, nil
+}
+
+// Quiesce the resource.
+// The original value is stored in the annotation.
+// Returns whether the resource has been changed.
+func (r *QuiescePolicy) Quiesce(object *unstructured.Unstructured) (bool, error) {
+	current, found, err := unstructured.NestedFieldCopy(object.Object, r.fields()...)
+	if err != nil {
+		return false, err
+	}
+	quiesced := normalizeQuiesceValue(r.QuiescedValue)
+	if found && reflect.DeepEqual(normalizeQuiesceValue(current), quiesced) {
+		return false, nil
+	}
+	original, err := json.Marshal(current)
+	if err != nil {
+		return false, err
+	}
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[r.annotation()] = string(original)
+	object.SetAnnotations(annotations)
+	err = unstructured.SetNestedField(object.Object, quiesced, r.fields()...)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Unquiesce the resource.
+// The original value is restored from the annotation.
+// Returns whether the resource has been changed.
+func (r *QuiescePolicy) Unquiesce(object *unstructured.Unstructured) (bool, error) {
+	annotations := object.GetAnnotations()
+	stored, found := annotations[r.annotation()]
+	if !found {
+		return false, nil
+	}
+	var original interface{}
+	err := json.Unmarshal([]byte(stored), &original)
+	if err != nil {
+		return false, err
+	}
+	if original == nil {
+		unstructured.RemoveNestedField(object.Object, r.fields()...)
+	} else {
+		err = unstructured.SetNestedField(object.Object, normalizeQuiesceValue(original), r.fields()...)
+		if err != nil {
+			return false, err
+		}
+	}
+	delete(annotations, r.annotation())
+	object.SetAnnotations(annotations)
+	return true, nil
+}
+
+// Convert a value to the types used by unstructured content.
+// Numbers decoded from JSON are float64 while unstructured integers are int64.
+func normalizeQuiesceValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	}
+	return value
+}
+
+// Parse the policies found in the ConfigMap.
+func parseQuiescePolicies(configMap *v1.ConfigMap) ([]QuiescePolicy, error) {
+	keys := []string{}
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	policies := []QuiescePolicy{}
+	for _, key := range keys {
+		policy := QuiescePolicy{}
+		decoder := yaml.NewYAMLOrJSONDecoder(
+			bytes.NewBufferString(configMap.Data[key]),
+			quiescePolicyDocumentMax)
+		err := decoder.Decode(&policy)
+		if err != nil {
+			return nil, fmt.Errorf("quiesce policy %q is malformed: %s", key, err.Error())
+		}
+		policy.QuiescedValue = normalizeQuiesceValue(policy.QuiescedValue)
+		err = policy.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("quiesce policy %q is invalid: %s", key, err.Error())
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// Get the quiesce policies configured on the host cluster.
+func (t *Task) getQuiescePolicies() ([]QuiescePolicy, error) {
+	configMap := v1.ConfigMap{}
+	err := t.Client.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{
+			Namespace: migapi.OpenshiftMigrationNamespace,
+			Name:      QuiescePolicyConfigMap,
+		},
+		&configMap)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return []QuiescePolicy{}, nil
+		}
+		return nil, liberr.Wrap(err)
+	}
+	policies, err := parseQuiescePolicies(&configMap)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	return policies, nil
+}
+
+// Quiesce the resources matched by the quiesce policies.
+func (t *Task) quiesceByPolicy(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
+	policies, err := t.getQuiescePolicies()
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range policies {
+		err = t.applyQuiescePolicy(client, namespaces, selected, &policies[i], policies[i].Quiesce)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Unquiesce the resources matched by the quiesce policies.
+func (t *Task) unQuiesceByPolicy(client k8sclient.Client, namespaces []string, selected workloadSelector) error {
+	policies, err := t.getQuiescePolicies()
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range policies {
+		err = t.applyQuiescePolicy(client, namespaces, selected, &policies[i], policies[i].Unquiesce)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// List the resources of the policy kind in a namespace.
+// Returns false when the cluster does not serve the kind.
+func listQuiescePolicyResources(client k8sclient.Client, ns string, policy *QuiescePolicy) (*unstructured.UnstructuredList, bool, error) {
+	gvk := policy.GVK()
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   gvk.Group,
+		Version: gvk.Version,
+		Kind:    gvk.Kind + "List",
+	})
+	err := client.List(context.TODO(), k8sclient.InNamespace(ns), &list)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, false, nil
+		}
+		return nil, false, liberr.Wrap(err)
+	}
+	return &list, true, nil
+}
+
+// Apply the quiesce or unquiesce function to the selected resources of the policy kind.
+// Skipped when the cluster does not serve the kind.
+func (t *Task) applyQuiescePolicy(
+	client k8sclient.Client,
+	namespaces []string,
+	selected workloadSelector,
+	policy *QuiescePolicy,
+	apply func(*unstructured.Unstructured) (bool, error)) error {
+	for _, ns := range namespaces {
+		list, served, err := listQuiescePolicyResources(client, ns, policy)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if !served {
+			t.Log.Info("Quiesce policy kind not found on cluster, skipped.", "kind", policy.GVK().String())
+			return nil
+		}
+		for i := range list.Items {
+			object := &list.Items[i]
+			if !selected(object.GetLabels()) {
+				continue
+			}
+			changed, err := apply(object)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+			if !changed {
+				continue
+			}
+			err = client.Update(context.TODO(), object)
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+		}
+	}
+	return nil
+}
+
+// Get whether the pods of the resources quiesced by the policies have terminated.
+// Only the policies with a pod selector path are considered.
+func (t *Task) policyPodsTerminated(
+	client k8sclient.Client,
+	namespaces []string,
+	policies []QuiescePolicy,
+	skippedPhases map[v1.PodPhase]bool) (bool, error) {
+	for i := range policies {
+		policy := &policies[i]
+		if policy.PodSelectorPath == "" {
+			continue
+		}
+		for _, ns := range namespaces {
+			list, served, err := listQuiescePolicyResources(client, ns, policy)
+			if err != nil {
+				return false, liberr.Wrap(err)
+			}
+			if !served {
+				break
+			}
+			for j := range list.Items {
+				selector, found, err := policy.PodSelector(&list.Items[j])
+				if err != nil {
+					return false, liberr.Wrap(err)
+				}
+				if !found {
+					continue
+				}
+				pods := v1.PodList{}
+				err = client.List(
+					context.TODO(),
+					&k8sclient.ListOptions{
+						Namespace:     ns,
+						LabelSelector: selector,
+					},
+					&pods)
+				if err != nil {
+					return false, liberr.Wrap(err)
+				}
+				for _, pod := range pods.Items {
+					if !skippedPhases[pod.Status.Phase] {
+						return false, nil
+					}
+				}
+			}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,191 @@
+package migmigration
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestParseQuiescePolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    []QuiescePolicy
+		wantErr bool
+	}{
+		{
+			name: "yaml and json policies",
+			data: map[string]string{
+				"kafka": "group: kafka.strimzi.io\nversion: v1beta1\nkind: Kafka\npath: spec.kafka.replicas\nquiescedValue: 0\n",
+				"etcd":  `{"group": "etcd.database.coreos.com", "version": "v1beta2", "kind": "EtcdCluster", "path": "spec.paused", "quiescedValue": true}`,
+			},
+			want: []QuiescePolicy{
+				{
+					Group:         "etcd.database.coreos.com",
+					Version:       "v1beta2",
+					Kind:          "EtcdCluster",
+					Path:          "spec.paused",
+					QuiescedValue: true,
+				},
+				{
+					Group:         "kafka.strimzi.io",
+					Version:       "v1beta1",
+					Kind:          "Kafka",
+					Path:          "spec.kafka.replicas",
+					QuiescedValue: int64(0),
+				},
+			},
+		},
+		{
+			name:    "missing path",
+			data:    map[string]string{"foo": "version: v1\nkind: Foo\nquiescedValue: 0\n"},
+			wantErr: true,
+		},
+		{
+			name:    "malformed path",
+			data:    map[string]string{"foo": "version: v1\nkind: Foo\npath: spec..replicas\nquiescedValue: 0\n"},
+			wantErr: true,
+		},
+		{
+			name:    "missing quiesced value",
+			data:    map[string]string{"foo": "version: v1\nkind: Foo\npath: spec.replicas\n"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQuiescePolicies(&v1.ConfigMap{Data: tt.data})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseQuiescePolicies() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuiescePolicies() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuiescePolicy_QuiesceUnquiesce(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  QuiescePolicy
+		spec    map[string]interface{}
+		want    map[string]interface{}
+		changed bool
+	}{
+		{
+			name:    "scale replicas",
+			policy:  QuiescePolicy{Version: "v1", Kind: "Foo", Path: "spec.replicas", QuiescedValue: int64(0)},
+			spec:    map[string]interface{}{"replicas": int64(3)},
+			want:    map[string]interface{}{"replicas": int64(0)},
+			changed: true,
+		},
+		{
+			name:    "pause",
+			policy:  QuiescePolicy{Version: "v1", Kind: "Foo", Path: "spec.paused", QuiescedValue: true},
+			spec:    map[string]interface{}{},
+			want:    map[string]interface{}{"paused": true},
+			changed: true,
+		},
+		{
+			name:    "already quiesced",
+			policy:  QuiescePolicy{Version: "v1", Kind: "Foo", Path: "spec.replicas", QuiescedValue: int64(0)},
+			spec:    map[string]interface{}{"replicas": int64(0)},
+			want:    map[string]interface{}{"replicas": int64(0)},
+			changed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			original := runtime.DeepCopyJSON(tt.spec)
+			object.Object["spec"] = tt.spec
+			changed, err := tt.policy.Quiesce(object)
+			if err != nil {
+				t.Fatalf("Quiesce() error = %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("Quiesce() changed = %v, want %v", changed, tt.changed)
+			}
+			if !reflect.DeepEqual(object.Object["spec"], tt.want) {
+				t.Errorf("Quiesce() spec = %v, want %v", object.Object["spec"], tt.want)
+			}
+			changed, err = tt.policy.Unquiesce(object)
+			if err != nil {
+				t.Fatalf("Unquiesce() error = %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("Unquiesce() changed = %v, want %v", changed, tt.changed)
+			}
+			if !reflect.DeepEqual(object.Object["spec"], original) {
+				t.Errorf("Unquiesce() spec = %v, want %v", object.Object["spec"], original)
+			}
+			if _, found := object.GetAnnotations()[QuiescePolicyAnnotation]; found {
+				t.Errorf("Unquiesce() annotation not removed")
+			}
+		})
+	}
+}
+
+func TestQuiescePolicy_PodSelector(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		spec      map[string]interface{}
+		want      string
+		wantFound bool
+	}{
+		{
+			name: "path not set",
+			spec: map[string]interface{}{"selector": map[string]interface{}{"app": "kafka"}},
+		},
+		{
+			name:      "map of labels",
+			path:      "spec.selector",
+			spec:      map[string]interface{}{"selector": map[string]interface{}{"app": "kafka"}},
+			want:      "app=kafka",
+			wantFound: true,
+		},
+		{
+			name: "label selector",
+			path: "spec.selector",
+			spec: map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"app": "etcd"},
+				},
+			},
+			want:      "app=etcd",
+			wantFound: true,
+		},
+		{
+			name: "empty selector",
+			path: "spec.selector",
+			spec: map[string]interface{}{"selector": map[string]interface{}{}},
+		},
+		{
+			name: "not found",
+			path: "spec.selector",
+			spec: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := QuiescePolicy{Version: "v1", Kind: "Foo", Path: "spec.replicas", PodSelectorPath: tt.path}
+			object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": tt.spec}}
+			got, found, err := policy.PodSelector(object)
+			if err != nil {
+				t.Fatalf("PodSelector() error = %v", err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("PodSelector() found = %v, want %v", found, tt.wantFound)
+			}
+			if found && got.String() != tt.want {
+				t.Errorf("PodSelector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -589,6 +589,13 @@ func (t *Task) deleteMigrated() error {
 		return liberr.Wrap(err)
 	}
 
+	if t.hasClusterResources() {
+		err = t.deleteMigratedClusterResources()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
+
 	return nil
 }
 
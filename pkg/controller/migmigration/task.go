@@ -42,6 +42,16 @@ const (
 	PostBackupHooksFailed                  = "PostBackupHooksFailed"
 	PreRestoreHooksFailed                  = "PreRestoreHooksFailed"
 	PostRestoreHooksFailed                 = "PostRestoreHooksFailed"
+	PreQuiesceHooks                        = "PreQuiesceHooks"
+	PostQuiesceHooks                       = "PostQuiesceHooks"
+	PreVolumeCopyHooks                     = "PreVolumeCopyHooks"
+	PostVolumeCopyHooks                    = "PostVolumeCopyHooks"
+	PostVerifyHooks                        = "PostVerifyHooks"
+	PreQuiesceHooksFailed                  = "PreQuiesceHooksFailed"
+	PostQuiesceHooksFailed                 = "PostQuiesceHooksFailed"
+	PreVolumeCopyHooksFailed               = "PreVolumeCopyHooksFailed"
+	PostVolumeCopyHooksFailed              = "PostVolumeCopyHooksFailed"
+	PostVerifyHooksFailed                  = "PostVerifyHooksFailed"
 	EnsureInitialBackup                    = "EnsureInitialBackup"
 	InitialBackupCreated                   = "InitialBackupCreated"
 	InitialBackupFailed                    = "InitialBackupFailed"
@@ -72,10 +82,15 @@ const (
 	CreateDirectVolumeMigration            = "CreateDirectVolumeMigration"
 	WaitForDirectVolumeMigrationToComplete = "WaitForDirectVolumeMigrationToComplete"
 	DirectVolumeMigrationFailed            = "DirectVolumeMigrationFailed"
+	MigrateClusterResources                = "MigrateClusterResources"
 	EnsureFinalRestore                     = "EnsureFinalRestore"
 	FinalRestoreCreated                    = "FinalRestoreCreated"
 	FinalRestoreFailed                     = "FinalRestoreFailed"
+	RewriteImageReferences                 = "RewriteImageReferences"
 	Verification                           = "Verification"
+	RunVerificationChecks                  = "RunVerificationChecks"
+	VerificationChecksFailed               = "VerificationChecksFailed"
+	CompareResources                       = "CompareResources"
 	EnsureStagePodsDeleted                 = "EnsureStagePodsDeleted"
 	EnsureStagePodsTerminated              = "EnsureStagePodsTerminated"
 	EnsureAnnotationsDeleted               = "EnsureAnnotationsDeleted"
@@ -96,18 +111,22 @@ const (
 
 // Flags
 const (
-	Quiesce        = 0x001 // Only when QuiescePods (true).
-	HasStagePods   = 0x002 // Only when stage pods created.
-	HasPVs         = 0x004 // Only when PVs migrated.
-	HasVerify      = 0x008 // Only when the plan has enabled verification
-	HasISs         = 0x010 // Only when ISs migrated
-	DirectImage    = 0x020 // Only when using direct image migration
-	IndirectImage  = 0x040 // Only when using indirect image migration
-	DirectVolume   = 0x080 // Only when using direct volume migration
-	IndirectVolume = 0x100 // Only when using indirect volume migration
-	HasStageBackup = 0x200 // True when stage backup is needed
-	EnableImage    = 0x400 // True when disable_image_migration is unset
-	EnableVolume   = 0x800 // True when disable_volume is unset
+	Quiesce             = 0x001  // Only when QuiescePods (true).
+	HasStagePods        = 0x002  // Only when stage pods created.
+	HasPVs              = 0x004  // Only when PVs migrated.
+	HasVerify           = 0x008  // Only when the plan has enabled verification
+	HasISs              = 0x010  // Only when ISs migrated
+	DirectImage         = 0x020  // Only when using direct image migration
+	IndirectImage       = 0x040  // Only when using indirect image migration
+	DirectVolume        = 0x080  // Only when using direct volume migration
+	IndirectVolume      = 0x100  // Only when using indirect volume migration
+	HasStageBackup      = 0x200  // True when stage backup is needed
+	EnableImage         = 0x400  // True when disable_image_migration is unset
+	EnableVolume        = 0x800  // True when disable_volume is unset
+	DestRegistry        = 0x1000 // Only when images are migrated to a registry outside the destination cluster
+	HasVerifyChecks     = 0x2000 // Only when the plan has verification checks
+	HasCompare          = 0x4000 // Only when the migrated resources are compared
+	HasClusterResources = 0x8000 // Only when the plan migrates cluster-scoped resources
 )
 
 // Migration steps
@@ -147,6 +166,7 @@ var StageItinerary = Itinerary{
 		{Name: WaitForStaleStagePodsTerminated, Step: StepPrepare},
 		{Name: CreateRegistries, Step: StepPrepare, all: IndirectImage | EnableImage | HasISs},
 		{Name: CreateDirectImageMigration, Step: StepStageBackup, all: DirectImage | EnableImage},
+		{Name: PreVolumeCopyHooks, Step: StepStageBackup, all: DirectVolume | EnableVolume},
 		{Name: CreateDirectVolumeMigration, Step: StepStageBackup, all: DirectVolume | EnableVolume},
 		{Name: EnsureStagePodsFromRunning, Step: StepStageBackup, all: HasPVs | IndirectVolume},
 		{Name: EnsureStagePodsFromTemplates, Step: StepStageBackup, all: HasPVs | IndirectVolume},
@@ -158,8 +178,10 @@ var StageItinerary = Itinerary{
 		{Name: WaitForResticReady, Step: StepStageBackup, all: HasPVs | HasStagePods},
 		{Name: WaitForRegistriesReady, Step: StepStageBackup, all: IndirectImage | EnableImage | HasISs},
 		{Name: EnsureCloudSecretPropagated, Step: StepStageBackup},
+		{Name: PreQuiesceHooks, Step: StepStageBackup, all: Quiesce},
 		{Name: QuiesceApplications, Step: StepStageBackup, all: Quiesce},
 		{Name: EnsureQuiesced, Step: StepStageBackup, all: Quiesce},
+		{Name: PostQuiesceHooks, Step: StepStageBackup, all: Quiesce},
 		{Name: EnsureStageBackup, Step: StepStageBackup, all: HasStageBackup},
 		{Name: StageBackupCreated, Step: StepStageBackup, all: HasStageBackup},
 		{Name: EnsureStageBackupReplicated, Step: StepStageBackup, all: HasStageBackup},
@@ -167,6 +189,7 @@ var StageItinerary = Itinerary{
 		{Name: StageRestoreCreated, Step: StepStageRestore, all: HasStageBackup},
 		{Name: WaitForDirectImageMigrationToComplete, Step: StepDirectImage, all: DirectImage | EnableImage},
 		{Name: WaitForDirectVolumeMigrationToComplete, Step: StepDirectVolume, all: DirectVolume | EnableVolume},
+		{Name: PostVolumeCopyHooks, Step: StepDirectVolume, all: DirectVolume | EnableVolume},
 		{Name: DeleteRegistries, Step: StepCleanup},
 		{Name: EnsureStagePodsDeleted, Step: StepCleanup, all: HasStagePods},
 		{Name: EnsureStagePodsTerminated, Step: StepCleanup, all: HasStagePods},
@@ -203,8 +226,11 @@ var FinalItinerary = Itinerary{
 		{Name: RestartRestic, Step: StepStageBackup, all: HasStagePods},
 		{Name: AnnotateResources, Step: StepStageBackup, all: HasStageBackup},
 		{Name: WaitForResticReady, Step: StepStageBackup, any: HasPVs | HasStagePods},
+		{Name: PreQuiesceHooks, Step: StepStageBackup, all: Quiesce},
 		{Name: QuiesceApplications, Step: StepStageBackup, all: Quiesce},
 		{Name: EnsureQuiesced, Step: StepStageBackup, all: Quiesce},
+		{Name: PostQuiesceHooks, Step: StepStageBackup, all: Quiesce},
+		{Name: PreVolumeCopyHooks, Step: StepStageBackup, all: DirectVolume | EnableVolume},
 		{Name: CreateDirectVolumeMigration, Step: StepStageBackup, all: DirectVolume | EnableVolume},
 		{Name: EnsureStageBackup, Step: StepStageBackup, all: HasStageBackup},
 		{Name: StageBackupCreated, Step: StepStageBackup, all: HasStageBackup},
@@ -215,16 +241,22 @@ var FinalItinerary = Itinerary{
 		{Name: EnsureStagePodsTerminated, Step: StepStageRestore, all: HasStagePods},
 		{Name: WaitForDirectImageMigrationToComplete, Step: StepDirectImage, all: DirectImage | EnableImage},
 		{Name: WaitForDirectVolumeMigrationToComplete, Step: StepDirectVolume, all: DirectVolume | EnableVolume},
+		{Name: PostVolumeCopyHooks, Step: StepDirectVolume, all: DirectVolume | EnableVolume},
 		{Name: EnsureAnnotationsDeleted, Step: StepRestore, all: HasStageBackup},
 		{Name: EnsureInitialBackupReplicated, Step: StepRestore},
 		{Name: PostBackupHooks, Step: StepRestore},
 		{Name: PreRestoreHooks, Step: StepRestore},
+		{Name: MigrateClusterResources, Step: StepRestore, all: HasClusterResources},
 		{Name: EnsureFinalRestore, Step: StepRestore},
 		{Name: FinalRestoreCreated, Step: StepRestore},
+		{Name: RewriteImageReferences, Step: StepRestore, all: DirectImage | EnableImage | DestRegistry},
 		{Name: UnQuiesceDestApplications, Step: StepRestore},
 		{Name: PostRestoreHooks, Step: StepRestore},
 		{Name: DeleteRegistries, Step: StepCleanup},
 		{Name: Verification, Step: StepCleanup, all: HasVerify},
+		{Name: RunVerificationChecks, Step: StepCleanup, all: HasVerifyChecks},
+		{Name: PostVerifyHooks, Step: StepCleanup},
+		{Name: CompareResources, Step: StepCleanup, all: HasCompare},
 		{Name: Completed, Step: StepCleanup},
 	},
 }
@@ -251,6 +283,7 @@ var FailedItinerary = Itinerary{
 	Phases: []Phase{
 		{Name: MigrationFailed, Step: StepCleanupHelpers},
 		{Name: DeleteRegistries, Step: StepCleanupHelpers},
+		{Name: DeleteHookJobs, Step: StepCleanupHelpers},
 		{Name: EnsureAnnotationsDeleted, Step: StepCleanupHelpers, all: HasStageBackup},
 		{Name: Completed, Step: StepCleanup},
 	},
@@ -264,6 +297,7 @@ var RollbackItinerary = Itinerary{
 		{Name: DeleteRestores, Step: StepCleanupVelero},
 		{Name: DeleteRegistries, Step: StepCleanupHelpers},
 		{Name: EnsureStagePodsDeleted, Step: StepCleanupHelpers},
+		{Name: DeleteHookJobs, Step: StepCleanupHelpers},
 		{Name: EnsureAnnotationsDeleted, Step: StepCleanupHelpers, any: HasPVs | HasISs},
 		{Name: DeleteMigrated, Step: StepCleanupMigrated},
 		{Name: EnsureMigratedDeleted, Step: StepCleanupMigrated},
@@ -279,9 +313,9 @@ type Phase struct {
 	// High level Step this phase belongs to
 	Step string
 	// Step included when ALL flags evaluate true.
-	all uint16
+	all uint32
 	// Step included when ANY flag evaluates true.
-	any uint16
+	any uint32
 }
 
 // Get a progress report.
@@ -327,10 +361,10 @@ type Task struct {
 
 // Run the task.
 // Each call will:
-//   1. Run the current phase.
-//   2. Update the phase to the next phase.
-//   3. Set the Requeue (as appropriate).
-//   4. Return.
+//  1. Run the current phase.
+//  2. Update the phase to the next phase.
+//  3. Set the Requeue (as appropriate).
+//  4. Return.
 func (t *Task) Run() error {
 	t.Requeue = FastReQ
 	t.Log.Info("[RUN]", "stage", t.stage(), "phase", t.Phase)
@@ -608,12 +642,20 @@ func (t *Task) Run() error {
 			t.Requeue = PollReQ
 		}
 	case QuiesceApplications:
-		err := t.quiesceApplications()
+		quiesced, err := t.quiesceGroups()
 		if err != nil {
 			return liberr.Wrap(err)
 		}
-		if err = t.next(); err != nil {
-			return liberr.Wrap(err)
+		if quiesced {
+			err = t.quiesceApplications()
+			if err != nil {
+				return liberr.Wrap(err)
+			}
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
 		}
 	case EnsureQuiesced:
 		quiesced, err := t.ensureQuiescedPodsTerminated()
@@ -636,12 +678,16 @@ func (t *Task) Run() error {
 			return liberr.Wrap(err)
 		}
 	case UnQuiesceDestApplications:
-		err := t.unQuiesceDestApplications()
+		started, err := t.unQuiesceDestApplications()
 		if err != nil {
 			return liberr.Wrap(err)
 		}
-		if err = t.next(); err != nil {
-			return liberr.Wrap(err)
+		if started {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
 		}
 	case CreateDirectVolumeMigration:
 		err := t.createDirectVolumeMigration()
@@ -840,6 +886,18 @@ func (t *Task) Run() error {
 		} else {
 			t.Requeue = PollReQ
 		}
+	case MigrateClusterResources:
+		established, err := t.migrateClusterResources()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if established {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+		}
 	case EnsureFinalRestore:
 		backup, err := t.getInitialBackup()
 		if err != nil {
@@ -877,6 +935,14 @@ func (t *Task) Run() error {
 		} else {
 			t.Requeue = PollReQ
 		}
+	case RewriteImageReferences:
+		err := t.rewriteImageReferences()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if err = t.next(); err != nil {
+			return liberr.Wrap(err)
+		}
 	case PostRestoreHooks:
 		status, err := t.runHooks(migapi.PostRestoreHookPhase)
 		if err != nil {
@@ -890,6 +956,71 @@ func (t *Task) Run() error {
 		} else {
 			t.Requeue = PollReQ
 		}
+	case PreQuiesceHooks:
+		status, err := t.runHooks(migapi.PreQuiesceHookPhase)
+		if err != nil {
+			t.fail(PreQuiesceHooksFailed, []string{err.Error()})
+			return liberr.Wrap(err)
+		}
+		if status {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+		}
+	case PostQuiesceHooks:
+		status, err := t.runHooks(migapi.PostQuiesceHookPhase)
+		if err != nil {
+			t.fail(PostQuiesceHooksFailed, []string{err.Error()})
+			return liberr.Wrap(err)
+		}
+		if status {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+		}
+	case PreVolumeCopyHooks:
+		status, err := t.runHooks(migapi.PreVolumeCopyHookPhase)
+		if err != nil {
+			t.fail(PreVolumeCopyHooksFailed, []string{err.Error()})
+			return liberr.Wrap(err)
+		}
+		if status {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+		}
+	case PostVolumeCopyHooks:
+		status, err := t.runHooks(migapi.PostVolumeCopyHookPhase)
+		if err != nil {
+			t.fail(PostVolumeCopyHooksFailed, []string{err.Error()})
+			return liberr.Wrap(err)
+		}
+		if status {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+		}
+	case PostVerifyHooks:
+		status, err := t.runHooks(migapi.PostVerifyHookPhase)
+		if err != nil {
+			t.fail(PostVerifyHooksFailed, []string{err.Error()})
+			return liberr.Wrap(err)
+		}
+		if status {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		} else {
+			t.Requeue = PollReQ
+		}
 	case Verification:
 		completed, err := t.VerificationCompleted()
 		if err != nil {
@@ -902,6 +1033,25 @@ func (t *Task) Run() error {
 		} else {
 			t.Requeue = PollReQ
 		}
+	case RunVerificationChecks:
+		completed, failed, err := t.runVerificationChecks()
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if !completed {
+			t.Requeue = PollReQ
+		} else if len(failed) > 0 {
+			t.fail(VerificationChecksFailed, failed)
+		} else {
+			if err = t.next(); err != nil {
+				return liberr.Wrap(err)
+			}
+		}
+	case CompareResources:
+		t.compareMigratedResources()
+		if err := t.next(); err != nil {
+			return liberr.Wrap(err)
+		}
 	case Canceling:
 		// Skip directly to Completed if the Cancel was set on a Rollback migration.
 		if t.rollback() {
@@ -962,6 +1112,10 @@ func (t *Task) Run() error {
 			return liberr.Wrap(err)
 		}
 		if status {
+			// Delete the hook source credentials left in the hook namespaces.
+			if err = t.deleteHookSourceSecrets(); err != nil {
+				return liberr.Wrap(err)
+			}
 			if err = t.next(); err != nil {
 				return liberr.Wrap(err)
 			}
@@ -1219,6 +1373,18 @@ func (t *Task) allFlags(phase Phase) (bool, error) {
 	if phase.all&EnableVolume != 0 && t.PlanResources.MigPlan.IsVolumeMigrationDisabled() {
 		return false, nil
 	}
+	if phase.all&DestRegistry != 0 && !t.destImageRegistry() {
+		return false, nil
+	}
+	if phase.all&HasVerifyChecks != 0 && !t.hasVerificationChecks() {
+		return false, nil
+	}
+	if phase.all&HasCompare != 0 && !t.compareResources() {
+		return false, nil
+	}
+	if phase.all&HasClusterResources != 0 && !t.hasClusterResources() {
+		return false, nil
+	}
 	if phase.all&HasStageBackup != 0 {
 		hasImageStream, err := t.hasImageStreams()
 		if err != nil {
@@ -1247,6 +1413,9 @@ func (t *Task) anyFlags(phase Phase) (bool, error) {
 	if phase.any&HasVerify != 0 && t.hasVerify() {
 		return true, nil
 	}
+	if phase.any&HasVerifyChecks != 0 && t.hasVerificationChecks() {
+		return true, nil
+	}
 	if phase.any&HasISs != 0 {
 		hasImageStream, err := t.hasImageStreams()
 		if err != nil {
@@ -1274,6 +1443,9 @@ func (t *Task) anyFlags(phase Phase) (bool, error) {
 	if phase.any&EnableVolume != 0 && !t.PlanResources.MigPlan.IsVolumeMigrationDisabled() {
 		return false, nil
 	}
+	if phase.any&DestRegistry != 0 && t.destImageRegistry() {
+		return true, nil
+	}
 	if phase.any&HasStageBackup != 0 {
 		hasImageStream, err := t.hasImageStreams()
 		if err != nil {
@@ -1283,7 +1455,7 @@ func (t *Task) anyFlags(phase Phase) (bool, error) {
 			return true, nil
 		}
 	}
-	return phase.any == uint16(0), nil
+	return phase.any == uint32(0), nil
 }
 
 // Phase fail.
@@ -1477,6 +1649,11 @@ func (t *Task) directImageMigration() bool {
 	return !t.indirectImageMigration()
 }
 
+// Returns true if images are migrated to a registry outside the destination cluster
+func (t *Task) destImageRegistry() bool {
+	return t.PlanResources.MigPlan.Spec.DestImageRegistry != nil
+}
+
 // Returns true if the IndirectVolumeMigration override on the plan is set (plan is configured not to do direct migration)
 func (t *Task) indirectVolumeMigration() bool {
 	return t.PlanResources.MigPlan.Spec.IndirectVolumeMigration
@@ -189,3 +189,28 @@ func TestTask_getStagePVs(t1 *testing.T) {
 		})
 	}
 }
+
+func TestTask_next_PostVerifyHooks(t *testing.T) {
+	task := &Task{
+		Owner: &migapi.MigMigration{
+			Spec: migapi.MigMigrationSpec{Verify: false},
+		},
+		PlanResources: &migapi.PlanResources{
+			MigPlan: &migapi.MigPlan{
+				Spec: migapi.MigPlanSpec{
+					Hooks: []migapi.MigPlanHook{
+						{Phase: migapi.PostVerifyHookPhase},
+					},
+				},
+			},
+		},
+		Phase:     DeleteRegistries,
+		Itinerary: FinalItinerary,
+	}
+	if err := task.next(); err != nil {
+		t.Fatalf("next() error = %v", err)
+	}
+	if task.Phase != PostVerifyHooks {
+		t.Errorf("next() = %s, want %s", task.Phase, PostVerifyHooks)
+	}
+}
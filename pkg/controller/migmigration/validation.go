@@ -42,6 +42,10 @@ const (
 	StaleDestVeleroCRsDeleted          = "StaleDestVeleroCRsDeleted"
 	StaleResticCRsDeleted              = "StaleResticCRsDeleted"
 	DirectVolumeMigrationBlocked       = "DirectVolumeMigrationBlocked"
+	ResourceDifferencesFound           = "ResourceDifferencesFound"
+	ResourceComparisonFailed           = "ResourceComparisonFailed"
+	HookFailuresIgnored                = "HookFailuresIgnored"
+	ClusterResourceConflict            = "ClusterResourceConflict"
 )
 
 // Categories
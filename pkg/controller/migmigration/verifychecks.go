@@ -0,0 +1,369 @@
+package migmigration
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/pods"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Verification check settings.
+const (
+	// How long failed checks are retried by default.
+	VerificationTimeout = time.Minute * 10
+	// How often failed checks are retried.
+	VerificationCheckInterval = time.Second * 10
+	// How long a check may run.
+	VerificationCheckTimeout = time.Second * 30
+)
+
+// Get whether the plan has verification checks.
+func (t *Task) hasVerificationChecks() bool {
+	verification := t.PlanResources.MigPlan.Spec.Verification
+	return verification != nil && len(verification.Checks) > 0
+}
+
+// Run the verification checks on the destination cluster.
+// Checks not passed are run again until they pass or the timeout is reached.
+// Returns: `true` when all checks passed or the timeout is reached, and
+// the failed checks when the timeout is reached.
+func (t *Task) runVerificationChecks() (bool, []string, error) {
+	verification := t.PlanResources.MigPlan.Spec.Verification
+	status := t.Owner.Status.Verification
+	if status == nil {
+		status = &migapi.VerificationStatus{
+			StartTimestamp: &metav1.Time{Time: time.Now()},
+		}
+		t.Owner.Status.Verification = status
+	}
+	timeout := VerificationTimeout
+	if verification.Timeout != nil {
+		timeout = verification.Timeout.Duration
+	}
+	for i := range verification.Checks {
+		check := &verification.Checks[i]
+		result := status.GetResult(check.Name)
+		if result.Passed {
+			continue
+		}
+		if result.LastAttemptTimestamp != nil &&
+			time.Since(result.LastAttemptTimestamp.Time) < VerificationCheckInterval {
+			continue
+		}
+		err := t.runVerificationCheck(check)
+		result.Attempts++
+		result.LastAttemptTimestamp = &metav1.Time{Time: time.Now()}
+		if err != nil {
+			result.Message = err.Error()
+			t.Log.Info("Verification check failed.",
+				"check", check.Name,
+				"attempts", result.Attempts,
+				"error", err.Error())
+		} else {
+			result.Passed = true
+			result.Message = ""
+		}
+	}
+
+	progress := []string{}
+	failed := []string{}
+	for _, result := range status.Results {
+		if result.Passed {
+			progress = append(progress, fmt.Sprintf("Check %s: Passed", result.Name))
+			continue
+		}
+		progress = append(progress,
+			fmt.Sprintf("Check %s: Failed %d time(s): %s", result.Name, result.Attempts, result.Message))
+		failed = append(failed, fmt.Sprintf("Verification check %s failed: %s", result.Name, result.Message))
+	}
+	t.setProgress(progress)
+
+	if len(failed) == 0 {
+		return true, nil, nil
+	}
+	if time.Since(status.StartTimestamp.Time) < timeout {
+		return false, nil, nil
+	}
+
+	return true, failed, nil
+}
+
+// Run a verification check.
+// Returns: an error describing why the check failed.
+func (t *Task) runVerificationCheck(check *migapi.VerificationCheck) error {
+	// The checks are run with the controller privileges, only the
+	// migrated namespaces may be checked.
+	for _, namespace := range check.Namespaces() {
+		if !t.isDestinationNamespace(namespace) {
+			return fmt.Errorf("namespace %s is not a destination namespace of the plan", namespace)
+		}
+	}
+	switch {
+	case check.HTTPGet != nil:
+		return t.runHTTPGetCheck(check.HTTPGet)
+	case check.Exec != nil:
+		return t.runExecCheck(check.Exec)
+	case check.ObjectCount != nil:
+		return t.runObjectCountCheck(check.ObjectCount)
+	}
+	return fmt.Errorf("no check defined")
+}
+
+// Get whether the namespace is a destination namespace of the plan.
+func (t *Task) isDestinationNamespace(namespace string) bool {
+	for _, ns := range t.destinationNamespaces() {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Build the REST configuration for the destination cluster.
+func (t *Task) verificationRestConfig() (*rest.Config, error) {
+	restCfg, err := t.PlanResources.DestMigCluster.BuildRestConfig(t.Client)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	restCfg = rest.CopyConfig(restCfg)
+	restCfg.Timeout = VerificationCheckTimeout
+	return restCfg, nil
+}
+
+// Get the dial function used to connect to the destination cluster.
+// Connections are tunneled through the cluster proxy when configured.
+func (t *Task) verificationDial() (func(context.Context, string, string) (net.Conn, error), error) {
+	restCfg, err := t.verificationRestConfig()
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	if restCfg.Dial != nil {
+		return restCfg.Dial, nil
+	}
+	dialer := &net.Dialer{Timeout: VerificationCheckTimeout}
+	return dialer.DialContext, nil
+}
+
+// Send an HTTP GET request to a service or a route.
+func (t *Task) runHTTPGetCheck(check *migapi.HTTPGetCheck) error {
+	expected := check.ExpectedStatus
+	if expected == 0 {
+		expected = http.StatusOK
+	}
+	path := "/" + strings.TrimPrefix(check.Path, "/")
+	var statusCode int
+	var err error
+	if check.Route != "" {
+		statusCode, err = t.getRoute(check, path)
+	} else {
+		statusCode, err = t.getService(check, path)
+	}
+	if err != nil {
+		return err
+	}
+	if statusCode != expected {
+		return fmt.Errorf("expected HTTP status %d, got %d", expected, statusCode)
+	}
+	return nil
+}
+
+// Send an HTTP GET request to a service through the API server proxy.
+// Returns: the response status.
+func (t *Task) getService(check *migapi.HTTPGetCheck, path string) (int, error) {
+	restCfg, err := t.verificationRestConfig()
+	if err != nil {
+		return 0, err
+	}
+	restClient, err := apiutil.RESTClientForGVK(
+		schema.GroupVersionKind{
+			Version: "v1",
+			Kind:    "services",
+		},
+		restCfg,
+		serializer.NewCodecFactory(scheme.Scheme))
+	if err != nil {
+		return 0, err
+	}
+	name := check.Service
+	if check.Port.String() != "" && check.Port.String() != "0" {
+		name = name + ":" + check.Port.String()
+	}
+	if check.Scheme == v1.URISchemeHTTPS {
+		name = "https:" + name
+	}
+	result := restClient.Get().
+		Namespace(check.Namespace).
+		Resource("services").
+		Name(name).
+		SubResource("proxy").
+		Suffix(path).
+		Do()
+	statusCode := 0
+	result.StatusCode(&statusCode)
+	if statusCode == 0 {
+		return 0, result.Error()
+	}
+	return statusCode, nil
+}
+
+// Send an HTTP GET request to the host of a route.
+// Returns: the response status.
+func (t *Task) getRoute(check *migapi.HTTPGetCheck, path string) (int, error) {
+	client, err := t.getDestinationClient()
+	if err != nil {
+		return 0, err
+	}
+	route := routev1.Route{}
+	err = client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Namespace: check.Namespace,
+			Name:      check.Route,
+		},
+		&route)
+	if err != nil {
+		return 0, err
+	}
+	url := "http://" + route.Spec.Host + path
+	if route.Spec.TLS != nil {
+		url = "https://" + route.Spec.Host + path
+	}
+	dial, err := t.verificationDial()
+	if err != nil {
+		return 0, err
+	}
+	httpClient := &http.Client{
+		Timeout: VerificationCheckTimeout,
+		Transport: &http.Transport{
+			DialContext: dial,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: check.Insecure,
+			},
+		},
+	}
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	response.Body.Close()
+	return response.StatusCode, nil
+}
+
+// Run a command in a running pod matched by the selector.
+func (t *Task) runExecCheck(check *migapi.ExecCheck) error {
+	client, err := t.getDestinationClient()
+	if err != nil {
+		return err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(check.Selector)
+	if err != nil {
+		return err
+	}
+	list := v1.PodList{}
+	err = client.List(
+		context.TODO(),
+		&k8sclient.ListOptions{
+			Namespace:     check.Namespace,
+			LabelSelector: selector,
+		},
+		&list)
+	if err != nil {
+		return err
+	}
+	var pod *v1.Pod
+	for i := range list.Items {
+		if list.Items[i].Status.Phase == v1.PodRunning {
+			pod = &list.Items[i]
+			break
+		}
+	}
+	if pod == nil {
+		return fmt.Errorf("no running pod found in namespace %s matching %s", check.Namespace, selector.String())
+	}
+	restCfg, err := t.verificationRestConfig()
+	if err != nil {
+		return err
+	}
+	command := pods.PodCommand{
+		RestCfg:   restCfg,
+		Pod:       pod,
+		Container: check.Container,
+		Args:      check.Command,
+	}
+	err = command.Run()
+	if err != nil {
+		stderr := strings.TrimSpace(command.Err.String())
+		if stderr != "" {
+			return fmt.Errorf("command failed in pod %s: %s: %s", pod.Name, err.Error(), stderr)
+		}
+		return fmt.Errorf("command failed in pod %s: %s", pod.Name, err.Error())
+	}
+	return nil
+}
+
+// Compare the number of objects of a kind in the source and destination namespaces.
+func (t *Task) runObjectCountCheck(check *migapi.ObjectCountCheck) error {
+	gvk := schema.GroupVersionKind{
+		Group:   check.Group,
+		Version: check.Version,
+		Kind:    check.Kind,
+	}
+	srcClient, err := t.getSourceClient()
+	if err != nil {
+		return err
+	}
+	srcCount, err := countObjects(srcClient, gvk, t.sourceNamespaces())
+	if err != nil {
+		return err
+	}
+	destClient, err := t.getDestinationClient()
+	if err != nil {
+		return err
+	}
+	destCount, err := countObjects(destClient, gvk, t.destinationNamespaces())
+	if err != nil {
+		return err
+	}
+	if srcCount != destCount {
+		return fmt.Errorf("found %d %s objects on the source cluster and %d on the destination cluster",
+			srcCount, gvk.Kind, destCount)
+	}
+	return nil
+}
+
+// Count the objects of a kind in the namespaces.
+func countObjects(client k8sclient.Client, gvk schema.GroupVersionKind, namespaces []string) (int, error) {
+	count := 0
+	for _, ns := range namespaces {
+		list := unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Kind:    gvk.Kind + "List",
+		})
+		err := client.List(context.TODO(), k8sclient.InNamespace(ns), &list)
+		if err != nil {
+			return 0, err
+		}
+		count += len(list.Items)
+	}
+	return count, nil
+}
@@ -0,0 +1,88 @@
+package migplan
+
+import (
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/clusterresource"
+	migctl "github.com/konveyor/mig-controller/pkg/controller/migmigration"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+)
+
+// Discover the cluster-scoped resources migrated with the plan and
+// check them against the destination cluster.
+func (r ReconcileMigPlan) discoverClusterResources(plan *migapi.MigPlan) error {
+	if !plan.Spec.ClusterResources.Enabled() {
+		plan.Status.ClusterResources = nil
+		return nil
+	}
+	// No spec change this time
+	if plan.HasReconciled() || !clustersReady(plan) {
+		plan.Status.StageCondition(ClusterResourceConflict)
+		return nil
+	}
+	srcClient, err := r.clusterDynamicClient(plan.Spec.SrcMigClusterRef)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	dstClient, err := r.clusterDynamicClient(plan.Spec.DestMigClusterRef)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	discovery := clusterresource.Discovery{
+		Client:     srcClient,
+		Options:    plan.Spec.ClusterResources,
+		Namespaces: plan.GetSourceNamespaces(),
+	}
+	resources, err := discovery.Run()
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = clusterresource.Check(
+		dstClient,
+		resources,
+		plan.GetNamespaceMapping(),
+		labels.Set{migctl.MigPlanLabel: string(plan.UID)})
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	plan.Status.ClusterResources = []migapi.ClusterResource{}
+	conflicts := []string{}
+	for _, resource := range resources {
+		plan.Status.ClusterResources = append(plan.Status.ClusterResources, resource.ClusterResource)
+		if resource.Conflict {
+			conflicts = append(conflicts, resource.String())
+		}
+	}
+	if len(conflicts) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     ClusterResourceConflict,
+			Status:   True,
+			Reason:   Conflict,
+			Category: Warn,
+			Message: "Cluster-scoped resources [] exist on the destination cluster with a different content" +
+				" and will not be migrated.",
+			Items: conflicts,
+		})
+	}
+
+	return nil
+}
+
+// Get a dynamic client for a referenced cluster.
+func (r ReconcileMigPlan) clusterDynamicClient(ref *kapi.ObjectReference) (dynamic.Interface, error) {
+	cluster, err := migapi.GetCluster(r, ref)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	client, err := cluster.GetClient(r)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(client.RestConfig())
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	return dynamicClient, nil
+}
@@ -32,11 +32,13 @@ import (
 	miganalytic "github.com/konveyor/mig-controller/pkg/controller/miganalytic"
 	migctl "github.com/konveyor/mig-controller/pkg/controller/migmigration"
 	migref "github.com/konveyor/mig-controller/pkg/reference"
+	"github.com/konveyor/mig-controller/pkg/resourcefilter"
 	"github.com/konveyor/mig-controller/pkg/settings"
 	kapi "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -296,6 +298,9 @@ func (r *ReconcileMigPlan) Reconcile(request reconcile.Request) (reconcile.Resul
 		}
 	}
 
+	// Preview the destination PVCs
+	r.previewPVCTransforms(plan)
+
 	// Ready
 	plan.Status.SetReady(
 		plan.Status.HasCondition(StorageEnsured, PvsDiscovered) &&
@@ -431,10 +436,52 @@ func (r *ReconcileMigPlan) planSuspended(plan *migapi.MigPlan) error {
 	return nil
 }
 
-// Update Status.ExcludedResources based on settings
+// Update Status.ExcludedResources based on settings and the resource filters.
+// Update Status.IncludedResources based on the resource filters.
+// The filter rules are resolved to resource names using the source cluster
+// discovery, skipped until the source cluster is ready. The rules are
+// resolved again only when changed or the plan is refreshed. Groups that
+// cannot be discovered are skipped.
 func (r *ReconcileMigPlan) setExcludedResourceList(plan *migapi.MigPlan) error {
 	excludedResources := Settings.Plan.ExcludedResources
+	if plan.Spec.ResourceFilters == nil {
+		plan.Status.ExcludedResources = excludedResources
+		plan.Status.IncludedResources = nil
+		plan.Status.ResourceFiltersDigest = ""
+		return nil
+	}
+	if plan.ResourceFiltersResolved() && !plan.Spec.Refresh {
+		return nil
+	}
 	plan.Status.ExcludedResources = excludedResources
+	plan.Status.IncludedResources = nil
+	cluster, err := plan.GetSourceCluster(r)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if cluster == nil || !cluster.Status.IsReady() {
+		return nil
+	}
+	client, err := cluster.GetClient(r)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	resources, err := client.ServerPreferredNamespacedResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return liberr.Wrap(err)
+		}
+		log.Info("Resource filters resolved with partial discovery.", "error", err.Error())
+	}
+	filters := plan.Spec.ResourceFilters
+	plan.Status.ExcludedResources = append([]string{}, excludedResources...)
+	for _, name := range resourcefilter.ExcludedResources(filters, resources) {
+		if !plan.IsResourceExcluded(name) {
+			plan.Status.ExcludedResources = append(plan.Status.ExcludedResources, name)
+		}
+	}
+	plan.Status.IncludedResources = resourcefilter.IncludedResources(filters, resources)
+	plan.MarkResourceFiltersResolved()
 	return nil
 }
 
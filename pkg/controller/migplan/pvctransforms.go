@@ -0,0 +1,99 @@
+package migplan
+
+import (
+	"fmt"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Preview the destination PVCs created by direct volume migrations
+// with the PVC transform rules applied. The preview is built from the
+// PV list: the rules are matched and the size is requested as the source
+// PVCs, the labels of the source PVCs are not included.
+func (r ReconcileMigPlan) previewPVCTransforms(plan *migapi.MigPlan) {
+	if len(plan.Spec.PVCTransforms) == 0 || plan.Spec.IndirectVolumeMigration {
+		plan.Status.PVCPreview = nil
+		return
+	}
+	preview := []migapi.PVCPreview{}
+	for _, pv := range plan.Spec.PersistentVolumes.List {
+		if pv.Selection.Action != migapi.PvCopyAction || pv.Selection.CopyMethod != migapi.PvFilesystemCopyMethod {
+			continue
+		}
+		pvc := destinationPVC(pv)
+		entry := migapi.PVCPreview{
+			ObjectReference: &kapi.ObjectReference{
+				Namespace: pv.PVC.Namespace,
+				Name:      pv.PVC.Name,
+			},
+		}
+		index, rule := plan.FindPVCTransform(pv.PVC.Namespace, pv.PVC.StorageClass)
+		if rule != nil {
+			err := rule.Apply(&pvc)
+			if err != nil {
+				// Reported by the validation.
+				continue
+			}
+			entry.Rule = &index
+		}
+		if pvc.Spec.StorageClassName != nil {
+			entry.StorageClass = *pvc.Spec.StorageClassName
+		}
+		entry.AccessModes = pvc.Spec.AccessModes
+		entry.VolumeMode = pvc.Spec.VolumeMode
+		entry.Capacity = pvc.Spec.Resources.Requests[kapi.ResourceStorage]
+		entry.Labels = pvc.Labels
+		entry.Annotations = pvc.Annotations
+		preview = append(preview, entry)
+	}
+	plan.Status.PVCPreview = preview
+}
+
+// Build the destination PVC of a PV as created by direct volume migrations
+// before the transform rules are applied. The size is requested as the
+// source PVC, increased to the PV (proposed) capacity when PV resizing
+// is enabled.
+func destinationPVC(pv migapi.PV) kapi.PersistentVolumeClaim {
+	storageClass := pv.Selection.StorageClass
+	accessModes := pv.PVC.AccessModes
+	if pv.Selection.AccessMode != "" {
+		accessModes = []kapi.PersistentVolumeAccessMode{pv.Selection.AccessMode}
+	}
+	capacity := pv.PVC.Capacity
+	if Settings.DvmOpts.EnablePVResizing {
+		for _, resized := range []resource.Quantity{pv.Capacity, pv.ProposedCapacity} {
+			if resized.Cmp(capacity) > 0 {
+				capacity = resized
+			}
+		}
+	}
+	return kapi.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pv.PVC.Namespace,
+			Name:      pv.PVC.Name,
+		},
+		Spec: kapi.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			AccessModes:      accessModes,
+			Resources: kapi.ResourceRequirements{
+				Requests: kapi.ResourceList{
+					kapi.ResourceStorage: capacity,
+				},
+			},
+		},
+	}
+}
+
+// Get the problems of the PVC transform rules.
+func invalidPVCTransforms(plan *migapi.MigPlan) []string {
+	invalid := []string{}
+	for i := range plan.Spec.PVCTransforms {
+		for _, problem := range plan.Spec.PVCTransforms[i].Invalid() {
+			invalid = append(invalid, fmt.Sprintf("pvcTransforms[%d]: %s", i, problem))
+		}
+	}
+	return invalid
+}
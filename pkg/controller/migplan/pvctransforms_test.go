@@ -0,0 +1,131 @@
+package migplan
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestReconcileMigPlan_previewPVCTransforms(t *testing.T) {
+	filesystem := kapi.PersistentVolumeFilesystem
+	minSize := resource.MustParse("20Gi")
+	// The rules are matched with the storage class of the PVC and
+	// the size is requested as the PVC, not from the PV.
+	pv := func(ns, name, storageClass, request string) migapi.PV {
+		return migapi.PV{
+			Capacity:     resource.MustParse("100Gi"),
+			StorageClass: "pv-" + storageClass,
+			PVC: migapi.PVC{
+				Namespace:    ns,
+				Name:         name,
+				AccessModes:  []kapi.PersistentVolumeAccessMode{kapi.ReadWriteOnce},
+				StorageClass: storageClass,
+				Capacity:     resource.MustParse(request),
+			},
+			Selection: migapi.Selection{
+				Action:       migapi.PvCopyAction,
+				CopyMethod:   migapi.PvFilesystemCopyMethod,
+				StorageClass: "standard",
+			},
+		}
+	}
+	plan := &migapi.MigPlan{}
+	plan.Spec.PersistentVolumes.List = []migapi.PV{
+		pv("ns1", "gp2", "gp2", "10Gi"),
+		pv("ns1", "nfs", "nfs", "10Gi"),
+		pv("ns2", "nfs", "nfs", "1Gi"),
+		pv("ns1", "other", "local", "5Gi"),
+	}
+	plan.Spec.PersistentVolumes.List[3].Selection.Action = migapi.PvSkipAction
+	plan.Spec.PVCTransforms = []migapi.PVCTransformRule{
+		{
+			SourceStorageClass: "gp2",
+			StorageClass:       "gp3",
+			SizeMultiplier:     "1.5",
+			Labels:             map[string]string{"tier": "fast"},
+		},
+		{
+			SourceStorageClass: "nfs",
+			Namespaces:         []string{"ns2"},
+			StorageClass:       "ceph",
+			AccessModes:        []kapi.PersistentVolumeAccessMode{kapi.ReadWriteMany},
+			VolumeMode:         &filesystem,
+			MinSize:            &minSize,
+			Annotations:        map[string]string{"backup": "true"},
+		},
+	}
+	r := ReconcileMigPlan{}
+	r.previewPVCTransforms(plan)
+	rule0, rule1 := 0, 1
+	want := []migapi.PVCPreview{
+		{
+			ObjectReference: &kapi.ObjectReference{Namespace: "ns1", Name: "gp2"},
+			Rule:            &rule0,
+			StorageClass:    "gp3",
+			AccessModes:     []kapi.PersistentVolumeAccessMode{kapi.ReadWriteOnce},
+			Capacity:        resource.MustParse("15Gi"),
+			Labels:          map[string]string{"tier": "fast"},
+		},
+		{
+			ObjectReference: &kapi.ObjectReference{Namespace: "ns1", Name: "nfs"},
+			StorageClass:    "standard",
+			AccessModes:     []kapi.PersistentVolumeAccessMode{kapi.ReadWriteOnce},
+			Capacity:        resource.MustParse("10Gi"),
+		},
+		{
+			ObjectReference: &kapi.ObjectReference{Namespace: "ns2", Name: "nfs"},
+			Rule:            &rule1,
+			StorageClass:    "ceph",
+			AccessModes:     []kapi.PersistentVolumeAccessMode{kapi.ReadWriteMany},
+			VolumeMode:      &filesystem,
+			Capacity:        resource.MustParse("20Gi"),
+			Annotations:     map[string]string{"backup": "true"},
+		},
+	}
+	if len(plan.Status.PVCPreview) != len(want) {
+		t.Fatalf("previewPVCTransforms() = %+v", plan.Status.PVCPreview)
+	}
+	for i, got := range plan.Status.PVCPreview {
+		w := want[i]
+		if !reflect.DeepEqual(got.ObjectReference, w.ObjectReference) ||
+			!reflect.DeepEqual(got.Rule, w.Rule) ||
+			got.StorageClass != w.StorageClass ||
+			!reflect.DeepEqual(got.AccessModes, w.AccessModes) ||
+			!reflect.DeepEqual(got.VolumeMode, w.VolumeMode) ||
+			got.Capacity.Cmp(w.Capacity) != 0 ||
+			!reflect.DeepEqual(got.Labels, w.Labels) ||
+			!reflect.DeepEqual(got.Annotations, w.Annotations) {
+			t.Errorf("previewPVCTransforms()[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	plan.Spec.PVCTransforms = nil
+	r.previewPVCTransforms(plan)
+	if plan.Status.PVCPreview != nil {
+		t.Errorf("previewPVCTransforms() = %+v, want nil", plan.Status.PVCPreview)
+	}
+}
+
+func Test_invalidPVCTransforms(t *testing.T) {
+	mode := kapi.PersistentVolumeMode("Raw")
+	block := kapi.PersistentVolumeBlock
+	zero := resource.MustParse("0")
+	plan := &migapi.MigPlan{}
+	plan.Spec.PVCTransforms = []migapi.PVCTransformRule{
+		{SizeMultiplier: "1.2"},
+		{SizeMultiplier: "-1", MinSize: &zero, VolumeMode: &mode},
+		{VolumeMode: &block},
+	}
+	want := []string{
+		"pvcTransforms[1]: sizeMultiplier -1 not valid",
+		"pvcTransforms[1]: minSize 0 not valid",
+		"pvcTransforms[1]: volumeMode Raw not valid",
+		"pvcTransforms[2]: volumeMode Block not supported",
+	}
+	if got := invalidPVCTransforms(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("invalidPVCTransforms() = %v, want %v", got, want)
+	}
+}
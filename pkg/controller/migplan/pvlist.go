@@ -239,12 +239,18 @@ func (r *ReconcileMigPlan) getClaims(client k8sclient.Client, plan *migapi.MigPl
 		if !inNamespaces(pvc.Namespace, plan.GetSourceNamespaces()) {
 			continue
 		}
+		storageClass := ""
+		if pvc.Spec.StorageClassName != nil {
+			storageClass = *pvc.Spec.StorageClassName
+		}
 		claims = append(
 			claims, migapi.PVC{
 				Namespace:    pvc.Namespace,
 				Name:         pvc.Name,
 				AccessModes:  pvc.Spec.AccessModes,
 				HasReference: pvcInPodVolumes(pvc, podList),
+				StorageClass: storageClass,
+				Capacity:     pvc.Spec.Resources.Requests[core.ResourceStorage],
 			})
 	}
 
@@ -6,7 +6,9 @@ import (
 	"net"
 	"path"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	liberr "github.com/konveyor/controller/pkg/error"
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
@@ -14,9 +16,11 @@ import (
 	"github.com/konveyor/mig-controller/pkg/health"
 	"github.com/konveyor/mig-controller/pkg/pods"
 	migref "github.com/konveyor/mig-controller/pkg/reference"
+	"github.com/konveyor/mig-controller/pkg/resourcefilter"
 	"github.com/konveyor/mig-controller/pkg/settings"
 	kapi "k8s.io/api/core/v1"
 	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	k8sLabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -78,6 +82,24 @@ const (
 	InvalidHookSAName                          = "InvalidHookSAName"
 	HookPhaseUnknown                           = "HookPhaseUnknown"
 	HookPhaseDuplicate                         = "HookPhaseDuplicate"
+	SourceClusterUnhealthy                     = "SourceClusterUnhealthy"
+	DestinationClusterUnhealthy                = "DestinationClusterUnhealthy"
+	InvalidQuiesceGroup                        = "InvalidQuiesceGroup"
+	InvalidVerificationCheck                   = "InvalidVerificationCheck"
+	InvalidHookPolicy                          = "InvalidHookPolicy"
+	InvalidHookDependency                      = "InvalidHookDependency"
+	InvalidResourceFilter                      = "InvalidResourceFilter"
+	ClusterResourceConflict                    = "ClusterResourceConflict"
+	InvalidTransferSpec                        = "InvalidTransferSpec"
+	InvalidTransport                           = "InvalidTransport"
+	InvalidTLS                                 = "InvalidTLS"
+	InvalidTransferEngine                      = "InvalidTransferEngine"
+	InvalidTransferConcurrency                 = "InvalidTransferConcurrency"
+	InvalidBandwidthSchedule                   = "InvalidBandwidthSchedule"
+	InvalidTransferRetry                       = "InvalidTransferRetry"
+	InvalidPVCTransforms                       = "InvalidPVCTransforms"
+	IntraClusterVolumesNotMapped               = "IntraClusterVolumesNotMapped"
+	LocalTransferNotEnabled                    = "LocalTransferNotEnabled"
 )
 
 // Categories
@@ -101,6 +123,7 @@ const (
 	Conflict              = "Conflict"
 	NotHealthy            = "NotHealthy"
 	NodeSelectorsDetected = "NodeSelectorsDetected"
+	InvalidSelector       = "InvalidSelector"
 )
 
 // Statuses
@@ -175,12 +198,38 @@ func (r ReconcileMigPlan) validate(plan *migapi.MigPlan) error {
 		return liberr.Wrap(err)
 	}
 
+	// Quiesce groups
+	err = r.validateQuiesceGroups(plan)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	// Verification checks
+	r.validateVerificationChecks(plan)
+
+	// Resource filters
+	r.validateResourceFilters(plan)
+
+	// Transfer spec
+	r.validateTransferSpec(plan)
+	r.validateIntraClusterVolumes(plan)
+	err = r.validateTLS(plan)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
 	// GVK
 	err = r.compareGVK(plan)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
 
+	// Cluster-scoped resources
+	err = r.discoverClusterResources(plan)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
 	// Versions
 	err = r.validateOperatorVersions(plan)
 	if err != nil {
@@ -380,6 +429,41 @@ func (r ReconcileMigPlan) hasNodeSelectors(podList []kapi.Pod) bool {
 	return false
 }
 
+// Validate the health reported by a cluster.
+// The registry health is only considered when the registry is used by the plan.
+// Returns whether the cluster is unhealthy.
+func (r ReconcileMigPlan) validateClusterHealth(plan *migapi.MigPlan, cluster *migapi.MigCluster, cndType string, usesRegistry bool) bool {
+	blocking := []string{
+		migapi.VeleroNotReady,
+		migapi.NoSchedulableNodes,
+	}
+	if plan.Spec.IndirectVolumeMigration && len(plan.Spec.PersistentVolumes.List) > 0 {
+		blocking = append(blocking, migapi.ResticNotReady)
+	}
+	if usesRegistry {
+		blocking = append(blocking, migapi.RegistryNotHealthy)
+	}
+	issues := []string{}
+	for _, name := range blocking {
+		if cluster.Status.HasCondition(name) {
+			issues = append(issues, name)
+		}
+	}
+	if len(issues) == 0 {
+		return false
+	}
+	plan.Status.SetCondition(migapi.Condition{
+		Type:     cndType,
+		Status:   True,
+		Reason:   NotHealthy,
+		Category: Critical,
+		Message: fmt.Sprintf("The referenced cluster %s is not healthy: [], see the cluster conditions for details.",
+			path.Join(cluster.Namespace, cluster.Name)),
+		Items: issues,
+	})
+	return true
+}
+
 // Validate the referenced source cluster.
 func (r ReconcileMigPlan) validateSourceCluster(plan *migapi.MigPlan) error {
 	ref := plan.Spec.SrcMigClusterRef
@@ -426,6 +510,11 @@ func (r ReconcileMigPlan) validateSourceCluster(plan *migapi.MigPlan) error {
 		return nil
 	}
 
+	// Unhealthy
+	if r.validateClusterHealth(plan, cluster, SourceClusterUnhealthy, !plan.Spec.IndirectImageMigration) {
+		return nil
+	}
+
 	// No Registry Path
 	registryPath, err := cluster.GetRegistryPath(r)
 	if !plan.Spec.IndirectImageMigration && (err != nil || registryPath == "") {
@@ -501,9 +590,15 @@ func (r ReconcileMigPlan) validateDestinationCluster(plan *migapi.MigPlan) error
 		return nil
 	}
 
+	// Unhealthy
+	usesRegistry := !plan.Spec.IndirectImageMigration && plan.Spec.DestImageRegistry == nil
+	if r.validateClusterHealth(plan, cluster, DestinationClusterUnhealthy, usesRegistry) {
+		return nil
+	}
+
 	// No Registry Path
 	registryPath, err := cluster.GetRegistryPath(r)
-	if !plan.Spec.IndirectImageMigration && (err != nil || registryPath == "") {
+	if !plan.Spec.IndirectImageMigration && plan.Spec.DestImageRegistry == nil && (err != nil || registryPath == "") {
 		plan.Status.SetCondition(migapi.Condition{
 			Type:     DestinationClusterNoRegistryPath,
 			Status:   True,
@@ -1084,100 +1179,526 @@ func (r ReconcileMigPlan) validatePodHealth(plan *migapi.MigPlan) error {
 }
 
 func (r ReconcileMigPlan) validateHooks(plan *migapi.MigPlan) error {
-	var preBackupCount, postBackupCount, preRestoreCount, postRestoreCount int = 0, 0, 0, 0
-
+	phases := map[string][]migapi.MigPlanHook{}
 	for _, hook := range plan.Spec.Hooks {
-		migHook := migapi.MigHook{}
-		err := r.Get(
-			context.TODO(),
-			types.NamespacedName{
-				Name:      hook.Reference.Name,
-				Namespace: hook.Reference.Namespace,
-			},
-			&migHook)
-
-		// NotFound
-		if k8serror.IsNotFound(err) {
-			plan.Status.SetCondition(migapi.Condition{
-				Type:     InvalidHookRef,
-				Status:   True,
-				Reason:   NotFound,
-				Category: Critical,
-				Message:  "One or more referenced hooks do not exist.",
-			})
-			return nil
-		} else if err != nil {
+		valid, err := r.validateHook(plan, hook)
+		if err != nil {
 			return liberr.Wrap(err)
 		}
-
-		// InvalidHookSA
-		if errs := validation.IsDNS1123Subdomain(hook.ServiceAccount); len(errs) != 0 {
-			plan.Status.SetCondition(migapi.Condition{
-				Type:     InvalidHookSAName,
-				Status:   True,
-				Reason:   NotSet,
-				Category: Critical,
-				Message: "The serviceAccount specified is invalid, DNS-1123 subdomain regex used for validation" +
-					" is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*'",
-			})
+		if !valid {
+			return nil
 		}
 
-		// InvalidHookNS
-		if errs := validation.IsDNS1123Label(hook.ExecutionNamespace); len(errs) != 0 {
+		switch hook.Phase {
+		case migapi.PreRestoreHookPhase,
+			migapi.PostRestoreHookPhase,
+			migapi.PreBackupHookPhase,
+			migapi.PostBackupHookPhase,
+			migapi.PreQuiesceHookPhase,
+			migapi.PostQuiesceHookPhase,
+			migapi.PreVolumeCopyHookPhase,
+			migapi.PostVolumeCopyHookPhase,
+			migapi.PostVerifyHookPhase:
+			phases[hook.Phase] = append(phases[hook.Phase], hook)
+		default:
 			plan.Status.SetCondition(migapi.Condition{
-				Type:     InvalidHookNSName,
+				Type:     HookPhaseUnknown,
 				Status:   True,
-				Reason:   NotSet,
 				Category: Critical,
-				Message: "The executionNamespace specified is invalid, DNS-1123 label regex used for validation" +
-					" is '[a-z0-9]([-a-z0-9]*[a-z0-9])?'.",
+				Message:  "One or more referenced hooks are in an unknown phase.",
 			})
+			return nil
 		}
+	}
 
-		// NotReady
-		if !migHook.Status.IsReady() {
+	invalidPolicy := []string{}
+	invalidDependency := []string{}
+	for _, hooks := range phases {
+		if duplicateHooks(hooks) {
 			plan.Status.SetCondition(migapi.Condition{
-				Type:     HookNotReady,
+				Type:     HookPhaseDuplicate,
 				Status:   True,
 				Category: Critical,
-				Message:  "One or more referenced hooks are not ready.",
+				Message:  "The hook names and the referenced MigHooks must be unique per phase.",
 			})
 			return nil
 		}
+		for _, hook := range hooks {
+			if !validHookPolicy(hook) {
+				invalidPolicy = append(invalidPolicy, hook.GetName())
+			}
+		}
+		invalidDependency = append(invalidDependency, invalidHookDependencies(hooks)...)
+	}
+	if len(invalidPolicy) > 0 {
+		sort.Strings(invalidPolicy)
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookPolicy,
+			Status:   True,
+			Category: Critical,
+			Message: "The hooks [] are invalid, the failurePolicy must be Fail, Ignore or Rollback" +
+				" and the retries, retryBackoff and timeout must not be negative.",
+			Items: invalidPolicy,
+		})
+	}
+	if len(invalidDependency) > 0 {
+		sort.Strings(invalidDependency)
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookDependency,
+			Status:   True,
+			Category: Critical,
+			Message: "The hooks [] have invalid dependencies, a hook may only depend on hooks in" +
+				" the same phase with the same or a lower order and dependencies must not be circular.",
+			Items: invalidDependency,
+		})
+	}
 
-		switch hook.Phase {
-		case migapi.PreRestoreHookPhase:
-			preRestoreCount++
-		case migapi.PostRestoreHookPhase:
-			postRestoreCount++
-		case migapi.PreBackupHookPhase:
-			preBackupCount++
-		case migapi.PostBackupHookPhase:
-			postBackupCount++
-		default:
-			plan.Status.SetCondition(migapi.Condition{
-				Type:     HookPhaseUnknown,
-				Status:   True,
-				Category: Critical,
-				Message:  "One or more referenced hooks are in an unknown phase.",
-			})
+	return nil
+}
+
+// Get whether a hook name or a referenced MigHook is found more than once.
+// The hook jobs are correlated with the referenced MigHook and phase.
+func duplicateHooks(hooks []migapi.MigPlanHook) bool {
+	names := map[string]bool{}
+	refs := map[string]bool{}
+	for _, hook := range hooks {
+		ref := path.Join(hook.Reference.Namespace, hook.Reference.Name)
+		if names[hook.GetName()] || refs[ref] {
+			return true
+		}
+		names[hook.GetName()] = true
+		refs[ref] = true
+	}
+	return false
+}
+
+// Get whether the hook failure policy, retries and durations are valid.
+func validHookPolicy(hook migapi.MigPlanHook) bool {
+	switch hook.GetFailurePolicy() {
+	case migapi.HookFailurePolicyFail,
+		migapi.HookFailurePolicyIgnore,
+		migapi.HookFailurePolicyRollback:
+	default:
+		return false
+	}
+	if hook.Retries < 0 {
+		return false
+	}
+	if hook.RetryBackoff != nil && hook.RetryBackoff.Duration < 0 {
+		return false
+	}
+	if hook.Timeout != nil && hook.Timeout.Duration < time.Second {
+		return false
+	}
+	return true
+}
+
+// Get the hooks of a phase with invalid dependencies.
+// A hook may only depend on existing hooks with the same or a lower order
+// and dependencies must not be circular.
+func invalidHookDependencies(hooks []migapi.MigPlanHook) []string {
+	byName := map[string]*migapi.MigPlanHook{}
+	for i := range hooks {
+		byName[hooks[i].GetName()] = &hooks[i]
+	}
+	invalid := []string{}
+	for i := range hooks {
+		hook := &hooks[i]
+		for _, name := range hook.DependsOn {
+			dependency, found := byName[name]
+			if !found || dependency.Order > hook.Order || dependsOn(byName, dependency, hook.GetName(), map[string]bool{}) {
+				invalid = append(invalid, hook.GetName())
+				break
+			}
+		}
+	}
+	return invalid
+}
+
+// Get whether a hook depends (directly or not) on the named hook.
+func dependsOn(byName map[string]*migapi.MigPlanHook, hook *migapi.MigPlanHook, name string, visited map[string]bool) bool {
+	if visited[hook.GetName()] {
+		return false
+	}
+	visited[hook.GetName()] = true
+	for _, dependency := range hook.DependsOn {
+		if dependency == name {
+			return true
+		}
+		if next, found := byName[dependency]; found && dependsOn(byName, next, name, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate the hook reference, service account and execution namespace.
+// Returns `false` when the hook is not found or not ready.
+func (r ReconcileMigPlan) validateHook(plan *migapi.MigPlan, hook migapi.MigPlanHook) (bool, error) {
+	if hook.Reference == nil {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookRef,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "One or more hooks are missing the MigHook reference.",
+		})
+		return false, nil
+	}
+
+	migHook := migapi.MigHook{}
+	err := r.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Name:      hook.Reference.Name,
+			Namespace: hook.Reference.Namespace,
+		},
+		&migHook)
+
+	// NotFound
+	if k8serror.IsNotFound(err) {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookRef,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Critical,
+			Message:  "One or more referenced hooks do not exist.",
+		})
+		return false, nil
+	} else if err != nil {
+		return false, liberr.Wrap(err)
+	}
+
+	// InvalidHookSA
+	if errs := validation.IsDNS1123Subdomain(hook.ServiceAccount); len(errs) != 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookSAName,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message: "The serviceAccount specified is invalid, DNS-1123 subdomain regex used for validation" +
+				" is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*'",
+		})
+	}
+
+	// InvalidHookNS
+	if errs := validation.IsDNS1123Label(hook.ExecutionNamespace); len(errs) != 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidHookNSName,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message: "The executionNamespace specified is invalid, DNS-1123 label regex used for validation" +
+				" is '[a-z0-9]([-a-z0-9]*[a-z0-9])?'.",
+		})
+	}
+
+	// NotReady
+	if !migHook.Status.IsReady() {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     HookNotReady,
+			Status:   True,
+			Category: Critical,
+			Message:  "One or more referenced hooks are not ready.",
+		})
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Validate the quiesce groups.
+func (r ReconcileMigPlan) validateQuiesceGroups(plan *migapi.MigPlan) error {
+	names := map[string]bool{}
+	invalid := []string{}
+	for i := range plan.Spec.QuiesceGroups {
+		group := &plan.Spec.QuiesceGroups[i]
+		if errs := validation.IsDNS1123Label(group.Name); len(errs) != 0 || names[group.Name] {
+			invalid = append(invalid, group.Name)
+			continue
+		}
+		names[group.Name] = true
+		if group.Selector == nil {
+			invalid = append(invalid, group.Name)
+			continue
+		}
+		_, err := metav1.LabelSelectorAsSelector(group.Selector)
+		if err != nil {
+			invalid = append(invalid, group.Name)
+			continue
+		}
+		hook := group.GetDrainHook()
+		if hook == nil {
+			continue
+		}
+		valid, err := r.validateHook(plan, *hook)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+		if !valid {
 			return nil
 		}
 	}
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidQuiesceGroup,
+			Status:   True,
+			Reason:   InvalidSelector,
+			Category: Critical,
+			Message: "The quiesce groups [] are invalid, a group must have a unique DNS-1123 label name" +
+				" and a valid label selector.",
+			Items: invalid,
+		})
+	}
+
+	return nil
+}
 
-	if preRestoreCount > 1 ||
-		postRestoreCount > 1 ||
-		preBackupCount > 1 ||
-		postBackupCount > 1 {
+// Validate the verification checks.
+func (r ReconcileMigPlan) validateVerificationChecks(plan *migapi.MigPlan) {
+	if plan.Spec.Verification == nil {
+		return
+	}
+	names := map[string]bool{}
+	invalid := []string{}
+	namespaces := plan.GetDestinationNamespaces()
+	for i := range plan.Spec.Verification.Checks {
+		check := &plan.Spec.Verification.Checks[i]
+		if check.Name == "" || names[check.Name] || !validVerificationCheck(check, namespaces) {
+			invalid = append(invalid, check.Name)
+			continue
+		}
+		names[check.Name] = true
+	}
+	if len(invalid) > 0 {
 		plan.Status.SetCondition(migapi.Condition{
-			Type:     HookPhaseDuplicate,
+			Type:     InvalidVerificationCheck,
 			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message: "The verification checks [] are invalid, a check must have a unique name" +
+				" and define exactly one complete check in the destination namespaces of the plan.",
+			Items: invalid,
+		})
+	}
+}
+
+// Get whether exactly one complete check is defined.
+// The services, routes and pods checked must be in the destination namespaces.
+func validVerificationCheck(check *migapi.VerificationCheck, namespaces []string) bool {
+	defined := 0
+	valid := true
+	if check.HTTPGet != nil {
+		defined++
+		get := check.HTTPGet
+		valid = valid &&
+			get.Namespace != "" &&
+			(get.Service == "") != (get.Route == "")
+	}
+	if check.Exec != nil {
+		defined++
+		valid = valid &&
+			check.Exec.Namespace != "" &&
+			check.Exec.Selector != nil &&
+			len(check.Exec.Command) > 0
+		if check.Exec.Selector != nil {
+			_, err := metav1.LabelSelectorAsSelector(check.Exec.Selector)
+			valid = valid && err == nil
+		}
+	}
+	if check.ObjectCount != nil {
+		defined++
+		valid = valid &&
+			check.ObjectCount.Version != "" &&
+			check.ObjectCount.Kind != ""
+	}
+	for _, namespace := range check.Namespaces() {
+		found := false
+		for _, ns := range namespaces {
+			if ns == namespace {
+				found = true
+				break
+			}
+		}
+		valid = valid && found
+	}
+	return valid && defined == 1
+}
+
+// Validate the resource filters.
+func (r ReconcileMigPlan) validateResourceFilters(plan *migapi.MigPlan) {
+	_, err := resourcefilter.New(plan.Spec.ResourceFilters)
+	if err != nil {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidResourceFilter,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The resource filters are invalid, a rule must specify a kind and the label selector must be valid.",
+		})
+	}
+}
+
+// Validate the rsync options of the transfer spec.
+func (r ReconcileMigPlan) validateTransferSpec(plan *migapi.MigPlan) {
+	invalid := plan.Spec.Transfer.InvalidRsyncOptions()
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferSpec,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The rsync options [] of the transfer spec are not valid.",
+			Items:    invalid,
+		})
+	}
+	invalid = plan.Spec.Transfer.InvalidTransport(plan.GetSourceNamespaces())
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransport,
+			Status:   True,
+			Reason:   NotSet,
 			Category: Critical,
-			Message:  "Only one hook may be specified per phase.",
+			Message:  "The transport of the transfer spec is not valid: [].",
+			Items:    invalid,
 		})
+	}
+	invalid = plan.Spec.Transfer.InvalidEngine()
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferEngine,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The transfer engine of the transfer spec is not valid: [].",
+			Items:    invalid,
+		})
+	}
+	invalid = plan.Spec.Transfer.InvalidConcurrency()
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferConcurrency,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The concurrency of the transfer spec is not valid: [].",
+			Items:    invalid,
+		})
+	}
+	invalid = plan.Spec.Transfer.InvalidBandwidth()
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidBandwidthSchedule,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The bandwidth windows of the transfer spec are not valid: [].",
+			Items:    invalid,
+		})
+	}
+	invalid = plan.Spec.Transfer.InvalidRetry()
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTransferRetry,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The retry policy of the transfer spec is not valid: [].",
+			Items:    invalid,
+		})
+	}
+	invalid = invalidPVCTransforms(plan)
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidPVCTransforms,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The PVC transform rules are not valid: [].",
+			Items:    invalid,
+		})
+	}
+}
+
+// Validate the volumes copied directly within the cluster.
+// The workloads are not repointed to the copies, so the volumes must be
+// copied to a different namespace, by the opt-in plaintext transfer.
+func (r ReconcileMigPlan) validateIntraClusterVolumes(plan *migapi.MigPlan) {
+	volumes := intraClusterVolumes(plan)
+	if len(volumes) == 0 {
+		return
+	}
+	invalid := unmappedVolumes(plan, volumes)
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     IntraClusterVolumesNotMapped,
+			Status:   True,
+			Reason:   NotDistinct,
+			Category: Critical,
+			Message:  "The persistent volumes [] cannot be copied within their namespace on the same cluster.",
+			Items:    invalid,
+		})
+	}
+	if !Settings.DvmOpts.LocalPlaintext {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     LocalTransferNotEnabled,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message: fmt.Sprintf(
+				"Persistent volumes within a cluster are copied by a plaintext rsync daemon, which must be enabled by %s.",
+				settings.LocalPlaintext),
+		})
+	}
+}
+
+// Get the volumes copied directly within the cluster.
+func intraClusterVolumes(plan *migapi.MigPlan) []migapi.PV {
+	if plan.Spec.IndirectVolumeMigration ||
+		!migref.RefSet(plan.Spec.SrcMigClusterRef) ||
+		!migref.RefEquals(plan.Spec.SrcMigClusterRef, plan.Spec.DestMigClusterRef) {
 		return nil
 	}
+	volumes := []migapi.PV{}
+	for _, pv := range plan.Spec.PersistentVolumes.List {
+		if pv.Selection.Action == migapi.PvCopyAction &&
+			pv.Selection.CopyMethod == migapi.PvFilesystemCopyMethod {
+			volumes = append(volumes, pv)
+		}
+	}
+	return volumes
+}
+
+// Get the volumes not mapped to a different namespace.
+func unmappedVolumes(plan *migapi.MigPlan, volumes []migapi.PV) []string {
+	nsMapping := plan.GetNamespaceMapping()
+	unmapped := []string{}
+	for _, pv := range volumes {
+		if dest, found := nsMapping[pv.PVC.Namespace]; !found || dest == pv.PVC.Namespace {
+			unmapped = append(unmapped, path.Join(pv.PVC.Namespace, pv.PVC.Name))
+		}
+	}
+	return unmapped
+}
 
+// Validate the TLS settings and the referenced CA secret.
+func (r ReconcileMigPlan) validateTLS(plan *migapi.MigPlan) error {
+	invalid := plan.Spec.Transfer.InvalidTLS()
+	invalidSecret, err := plan.Spec.Transfer.InvalidCASecret(r)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	invalid = append(invalid, invalidSecret...)
+	if len(invalid) > 0 {
+		plan.Status.SetCondition(migapi.Condition{
+			Type:     InvalidTLS,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The TLS settings of the transfer spec are not valid: [].",
+			Items:    invalid,
+		})
+	}
 	return nil
 }
 
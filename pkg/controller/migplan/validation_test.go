@@ -0,0 +1,284 @@
+package migplan
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_validVerificationCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		check migapi.VerificationCheck
+		want  bool
+	}{
+		{
+			name: "http service",
+			check: migapi.VerificationCheck{
+				HTTPGet: &migapi.HTTPGetCheck{Namespace: "ns", Service: "web"},
+			},
+			want: true,
+		},
+		{
+			name: "http service and route",
+			check: migapi.VerificationCheck{
+				HTTPGet: &migapi.HTTPGetCheck{Namespace: "ns", Service: "web", Route: "web"},
+			},
+			want: false,
+		},
+		{
+			name: "http service not migrated",
+			check: migapi.VerificationCheck{
+				HTTPGet: &migapi.HTTPGetCheck{Namespace: "kube-system", Service: "web"},
+			},
+			want: false,
+		},
+		{
+			name: "exec",
+			check: migapi.VerificationCheck{
+				Exec: &migapi.ExecCheck{
+					Namespace: "ns",
+					Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					Command:   []string{"pg_isready"},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "exec not migrated",
+			check: migapi.VerificationCheck{
+				Exec: &migapi.ExecCheck{
+					Namespace: "openshift-config",
+					Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					Command:   []string{"pg_isready"},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "exec without command",
+			check: migapi.VerificationCheck{
+				Exec: &migapi.ExecCheck{
+					Namespace: "ns",
+					Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "object count",
+			check: migapi.VerificationCheck{
+				ObjectCount: &migapi.ObjectCountCheck{Version: "v1", Kind: "ConfigMap"},
+			},
+			want: true,
+		},
+		{
+			name:  "none",
+			check: migapi.VerificationCheck{},
+			want:  false,
+		},
+		{
+			name: "several",
+			check: migapi.VerificationCheck{
+				HTTPGet:     &migapi.HTTPGetCheck{Namespace: "ns", Service: "web"},
+				ObjectCount: &migapi.ObjectCountCheck{Version: "v1", Kind: "ConfigMap"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validVerificationCheck(&tt.check, []string{"ns"}); got != tt.want {
+				t.Errorf("validVerificationCheck() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_invalidHookDependencies(t *testing.T) {
+	hook := func(name string, order int, dependsOn ...string) migapi.MigPlanHook {
+		return migapi.MigPlanHook{
+			Reference: &kapi.ObjectReference{Namespace: "openshift-migration", Name: name},
+			Phase:     migapi.PreBackupHookPhase,
+			Order:     order,
+			DependsOn: dependsOn,
+		}
+	}
+	tests := []struct {
+		name  string
+		hooks []migapi.MigPlanHook
+		want  []string
+	}{
+		{
+			name:  "no dependencies",
+			hooks: []migapi.MigPlanHook{hook("a", 0), hook("b", 1)},
+			want:  []string{},
+		},
+		{
+			name:  "valid dependencies",
+			hooks: []migapi.MigPlanHook{hook("a", 0), hook("b", 0, "a"), hook("c", 1, "a", "b")},
+			want:  []string{},
+		},
+		{
+			name:  "unknown dependency",
+			hooks: []migapi.MigPlanHook{hook("a", 0, "missing")},
+			want:  []string{"a"},
+		},
+		{
+			name:  "dependency with a higher order",
+			hooks: []migapi.MigPlanHook{hook("a", 0, "b"), hook("b", 1)},
+			want:  []string{"a"},
+		},
+		{
+			name:  "circular dependencies",
+			hooks: []migapi.MigPlanHook{hook("a", 0, "c"), hook("b", 0, "a"), hook("c", 0, "b"), hook("d", 0, "a")},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "self dependency",
+			hooks: []migapi.MigPlanHook{hook("a", 0, "a")},
+			want:  []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidHookDependencies(tt.hooks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invalidHookDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validHookPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		hook migapi.MigPlanHook
+		want bool
+	}{
+		{
+			name: "defaults",
+			hook: migapi.MigPlanHook{},
+			want: true,
+		},
+		{
+			name: "ignore with retries",
+			hook: migapi.MigPlanHook{
+				FailurePolicy: migapi.HookFailurePolicyIgnore,
+				Retries:       3,
+				RetryBackoff:  &metav1.Duration{Duration: time.Minute},
+				Timeout:       &metav1.Duration{Duration: time.Hour},
+			},
+			want: true,
+		},
+		{
+			name: "unknown policy",
+			hook: migapi.MigPlanHook{FailurePolicy: "Retry"},
+			want: false,
+		},
+		{
+			name: "negative retries",
+			hook: migapi.MigPlanHook{Retries: -1},
+			want: false,
+		},
+		{
+			name: "zero timeout",
+			hook: migapi.MigPlanHook{Timeout: &metav1.Duration{}},
+			want: false,
+		},
+		{
+			name: "sub-second timeout",
+			hook: migapi.MigPlanHook{Timeout: &metav1.Duration{Duration: time.Millisecond * 500}},
+			want: false,
+		},
+		{
+			name: "one second timeout",
+			hook: migapi.MigPlanHook{Timeout: &metav1.Duration{Duration: time.Second}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validHookPolicy(tt.hook); got != tt.want {
+				t.Errorf("validHookPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_duplicateHooks(t *testing.T) {
+	ref := func(name string) *kapi.ObjectReference {
+		return &kapi.ObjectReference{Namespace: "openshift-migration", Name: name}
+	}
+	tests := []struct {
+		name  string
+		hooks []migapi.MigPlanHook
+		want  bool
+	}{
+		{
+			name:  "distinct",
+			hooks: []migapi.MigPlanHook{{Reference: ref("a")}, {Reference: ref("b")}},
+			want:  false,
+		},
+		{
+			name:  "same MigHook",
+			hooks: []migapi.MigPlanHook{{Reference: ref("a"), Name: "first"}, {Reference: ref("a"), Name: "second"}},
+			want:  true,
+		},
+		{
+			name:  "same name",
+			hooks: []migapi.MigPlanHook{{Reference: ref("a"), Name: "hook"}, {Reference: ref("b"), Name: "hook"}},
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duplicateHooks(tt.hooks); got != tt.want {
+				t.Errorf("duplicateHooks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unmappedVolumes(t *testing.T) {
+	host := &kapi.ObjectReference{Namespace: "openshift-migration", Name: "host"}
+	pv := func(ns, name string, action string) migapi.PV {
+		return migapi.PV{
+			PVC: migapi.PVC{Namespace: ns, Name: name},
+			Selection: migapi.Selection{
+				Action:     action,
+				CopyMethod: migapi.PvFilesystemCopyMethod,
+			},
+		}
+	}
+	plan := &migapi.MigPlan{}
+	plan.Spec.SrcMigClusterRef = host
+	plan.Spec.DestMigClusterRef = host
+	plan.Spec.Namespaces = []string{"ns1", "ns2:ns3", "ns4:ns4"}
+	plan.Spec.PersistentVolumes.List = []migapi.PV{
+		pv("ns1", "data", migapi.PvCopyAction),
+		pv("ns2", "data", migapi.PvCopyAction),
+		pv("ns4", "data", migapi.PvCopyAction),
+		pv("ns1", "skipped", migapi.PvSkipAction),
+	}
+	volumes := intraClusterVolumes(plan)
+	if len(volumes) != 3 {
+		t.Fatalf("intraClusterVolumes() = %v", volumes)
+	}
+	want := []string{"ns1/data", "ns4/data"}
+	if got := unmappedVolumes(plan, volumes); !reflect.DeepEqual(got, want) {
+		t.Errorf("unmappedVolumes() = %v, want %v", got, want)
+	}
+	plan.Spec.IndirectVolumeMigration = true
+	if volumes := intraClusterVolumes(plan); len(volumes) != 0 {
+		t.Errorf("intraClusterVolumes() indirect = %v", volumes)
+	}
+	plan.Spec.IndirectVolumeMigration = false
+	plan.Spec.DestMigClusterRef = &kapi.ObjectReference{Namespace: "openshift-migration", Name: "remote"}
+	if volumes := intraClusterVolumes(plan); len(volumes) != 0 {
+		t.Errorf("intraClusterVolumes() remote = %v", volumes)
+	}
+}
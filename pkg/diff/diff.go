@@ -0,0 +1,305 @@
+package diff
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Difference types.
+const (
+	// Found on the source cluster only.
+	Missing = "Missing"
+	// Found on the destination cluster only.
+	Extra = "Extra"
+	// Found on both clusters with a different content.
+	Changed = "Changed"
+)
+
+// Resources not compared.
+// The objects are (re)created by the clusters and are expected to differ.
+var SkippedResources = map[string]bool{
+	"pods":           true,
+	"events":         true,
+	"endpoints":      true,
+	"endpointslices": true,
+}
+
+// ConfigMaps created in every namespace by the clusters.
+var SkippedConfigMaps = map[string]bool{
+	"kube-root-ca.crt":         true,
+	"openshift-service-ca.crt": true,
+}
+
+// Label and annotation prefixes ignored when comparing.
+// Set by the migration, velero or the clusters.
+var IgnoredPrefixes = []string{
+	"migration.openshift.io/",
+	"velero.io/",
+	"backup.velero.io/",
+	"deployment.kubernetes.io/revision",
+	"openshift.io/host.generated",
+}
+
+// Difference between a source and a destination object.
+// The namespace is the destination namespace.
+// The fields are the (dot separated) paths of the changed fields.
+type Difference struct {
+	Group     string   `json:"group,omitempty"`
+	Version   string   `json:"version"`
+	Resource  string   `json:"resource"`
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Fields    []string `json:"fields,omitempty"`
+}
+
+// Report the differences between the source and destination objects.
+type Report struct {
+	// The number of source objects compared.
+	Compared int `json:"compared"`
+	// The differences found.
+	Differences []Difference `json:"differences,omitempty"`
+}
+
+// Add the differences found comparing objects.
+func (r *Report) Add(compared int, differences []Difference) {
+	r.Compared += compared
+	r.Differences = append(r.Differences, differences...)
+}
+
+// Count the differences of a type.
+func (r *Report) Count(kind string) int {
+	count := 0
+	for _, d := range r.Differences {
+		if d.Type == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Options used to normalize the objects.
+type Options struct {
+	// Source namespaces mapped to destination namespaces.
+	NamespaceMapping map[string]string
+}
+
+// Compare the source and destination objects of a resource.
+// The objects are matched by name. Cluster specific fields are ignored.
+// Returns: the number of source objects compared and the differences.
+func Compare(
+	gvr schema.GroupVersionResource,
+	namespace string,
+	src, dest []unstructured.Unstructured,
+	options *Options) (int, []Difference) {
+	if SkippedResources[gvr.Resource] {
+		return 0, nil
+	}
+	destObjects := map[string]*unstructured.Unstructured{}
+	for i := range dest {
+		if Skipped(gvr, &dest[i]) {
+			continue
+		}
+		destObjects[dest[i].GetName()] = &dest[i]
+	}
+	differences := []Difference{}
+	difference := func(name, kind string, fields []string) Difference {
+		return Difference{
+			Group:     gvr.Group,
+			Version:   gvr.Version,
+			Resource:  gvr.Resource,
+			Namespace: namespace,
+			Name:      name,
+			Type:      kind,
+			Fields:    fields,
+		}
+	}
+	compared := 0
+	for i := range src {
+		srcObject := &src[i]
+		if Skipped(gvr, srcObject) {
+			continue
+		}
+		compared++
+		name := srcObject.GetName()
+		destObject, found := destObjects[name]
+		if !found {
+			differences = append(differences, difference(name, Missing, nil))
+			continue
+		}
+		delete(destObjects, name)
+		fields := ChangedFields(
+			Normalize(srcObject, options),
+			Normalize(destObject, &Options{}),
+			"")
+		if len(fields) > 0 {
+			differences = append(differences, difference(name, Changed, fields))
+		}
+	}
+	extra := []string{}
+	for name := range destObjects {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		differences = append(differences, difference(name, Extra, nil))
+	}
+
+	return compared, differences
+}
+
+// Get whether the object is not compared.
+// Objects owned by other objects are (re)created by controllers.
+func Skipped(gvr schema.GroupVersionResource, object *unstructured.Unstructured) bool {
+	if len(object.GetOwnerReferences()) > 0 {
+		return true
+	}
+	switch gvr.Resource {
+	case "secrets":
+		// Service account tokens and pull secrets generated by the clusters.
+		if _, found := object.GetAnnotations()["kubernetes.io/service-account.name"]; found {
+			return true
+		}
+	case "configmaps":
+		return SkippedConfigMaps[object.GetName()]
+	}
+	return false
+}
+
+// Normalize the object content.
+// The metadata other than the name, labels and annotations, the status and
+// cluster specific fields are removed. Source namespaces are mapped to the
+// destination namespaces and the image registries are ignored since images
+// may be rewritten to the destination registry.
+func Normalize(object *unstructured.Unstructured, options *Options) map[string]interface{} {
+	content := object.DeepCopy().Object
+	delete(content, "status")
+	metadata := map[string]interface{}{
+		"name": object.GetName(),
+	}
+	if labels := filterIgnored(object.GetLabels()); len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if annotations := filterIgnored(object.GetAnnotations()); len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	content["metadata"] = metadata
+
+	switch object.GetKind() {
+	case "Service":
+		unstructured.RemoveNestedField(content, "spec", "clusterIP")
+		unstructured.RemoveNestedField(content, "spec", "clusterIPs")
+		unstructured.RemoveNestedField(content, "spec", "healthCheckNodePort")
+		ports, found, _ := unstructured.NestedSlice(content, "spec", "ports")
+		if found {
+			for _, port := range ports {
+				if port, cast := port.(map[string]interface{}); cast {
+					delete(port, "nodePort")
+				}
+			}
+			_ = unstructured.SetNestedSlice(content, ports, "spec", "ports")
+		}
+	case "ServiceAccount":
+		delete(content, "secrets")
+		delete(content, "imagePullSecrets")
+	case "Route":
+		if object.GetAnnotations()["openshift.io/host.generated"] == "true" {
+			unstructured.RemoveNestedField(content, "spec", "host")
+		}
+	}
+
+	return mapValues(content, "", options.NamespaceMapping).(map[string]interface{})
+}
+
+// Map the namespaces and images found in a value.
+func mapValues(value interface{}, key string, namespaces map[string]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = mapValues(item, k, namespaces)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = mapValues(item, key, namespaces)
+		}
+	case string:
+		if key == "image" {
+			return NormalizeImage(v, namespaces)
+		}
+		if mapped, found := namespaces[v]; found && (key == "namespace" || strings.HasSuffix(key, "Namespace")) {
+			return mapped
+		}
+	}
+	return value
+}
+
+// Normalize an image reference.
+// The registry is removed and the (namespace) repository path is mapped.
+func NormalizeImage(image string, namespaces map[string]string) string {
+	parts := strings.Split(image, "/")
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		parts = parts[1:]
+	}
+	if len(parts) > 1 {
+		if mapped, found := namespaces[parts[0]]; found {
+			parts[0] = mapped
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Remove the ignored labels or annotations.
+func filterIgnored(in map[string]string) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range in {
+		ignored := false
+		for _, prefix := range IgnoredPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Get the paths of the fields that differ.
+// Lists are compared as a whole.
+func ChangedFields(a, b map[string]interface{}, prefix string) []string {
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	fields := []string{}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		aValue, aFound := a[k]
+		bValue, bFound := b[k]
+		if aFound && bFound {
+			aMap, aIsMap := aValue.(map[string]interface{})
+			bMap, bIsMap := bValue.(map[string]interface{})
+			if aIsMap && bIsMap {
+				fields = append(fields, ChangedFields(aMap, bMap, path)...)
+				continue
+			}
+		}
+		if !reflect.DeepEqual(aValue, bValue) {
+			fields = append(fields, path)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,197 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func object(kind, namespace, name string, content map[string]interface{}) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: content}
+	if u.Object == nil {
+		u.Object = map[string]interface{}{}
+	}
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func TestCompare(t *testing.T) {
+	options := &Options{
+		NamespaceMapping: map[string]string{"src": "dest"},
+	}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	tests := []struct {
+		name     string
+		gvr      schema.GroupVersionResource
+		src      []unstructured.Unstructured
+		dest     []unstructured.Unstructured
+		compared int
+		want     []Difference
+	}{
+		{
+			name: "equal",
+			gvr:  configMaps,
+			src: []unstructured.Unstructured{
+				object("ConfigMap", "src", "settings", map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"uid":             "1",
+						"resourceVersion": "10",
+						"annotations": map[string]interface{}{
+							"migration.openshift.io/migrated-by-migplan": "a",
+						},
+					},
+					"data": map[string]interface{}{"key": "value"},
+				}),
+			},
+			dest: []unstructured.Unstructured{
+				object("ConfigMap", "dest", "settings", map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"uid":             "2",
+						"resourceVersion": "20",
+					},
+					"data": map[string]interface{}{"key": "value"},
+				}),
+			},
+			compared: 1,
+			want:     []Difference{},
+		},
+		{
+			name: "missing extra changed",
+			gvr:  configMaps,
+			src: []unstructured.Unstructured{
+				object("ConfigMap", "src", "a", nil),
+				object("ConfigMap", "src", "b", map[string]interface{}{
+					"data": map[string]interface{}{"key": "value", "other": "x"},
+				}),
+				object("ConfigMap", "src", "kube-root-ca.crt", nil),
+			},
+			dest: []unstructured.Unstructured{
+				object("ConfigMap", "dest", "b", map[string]interface{}{
+					"data": map[string]interface{}{"key": "changed", "other": "x"},
+				}),
+				object("ConfigMap", "dest", "c", nil),
+			},
+			compared: 2,
+			want: []Difference{
+				{Version: "v1", Resource: "configmaps", Namespace: "dest", Name: "a", Type: Missing},
+				{Version: "v1", Resource: "configmaps", Namespace: "dest", Name: "b", Type: Changed, Fields: []string{"data.key"}},
+				{Version: "v1", Resource: "configmaps", Namespace: "dest", Name: "c", Type: Extra},
+			},
+		},
+		{
+			name: "image rewritten and status ignored",
+			gvr:  deployments,
+			src: []unstructured.Unstructured{
+				object("Deployment", "src", "web", map[string]interface{}{
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name":  "web",
+										"image": "registry.src.example.com:5000/src/web:latest",
+									},
+								},
+							},
+						},
+					},
+					"status": map[string]interface{}{"replicas": int64(1)},
+				}),
+			},
+			dest: []unstructured.Unstructured{
+				object("Deployment", "dest", "web", map[string]interface{}{
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name":  "web",
+										"image": "image-registry.openshift-image-registry.svc:5000/dest/web:latest",
+									},
+								},
+							},
+						},
+					},
+				}),
+			},
+			compared: 1,
+			want:     []Difference{},
+		},
+		{
+			name: "owned objects skipped",
+			gvr:  configMaps,
+			src: []unstructured.Unstructured{
+				object("ConfigMap", "src", "owned", map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"ownerReferences": []interface{}{
+							map[string]interface{}{"kind": "Deployment", "name": "web", "uid": "1", "apiVersion": "apps/v1"},
+						},
+					},
+				}),
+			},
+			compared: 0,
+			want:     []Difference{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compared, got := Compare(tt.gvr, "dest", tt.src, tt.dest, options)
+			if compared != tt.compared {
+				t.Errorf("Compare() compared = %v, want %v", compared, tt.compared)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	namespaces := map[string]string{"src": "dest"}
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "nginx", want: "nginx"},
+		{image: "library/nginx:1.19", want: "library/nginx:1.19"},
+		{image: "docker.io/library/nginx:1.19", want: "library/nginx:1.19"},
+		{image: "localhost/src/web", want: "dest/web"},
+		{image: "172.30.1.1:5000/src/web@sha256:abc", want: "dest/web@sha256:abc"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeImage(tt.image, namespaces); got != tt.want {
+			t.Errorf("NormalizeImage(%s) = %v, want %v", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize_Service(t *testing.T) {
+	src := object("Service", "src", "web", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"clusterIP": "172.30.0.10",
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "nodePort": int64(30080)},
+			},
+		},
+	})
+	dest := object("Service", "dest", "web", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"clusterIP": "172.31.0.20",
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "nodePort": int64(31080)},
+			},
+		},
+	})
+	fields := ChangedFields(
+		Normalize(&src, &Options{}),
+		Normalize(&dest, &Options{}),
+		"")
+	if len(fields) > 0 {
+		t.Errorf("ChangedFields() = %v, want none", fields)
+	}
+}
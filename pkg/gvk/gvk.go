@@ -14,6 +14,7 @@ import (
 	mapset "github.com/deckarep/golang-set"
 
 	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	"github.com/konveyor/mig-controller/pkg/resourcefilter"
 	"github.com/konveyor/mig-controller/pkg/settings"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -91,10 +92,15 @@ func (r *Compare) Compare() (map[string][]schema.GroupVersionResource, error) {
 	for _, gvr := range incompatibleGVKs {
 		skip := false
 		for _, resource := range excludedResources {
-			if strings.EqualFold(gvr.Resource, resource) {
+			if strings.EqualFold(gvr.Resource, resource) ||
+				strings.EqualFold(gvr.GroupResource().String(), resource) {
 				skip = true
 			}
 		}
+		included := r.Plan.Status.IncludedResources
+		if len(included) > 0 && !resourcefilter.Contains(included, gvr.GroupResource()) {
+			skip = true
+		}
 		if !skip {
 			filteredGVKs = append(filteredGVKs, gvr)
 		}
@@ -191,6 +197,43 @@ func GetNamespacedGVRsForCluster(cluster *migapi.MigCluster, c client.Client) (d
 	return dynamic, GVRs, nil
 }
 
+// GetPreferredNamespacedGVRsForCluster collects the preferred namespace-scoped GVRs for the provided cluster compatible client.
+// Resources served by several groups (i.e. extensions and apps deployments) are only included once.
+func GetPreferredNamespacedGVRsForCluster(cluster *migapi.MigCluster, c client.Client) (dynamic.Interface, []schema.GroupVersionResource, error) {
+	compat, err := cluster.GetClient(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	dynamic, err := dynamic.NewForConfig(compat.RestConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	resourceList, err := collectPreferredResources(compat)
+	if err != nil {
+		return nil, nil, err
+	}
+	SortResources(resourceList)
+	cohabitatingResources := NewCohabitatingResources()
+	for _, res := range resourceList {
+		resources := []metav1.APIResource{}
+		for _, resource := range res.APIResources {
+			if cohabitator, found := cohabitatingResources[resource.Name]; found {
+				if cohabitator.Seen {
+					continue
+				}
+				cohabitator.Seen = true
+			}
+			resources = append(resources, resource)
+		}
+		res.APIResources = resources
+	}
+	GVRs, err := convertToGVRList(resourceList)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dynamic, GVRs, nil
+}
+
 func excludeSubresources(resources []metav1.APIResource) []metav1.APIResource {
 	filteredList := []metav1.APIResource{}
 	for _, res := range resources {
@@ -0,0 +1,182 @@
+package hooksource
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	"github.com/konveyor/mig-controller/pkg/settings"
+	kapi "k8s.io/api/core/v1"
+)
+
+// Git HTTP request timeout.
+const GitTimeout = time.Second * 30
+
+// A full commit SHA.
+var commitRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// Credentials used to fetch a source.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Get the credentials stored in a secret.
+// Returns nil when the `username` or `password` key is missing.
+func SecretCredentials(secret *kapi.Secret) *Credentials {
+	username, hasUsername := secret.Data[kapi.BasicAuthUsernameKey]
+	password, hasPassword := secret.Data[kapi.BasicAuthPasswordKey]
+	if !hasUsername || !hasPassword {
+		return nil
+	}
+	return &Credentials{
+		Username: string(username),
+		Password: string(password),
+	}
+}
+
+// Validate a Git URL.
+// Only HTTPS URLs are valid, unless plain HTTP is allowed by the settings.
+func ValidateGitURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	if u.Host == "" {
+		return liberr.Wrap(fmt.Errorf("url %s has no host", rawURL))
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !settings.Settings.Hook.SourceAllowHTTP {
+			return liberr.Wrap(fmt.Errorf("url %s not allowed, %s is not set", rawURL, settings.HookSourceAllowHTTP))
+		}
+	default:
+		return liberr.Wrap(fmt.Errorf("url %s must be HTTPS", rawURL))
+	}
+	return nil
+}
+
+// Resolve a Git branch, tag or commit to a commit SHA.
+// The refs are listed using the smart HTTP protocol (git ls-remote).
+// An empty ref is resolved to the remote HEAD.
+func ResolveGit(url, ref string, credentials *Credentials) (string, error) {
+	err := ValidateGitURL(url)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	if commitRegexp.MatchString(ref) {
+		return ref, nil
+	}
+	refs, err := listGitRefs(url, credentials)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	revision, found := matchGitRef(refs, ref)
+	if !found {
+		return "", liberr.Wrap(fmt.Errorf("ref %s not found in %s", ref, url))
+	}
+	return revision, nil
+}
+
+// List the refs advertised by a Git repository.
+// Returns: the commit SHAs keyed by ref name.
+func listGitRefs(url string, credentials *Credentials) (map[string]string, error) {
+	request, err := http.NewRequest(
+		http.MethodGet,
+		strings.TrimSuffix(url, "/")+"/info/refs?service=git-upload-pack",
+		nil)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	request.Header.Set("User-Agent", "git/2.0 (mig-controller)")
+	if credentials != nil {
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	client := http.Client{Timeout: GitTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, liberr.Wrap(fmt.Errorf("listing the refs of %s failed: %s", url, response.Status))
+	}
+	return parseGitRefs(response.Body)
+}
+
+// Parse the smart HTTP ref advertisement.
+// The content is a sequence of pkt-lines: a 4 digit hex length (including
+// the length itself) followed by the data. The `0000` flush packet
+// separates the service announcement from the refs.
+func parseGitRefs(reader io.Reader) (map[string]string, error) {
+	refs := map[string]string{}
+	buffered := bufio.NewReader(reader)
+	for {
+		prefix := make([]byte, 4)
+		_, err := io.ReadFull(buffered, prefix)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		length, err := strconv.ParseUint(string(prefix), 16, 16)
+		if err != nil {
+			return nil, liberr.Wrap(fmt.Errorf("invalid pkt-line length: %q", prefix))
+		}
+		if length == 0 {
+			continue
+		}
+		if length < 4 {
+			return nil, liberr.Wrap(fmt.Errorf("invalid pkt-line length: %q", prefix))
+		}
+		data := make([]byte, length-4)
+		_, err = io.ReadFull(buffered, data)
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		line := strings.TrimSuffix(string(data), "\n")
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		// The first ref is followed by the capabilities.
+		line = strings.SplitN(line, "\x00", 2)[0]
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 || !commitRegexp.MatchString(fields[0]) {
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+	return refs, nil
+}
+
+// Match a ref name against the advertised refs.
+// Branches are preferred over tags and annotated tags are peeled to the tagged commit.
+func matchGitRef(refs map[string]string, ref string) (string, bool) {
+	if ref == "" || ref == "HEAD" {
+		revision, found := refs["HEAD"]
+		return revision, found
+	}
+	candidates := []string{
+		ref,
+		"refs/heads/" + ref,
+		"refs/tags/" + ref,
+	}
+	for _, name := range candidates {
+		if revision, found := refs[name+"^{}"]; found {
+			return revision, true
+		}
+		if revision, found := refs[name]; found {
+			return revision, true
+		}
+	}
+	return "", false
+}
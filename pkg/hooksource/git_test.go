@@ -0,0 +1,189 @@
+package hooksource
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/mig-controller/pkg/settings"
+)
+
+const (
+	headCommit = "1111111111111111111111111111111111111111"
+	tagObject  = "2222222222222222222222222222222222222222"
+	tagCommit  = "3333333333333333333333333333333333333333"
+	mainCommit = "4444444444444444444444444444444444444444"
+)
+
+// Encode lines as pkt-lines.
+func pktLines(lines ...string) string {
+	b := strings.Builder{}
+	for _, line := range lines {
+		if line == "" {
+			b.WriteString("0000")
+			continue
+		}
+		fmt.Fprintf(&b, "%04x%s", len(line)+4, line)
+	}
+	return b.String()
+}
+
+var advertisement = pktLines(
+	"# service=git-upload-pack\n",
+	"",
+	headCommit+" HEAD\x00multi_ack side-band-64k symref=HEAD:refs/heads/main\n",
+	mainCommit+" refs/heads/main\n",
+	tagObject+" refs/tags/v1.0\n",
+	tagCommit+" refs/tags/v1.0^{}\n",
+	"")
+
+func Test_parseGitRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "advertisement",
+			content: advertisement,
+			want: map[string]string{
+				"HEAD":              headCommit,
+				"refs/heads/main":   mainCommit,
+				"refs/tags/v1.0":    tagObject,
+				"refs/tags/v1.0^{}": tagCommit,
+			},
+		},
+		{
+			name:    "empty",
+			content: "",
+			want:    map[string]string{},
+		},
+		{
+			name:    "invalid length",
+			content: "zzzz",
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			content: "00ffshort",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGitRefs(strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGitRefs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGitRefs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_matchGitRef(t *testing.T) {
+	refs := map[string]string{
+		"HEAD":              headCommit,
+		"refs/heads/main":   mainCommit,
+		"refs/tags/v1.0":    tagObject,
+		"refs/tags/v1.0^{}": tagCommit,
+	}
+	tests := []struct {
+		name      string
+		ref       string
+		want      string
+		wantFound bool
+	}{
+		{name: "default", ref: "", want: headCommit, wantFound: true},
+		{name: "head", ref: "HEAD", want: headCommit, wantFound: true},
+		{name: "branch", ref: "main", want: mainCommit, wantFound: true},
+		{name: "full branch", ref: "refs/heads/main", want: mainCommit, wantFound: true},
+		{name: "annotated tag", ref: "v1.0", want: tagCommit, wantFound: true},
+		{name: "unknown", ref: "v2.0", wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := matchGitRef(refs, tt.ref)
+			if found != tt.wantFound || got != tt.want {
+				t.Errorf("matchGitRef() = %v, %v, want %v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestResolveGit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/repo.git/info/refs" || r.URL.Query().Get("service") != "git-upload-pack" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(advertisement))
+	}))
+	defer server.Close()
+	settings.Settings.Hook.SourceAllowHTTP = true
+	defer func() {
+		settings.Settings.Hook.SourceAllowHTTP = false
+	}()
+	credentials := &Credentials{Username: "user", Password: "secret"}
+	tests := []struct {
+		name        string
+		url         string
+		ref         string
+		credentials *Credentials
+		want        string
+		wantErr     bool
+	}{
+		{name: "tag", url: server.URL + "/repo.git", ref: "v1.0", credentials: credentials, want: tagCommit},
+		{name: "commit", url: server.URL + "/missing.git", ref: mainCommit, want: mainCommit},
+		{name: "unknown ref", url: server.URL + "/repo.git", ref: "v2.0", credentials: credentials, wantErr: true},
+		{name: "unauthorized", url: server.URL + "/repo.git", ref: "main", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveGit(tt.url, tt.ref, tt.credentials)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveGit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveGit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGitURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		allowHTTP bool
+		wantErr   bool
+	}{
+		{name: "https", url: "https://github.com/konveyor/hooks.git"},
+		{name: "http", url: "http://github.com/konveyor/hooks.git", wantErr: true},
+		{name: "http allowed", url: "http://github.com/konveyor/hooks.git", allowHTTP: true},
+		{name: "ssh", url: "git@github.com:konveyor/hooks.git", wantErr: true},
+		{name: "file", url: "file:///tmp/hooks.git", wantErr: true},
+		{name: "no host", url: "https:///hooks.git", wantErr: true},
+	}
+	defer func() {
+		settings.Settings.Hook.SourceAllowHTTP = false
+	}()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings.Settings.Hook.SourceAllowHTTP = tt.allowHTTP
+			if err := ValidateGitURL(tt.url); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateGitURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package hooksource
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	liberr "github.com/konveyor/controller/pkg/error"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// The maximum size of a fetched file.
+// The content is stored in a ConfigMap.
+const MaxFileSize = 512 * 1024
+
+// Resolve an OCI artifact reference to the manifest digest.
+func ResolveOCI(reference string, credentials *Credentials) (string, error) {
+	source, err := openOCI(reference, credentials)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	defer source.Close()
+	content, _, err := source.GetManifest(context.TODO(), nil)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	digest, err := manifest.Digest(content)
+	if err != nil {
+		return "", liberr.Wrap(err)
+	}
+	return digest.String(), nil
+}
+
+// Fetch a file from an OCI artifact pinned to a manifest digest.
+// The file is either a layer titled with the path (as pushed by oras)
+// or a file at the path within a tar layer.
+func FetchOCI(reference, digest, filePath string, credentials *Credentials) ([]byte, error) {
+	source, err := openOCI(PinOCI(reference, digest), credentials)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	defer source.Close()
+	ctx := context.TODO()
+	content, mimeType, err := source.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	parsed, err := manifest.FromBlob(content, mimeType)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	layers := parsed.LayerInfos()
+	for _, layer := range layers {
+		title := layer.Annotations[imgspecv1.AnnotationTitle]
+		isTar := strings.Contains(layer.MediaType, "tar")
+		if !isTar && title != filePath && title != path.Base(filePath) {
+			continue
+		}
+		reader, _, err := source.GetBlob(ctx, layer.BlobInfo, none.NoCache)
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		var data []byte
+		if isTar {
+			data, err = extractFile(reader, strings.Contains(layer.MediaType, "gzip"), filePath)
+		} else {
+			data, err = readLimited(reader)
+		}
+		reader.Close()
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		if data != nil {
+			return data, nil
+		}
+	}
+
+	return nil, liberr.Wrap(fmt.Errorf("file %s not found in %s", filePath, reference))
+}
+
+// Pin an OCI reference to a digest.
+func PinOCI(reference, digest string) string {
+	if i := strings.Index(reference, "@"); i != -1 {
+		reference = reference[:i]
+	}
+	name := reference
+	if i := strings.LastIndex(reference, ":"); i > strings.LastIndex(reference, "/") {
+		name = reference[:i]
+	}
+	return name + "@" + digest
+}
+
+// Open an OCI artifact in a registry.
+func openOCI(reference string, credentials *Credentials) (types.ImageSource, error) {
+	ref, err := docker.ParseReference("//" + reference)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	sys := &types.SystemContext{}
+	if credentials != nil {
+		sys.DockerAuthConfig = &types.DockerAuthConfig{
+			Username: credentials.Username,
+			Password: credentials.Password,
+		}
+	}
+	source, err := ref.NewImageSource(context.TODO(), sys)
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	return source, nil
+}
+
+// Extract a file from a (gzip compressed) tar archive.
+// Returns nil when the file is not found.
+func extractFile(reader io.Reader, compressed bool, filePath string) ([]byte, error) {
+	if compressed {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	wanted := path.Clean(strings.TrimPrefix(filePath, "/"))
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if path.Clean(strings.TrimPrefix(header.Name, "/")) == wanted {
+			return readLimited(tarReader)
+		}
+	}
+}
+
+// Read the content up to the maximum file size.
+func readLimited(reader io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(reader, MaxFileSize+1))
+	if err != nil {
+		return nil, liberr.Wrap(err)
+	}
+	if len(data) > MaxFileSize {
+		return nil, liberr.Wrap(fmt.Errorf("file exceeds %d bytes", MaxFileSize))
+	}
+	return data, nil
+}
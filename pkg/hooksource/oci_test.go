@@ -0,0 +1,102 @@
+package hooksource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+// Build a (gzip compressed) tar archive.
+func archive(t *testing.T, compressed bool, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	var writer *tar.Writer
+	if compressed {
+		writer = tar.NewWriter(gzipWriter)
+	} else {
+		writer = tar.NewWriter(buf)
+	}
+	for name, content := range files {
+		err := writer.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = writer.Write([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if compressed {
+		if err := gzipWriter.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf
+}
+
+func Test_extractFile(t *testing.T) {
+	files := map[string]string{
+		"./hooks/playbook.yml": "- hosts: localhost\n",
+		"README.md":            "hooks",
+	}
+	tests := []struct {
+		name       string
+		compressed bool
+		files      map[string]string
+		path       string
+		want       string
+		wantErr    bool
+	}{
+		{name: "tar", files: files, path: "hooks/playbook.yml", want: "- hosts: localhost\n"},
+		{name: "tar.gz", compressed: true, files: files, path: "/hooks/playbook.yml", want: "- hosts: localhost\n"},
+		{name: "not found", files: files, path: "playbook.yml"},
+		{
+			name:    "too large",
+			files:   map[string]string{"large": strings.Repeat("x", MaxFileSize+1)},
+			path:    "large",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractFile(archive(t, tt.compressed, tt.files), tt.compressed, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("extractFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPinOCI(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name      string
+		reference string
+		want      string
+	}{
+		{name: "tag", reference: "quay.io/konveyor/hooks:v1", want: "quay.io/konveyor/hooks@" + digest},
+		{name: "no tag", reference: "quay.io/konveyor/hooks", want: "quay.io/konveyor/hooks@" + digest},
+		{name: "registry port", reference: "registry:5000/hooks:latest", want: "registry:5000/hooks@" + digest},
+		{name: "digest", reference: "registry:5000/hooks@sha256:abc", want: "registry:5000/hooks@" + digest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PinOCI(tt.reference, digest); got != tt.want {
+				t.Errorf("PinOCI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,17 +15,19 @@ import (
 // Command executed on a Pod.
 // RestCfg - The REST configuration for the cluster.
 // Pod - The pod on which to execute the command.
+// Container - The (optional) container in which to execute the command.
 // Args - The command (and args) to execute.
 // In - An (optional) command input stream.
 // Out - The command output stream set by `Run()`.
 // Err - the command error stream set by `Run()`.
 type PodCommand struct {
-	RestCfg *rest.Config
-	Pod     *v1.Pod
-	Args    []string
-	In      io.Reader
-	Out     bytes.Buffer
-	Err     bytes.Buffer
+	RestCfg   *rest.Config
+	Pod       *v1.Pod
+	Container string
+	Args      []string
+	In        io.Reader
+	Out       bytes.Buffer
+	Err       bytes.Buffer
 }
 
 // Run the command.
@@ -48,10 +50,11 @@ func (p *PodCommand) Run() error {
 		SubResource("exec")
 	post.VersionedParams(
 		&v1.PodExecOptions{
-			Command: p.Args,
-			Stdin:   true,
-			Stdout:  true,
-			Stderr:  true,
+			Container: p.Container,
+			Command:   p.Args,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
 		},
 		scheme.ParameterCodec)
 	executor, err := remotecommand.NewSPDYExecutor(
@@ -0,0 +1,96 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DialFunc dials a connection to an address.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// NewDialer returns a dial function tunneling connections through an HTTP
+// proxy using the CONNECT method. Credentials in the proxy URL are sent
+// using basic authentication.
+func NewDialer(proxyURL *url.URL) (DialFunc, error) {
+	if proxyURL.Scheme != "http" {
+		return nil, fmt.Errorf("proxy scheme %q not supported, must be: http", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy host not set")
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, proxyURL.Host)
+		if err != nil {
+			return nil, err
+		}
+		tunnel, err := connect(ctx, conn, proxyURL, address)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tunnel, nil
+	}
+
+	return dial, nil
+}
+
+// Send the CONNECT request and wait for the proxy to establish the tunnel.
+// Returns the connection to be used for the tunneled traffic.
+func connect(ctx context.Context, conn net.Conn, proxyURL *url.URL, address string) (net.Conn, error) {
+	if deadline, found := ctx.Deadline(); found {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := proxyURL.User.Username() + ":" + password
+		request.Header.Set(
+			"Proxy-Authorization",
+			"Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	}
+	err := request.Write(conn)
+	if err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy CONNECT to %s failed: %s", address, response.Status)
+	}
+	// Data sent by the server right after the proxy response.
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+
+	return conn, nil
+}
+
+// Connection reading data buffered while reading the proxy response first.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (r *bufferedConn) Read(b []byte) (int, error) {
+	return r.reader.Read(b)
+}
@@ -0,0 +1,81 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// Serve a single CONNECT request, answering with the status.
+// The requested address and authorization header are sent on the channel.
+func serveConnect(t *testing.T, status int) (*url.URL, chan *http.Request) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := make(chan *http.Request, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		request, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		requests <- request
+		response := &http.Response{StatusCode: status, ProtoMajor: 1, ProtoMinor: 1}
+		response.Write(conn)
+		conn.Write([]byte("tunneled"))
+	}()
+	return &url.URL{Scheme: "http", Host: listener.Addr().String(), User: url.UserPassword("user", "secret")}, requests
+}
+
+func TestNewDialer(t *testing.T) {
+	proxyURL, requests := serveConnect(t, http.StatusOK)
+	dial, err := NewDialer(proxyURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := dial(context.TODO(), "tcp", "api.example.com:6443")
+	if err != nil {
+		t.Fatalf("dial() error = %v", err)
+	}
+	defer conn.Close()
+	request := <-requests
+	if request.Method != http.MethodConnect || request.Host != "api.example.com:6443" {
+		t.Errorf("request = %s %s, want CONNECT api.example.com:6443", request.Method, request.Host)
+	}
+	if got := request.Header.Get("Proxy-Authorization"); got != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("Proxy-Authorization = %q", got)
+	}
+	b := make([]byte, len("tunneled"))
+	_, err = conn.Read(b)
+	if err != nil || string(b) != "tunneled" {
+		t.Errorf("Read() = %q, %v, want tunneled", b, err)
+	}
+}
+
+func TestNewDialer_Refused(t *testing.T) {
+	proxyURL, _ := serveConnect(t, http.StatusForbidden)
+	dial, err := NewDialer(proxyURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dial(context.TODO(), "tcp", "api.example.com:6443")
+	if err == nil {
+		t.Error("dial() expected error")
+	}
+}
+
+func TestNewDialer_Scheme(t *testing.T) {
+	_, err := NewDialer(&url.URL{Scheme: "socks5", Host: "proxy:1080"})
+	if err == nil {
+		t.Error("NewDialer() expected error")
+	}
+}
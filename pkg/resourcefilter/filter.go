@@ -0,0 +1,149 @@
+package resourcefilter
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	liberr "github.com/konveyor/controller/pkg/error"
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Filter selects the objects migrated in the plan namespaces.
+// The filter matches the objects selected by the velero backup built
+// from the included and excluded resources and the label selector.
+type Filter struct {
+	include  []migapi.ResourceFilterRule
+	exclude  []migapi.ResourceFilterRule
+	selector labels.Selector
+}
+
+// Build the filter.
+// Returns nil when no filters are defined.
+func New(filters *migapi.ResourceFilters) (*Filter, error) {
+	if filters == nil {
+		return nil, nil
+	}
+	for _, rules := range [][]migapi.ResourceFilterRule{filters.Include, filters.Exclude} {
+		for _, r := range rules {
+			if r.Kind == "" {
+				return nil, liberr.Wrap(errors.New("rule kind not specified"))
+			}
+		}
+	}
+	f := &Filter{
+		include: filters.Include,
+		exclude: filters.Exclude,
+	}
+	if filters.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(filters.LabelSelector)
+		if err != nil {
+			return nil, liberr.Wrap(err)
+		}
+		f.selector = selector
+	}
+	return f, nil
+}
+
+// Get whether the objects of a kind are migrated.
+// The kind is migrated when it matches an include rule (or no include
+// rules are defined) and matches no exclude rule.
+func (f *Filter) IncludesKind(gk schema.GroupKind) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 {
+		included := false
+		for _, r := range f.include {
+			if r.MatchesKind(gk.Group, gk.Kind) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, r := range f.exclude {
+		if r.MatchesKind(gk.Group, gk.Kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// Get whether an object is migrated.
+func (f *Filter) Included(object *unstructured.Unstructured) bool {
+	if f == nil {
+		return true
+	}
+	if !f.IncludesKind(object.GroupVersionKind().GroupKind()) {
+		return false
+	}
+	return f.selector == nil || f.selector.Matches(labels.Set(object.GetLabels()))
+}
+
+// Get the names of the resources matched by the include rules.
+// The names are qualified by group (resource.group) and include the
+// namespaces so the namespaces are migrated. Returns nil when no include
+// rules are defined.
+func IncludedResources(filters *migapi.ResourceFilters, resources []*metav1.APIResourceList) []string {
+	if filters == nil || len(filters.Include) == 0 {
+		return nil
+	}
+	names := resolve(filters.Include, resources)
+	names = append(names, "namespaces")
+	sort.Strings(names)
+	return names
+}
+
+// Get the names of the resources matched by the exclude rules.
+// The names are qualified by group (resource.group).
+func ExcludedResources(filters *migapi.ResourceFilters, resources []*metav1.APIResourceList) []string {
+	if filters == nil {
+		return nil
+	}
+	return resolve(filters.Exclude, resources)
+}
+
+// Resolve the rules to the names of the (namespaced) resources.
+func resolve(rules []migapi.ResourceFilterRule, resources []*metav1.APIResourceList) []string {
+	names := map[string]bool{}
+	for _, list := range resources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if !resource.Namespaced || strings.Contains(resource.Name, "/") {
+				continue
+			}
+			for _, r := range rules {
+				if r.MatchesKind(gv.Group, resource.Kind) {
+					names[gv.WithResource(resource.Name).GroupResource().String()] = true
+				}
+			}
+		}
+	}
+	list := []string{}
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Get whether a resource is found in a list of resource names.
+// The names are matched with and without the group.
+func Contains(names []string, gr schema.GroupResource) bool {
+	for _, name := range names {
+		if name == gr.Resource || name == gr.String() {
+			return true
+		}
+	}
+	return false
+}
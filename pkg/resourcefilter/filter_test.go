@@ -0,0 +1,163 @@
+package resourcefilter
+
+import (
+	"reflect"
+	"testing"
+
+	migapi "github.com/konveyor/mig-controller/pkg/apis/migration/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Build an object.
+func object(apiVersion, kind, name string, labels map[string]string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func TestFilter_Included(t *testing.T) {
+	objects := []unstructured.Unstructured{
+		object("apps/v1", "Deployment", "web", map[string]string{"app": "web"}),
+		object("apps/v1", "Deployment", "db", map[string]string{"app": "db"}),
+		object("v1", "Pod", "web-1-a", map[string]string{"app": "web"}),
+		object("v1", "ConfigMap", "web-config", nil),
+		object("v1", "Secret", "web-tls", nil),
+	}
+
+	tests := []struct {
+		name    string
+		filters *migapi.ResourceFilters
+		want    []string
+	}{
+		{
+			name:    "no filters",
+			filters: nil,
+			want:    []string{"web", "db", "web-1-a", "web-config", "web-tls"},
+		},
+		{
+			name: "include kinds",
+			filters: &migapi.ResourceFilters{
+				Include: []migapi.ResourceFilterRule{{Group: "apps", Kind: "Deployment"}, {Kind: "configmap"}},
+			},
+			want: []string{"web", "db", "web-config"},
+		},
+		{
+			name: "exclude kinds",
+			filters: &migapi.ResourceFilters{
+				Exclude: []migapi.ResourceFilterRule{{Kind: "Secret"}, {Group: "extensions", Kind: "Deployment"}},
+			},
+			want: []string{"web", "db", "web-1-a", "web-config"},
+		},
+		{
+			name: "label selector",
+			filters: &migapi.ResourceFilters{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			want: []string{"web", "web-1-a"},
+		},
+		{
+			name: "include kinds and label selector",
+			filters: &migapi.ResourceFilters{
+				Include:       []migapi.ResourceFilterRule{{Kind: "Deployment"}},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			want: []string{"web"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.filters)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got := []string{}
+			for i := range objects {
+				if f.Included(&objects[i]) {
+					got = append(got, objects[i].GetName())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Included() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters *migapi.ResourceFilters
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			filters: &migapi.ResourceFilters{Include: []migapi.ResourceFilterRule{{Kind: "ConfigMap"}}},
+		},
+		{
+			name:    "missing kind",
+			filters: &migapi.ResourceFilters{Exclude: []migapi.ResourceFilterRule{{Group: "apps"}}},
+			wantErr: true,
+		},
+		{
+			name: "invalid selector",
+			filters: &migapi.ResourceFilters{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.filters); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIncludedAndExcludedResources(t *testing.T) {
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+				{Name: "secrets", Kind: "Secret", Namespaced: true},
+				{Name: "nodes", Kind: "Node", Namespaced: false},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true},
+				{Name: "deployments/scale", Kind: "Scale", Namespaced: true},
+			},
+		},
+		{
+			GroupVersion: "extensions/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true},
+			},
+		},
+	}
+	filters := &migapi.ResourceFilters{
+		Include: []migapi.ResourceFilterRule{{Kind: "Deployment"}, {Kind: "ConfigMap"}},
+		Exclude: []migapi.ResourceFilterRule{{Kind: "Secret"}, {Kind: "Node"}},
+	}
+	wantIncluded := []string{"configmaps", "deployments.apps", "deployments.extensions", "namespaces"}
+	if got := IncludedResources(filters, resources); !reflect.DeepEqual(got, wantIncluded) {
+		t.Errorf("IncludedResources() = %v, want %v", got, wantIncluded)
+	}
+	wantExcluded := []string{"secrets"}
+	if got := ExcludedResources(filters, resources); !reflect.DeepEqual(got, wantExcluded) {
+		t.Errorf("ExcludedResources() = %v, want %v", got, wantExcluded)
+	}
+	if got := IncludedResources(&migapi.ResourceFilters{}, resources); got != nil {
+		t.Errorf("IncludedResources() = %v, want nil", got)
+	}
+}
@@ -1,8 +1,10 @@
 package settings
 
 import (
+	"errors"
 	"os"
 	"strings"
+	"time"
 )
 
 // DVM options
@@ -15,8 +17,17 @@ const (
 	RsyncOptInfo      = "RSYNC_OPT_INFO"
 	RsyncOptExtras    = "RSYNC_OPT_EXTRAS"
 	EnablePVResizing  = "ENABLE_DVM_PV_RESIZING"
+	TransportTimeout  = "DVM_TRANSPORT_TIMEOUT"
+	SnapshotTimeout   = "DVM_SNAPSHOT_TIMEOUT"
+	IngressClass      = "DVM_INGRESS_CLASS"
+	IngressAnnotation = "DVM_INGRESS_ANNOTATIONS"
+	LocalPlaintext    = "DVM_LOCAL_PLAINTEXT"
 )
 
+// Default annotations of the transport Ingresses.
+// TLS passthrough with the nginx Ingress controller.
+const DefaultIngressAnnotations = "nginx.ingress.kubernetes.io/ssl-passthrough=true"
+
 // RsyncOpts Rsync Options
 //	BwLimit: equivalent to --bwlimit=<integer>
 //	Archive: whether to set --archive option or not
@@ -35,9 +46,20 @@ type RsyncOpts struct {
 }
 
 // DvmOpts DVM settings
+//
+//	TransportTimeout: time to wait for the transport to be reachable before failing (minutes)
+//	SnapshotTimeout: time to wait for the source snapshots to be ready before failing (minutes)
+//	IngressClassName: default class of the transport Ingresses
+//	IngressAnnotations: default annotations of the transport Ingresses, in k=v,k=v format
+//	LocalPlaintext: whether volumes may be transferred within a cluster by a plaintext rsync daemon
 type DvmOpts struct {
 	RsyncOpts
-	EnablePVResizing bool
+	EnablePVResizing   bool
+	TransportTimeout   time.Duration
+	SnapshotTimeout    time.Duration
+	IngressClassName   string
+	IngressAnnotations map[string]string
+	LocalPlaintext     bool
 }
 
 // Load load rsync options
@@ -66,9 +88,46 @@ func (r *RsyncOpts) Load() error {
 func (r *DvmOpts) Load() error {
 	var err error
 	r.EnablePVResizing = getEnvBool(EnablePVResizing, false)
+	minutes, err := getEnvLimit(TransportTimeout, 10)
+	if err != nil {
+		return err
+	}
+	r.TransportTimeout = time.Duration(minutes) * time.Minute
+	minutes, err = getEnvLimit(SnapshotTimeout, 30)
+	if err != nil {
+		return err
+	}
+	r.SnapshotTimeout = time.Duration(minutes) * time.Minute
+	r.IngressClassName = os.Getenv(IngressClass)
+	r.LocalPlaintext = getEnvBool(LocalPlaintext, false)
+	annotations, found := os.LookupEnv(IngressAnnotation)
+	if !found {
+		annotations = DefaultIngressAnnotations
+	}
+	r.IngressAnnotations, err = parseAnnotations(annotations)
+	if err != nil {
+		return errors.New(IngressAnnotation + " must be in k=v,k=v format")
+	}
 	err = r.RsyncOpts.Load()
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// Parse annotations in k=v,k=v format.
+func parseAnnotations(s string) (map[string]string, error) {
+	annotations := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, errors.New("annotation not valid: " + pair)
+		}
+		annotations[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return annotations, nil
+}
@@ -0,0 +1,33 @@
+package settings
+
+import (
+	"os"
+)
+
+// Hook options
+const (
+	HookSourceImage     = "HOOK_SOURCE_IMAGE"
+	HookSourceAllowHTTP = "HOOK_SOURCE_ALLOW_HTTP"
+)
+
+// Default image of the init container cloning the Git hook sources.
+const DefaultHookSourceImage = "docker.io/alpine/git:latest"
+
+// Hook settings
+//
+//	SourceImage: image cloning the Git hook sources, must provide sh and git
+//	SourceAllowHTTP: whether Git hook sources may be fetched over plain HTTP
+type Hook struct {
+	SourceImage     string
+	SourceAllowHTTP bool
+}
+
+// Load loads hook options
+func (r *Hook) Load() error {
+	r.SourceImage = DefaultHookSourceImage
+	if s, found := os.LookupEnv(HookSourceImage); found && s != "" {
+		r.SourceImage = s
+	}
+	r.SourceAllowHTTP = getEnvBool(HookSourceAllowHTTP, false)
+	return nil
+}
@@ -34,6 +34,7 @@ type _Settings struct {
 	Discovery
 	Plan
 	DvmOpts
+	Hook
 	Roles     map[string]bool
 	ProxyVars map[string]string
 }
@@ -52,6 +53,10 @@ func (r *_Settings) Load() error {
 	if err != nil {
 		return err
 	}
+	err = r.Hook.Load()
+	if err != nil {
+		return err
+	}
 	err = r.loadRoles()
 	if err != nil {
 		return err
@@ -0,0 +1,232 @@
+package transfer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/go-logr/logr"
+)
+
+// Transfer client.
+// Source - The directory synced to the module.
+// Module - The name of the destination directory (volume).
+// Token - The (optional) shared secret expected by the server.
+// Delete - Delete the entries not found on the source.
+// Checksum - Compare files by checksum instead of size and mtime.
+// BwLimit - The bandwidth limit in KB/s, 0 for no limit.
+// Tracker - The progress tracker.
+// Log - The logger.
+type Client struct {
+	Source   string
+	Module   string
+	Token    string
+	Delete   bool
+	Checksum bool
+	BwLimit  int
+	Tracker  *Tracker
+	Log      logr.Logger
+	limiter  *limiter
+}
+
+// Run the transfer on a connection.
+func (r *Client) Run(conn io.ReadWriter) (err error) {
+	if r.Tracker == nil {
+		r.Tracker = NewTracker()
+	}
+	r.limiter = newLimiter(r.BwLimit)
+	defer func() {
+		r.Tracker.complete(err)
+	}()
+	headers, err := r.scan()
+	if err != nil {
+		return err
+	}
+	c := newCodec(conn)
+	err = c.send(&Message{
+		Hello: &Hello{
+			Version:  Version,
+			Token:    r.Token,
+			Module:   r.Module,
+			Delete:   r.Delete,
+			Checksum: r.Checksum,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = c.result()
+	if err != nil {
+		return err
+	}
+	for _, header := range headers {
+		err = r.sync(c, header)
+		if err != nil {
+			return err
+		}
+		r.Tracker.transferred()
+	}
+	err = c.send(&Message{End: &End{}})
+	if err != nil {
+		return err
+	}
+	return c.result()
+}
+
+// Scan the source.
+// Entries other than directories, regular files and symlinks are skipped.
+func (r *Client) scan() ([]*Header, error) {
+	headers := []*Header{}
+	err := filepath.Walk(r.Source, func(local string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if local == r.Source {
+			return nil
+		}
+		rel, err := filepath.Rel(r.Source, local)
+		if err != nil {
+			return err
+		}
+		header := &Header{
+			Path:    filepath.ToSlash(rel),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime(),
+		}
+		if stat, cast := info.Sys().(*syscall.Stat_t); cast {
+			header.Uid = int(stat.Uid)
+			header.Gid = int(stat.Gid)
+		}
+		switch {
+		case info.Mode().IsRegular():
+			header.Type = File
+			header.Size = info.Size()
+		case info.IsDir():
+			header.Type = Dir
+		case info.Mode()&os.ModeSymlink != 0:
+			header.Type = Symlink
+			header.Link, err = os.Readlink(local)
+			if err != nil {
+				return err
+			}
+		default:
+			r.Log.Info("entry skipped.", "path", header.Path, "mode", info.Mode().String())
+			return nil
+		}
+		headers = append(headers, header)
+		r.Tracker.found(header.Size)
+		return nil
+	})
+
+	return headers, err
+}
+
+// Sync an entry.
+func (r *Client) sync(c *codec, header *Header) error {
+	err := c.send(&Message{Header: header})
+	if err != nil {
+		return err
+	}
+	if header.Type != File {
+		return c.result()
+	}
+	m, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if m.Result != nil {
+		return m.Result.Err()
+	}
+	if m.Offer == nil {
+		return fmt.Errorf("unexpected message: offer expected")
+	}
+	if m.Offer.Skip {
+		r.Tracker.skipped(header.Size)
+		return nil
+	}
+	err = r.send(c, header, m.Offer)
+	if err != nil {
+		return err
+	}
+	return c.result()
+}
+
+// Send the data of a regular file.
+// Resumes from the offered offset when the digest of the offered
+// file matches the beginning of the source file.
+func (r *Client) send(c *codec, header *Header, offer *Offer) error {
+	local := filepath.Join(r.Source, filepath.FromSlash(header.Path))
+	digest := sha256.New()
+	offset := int64(0)
+	if offer.Offset > 0 && offer.Offset <= header.Size {
+		prefix, err := fileDigest(local, offer.Offset)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(prefix, offer.Digest) {
+			if offer.Existing {
+				r.Tracker.skipped(header.Size)
+				return c.send(&Message{Data: &Data{Keep: true}})
+			}
+			offset = offer.Offset
+			err = hashFile(digest, local, offset)
+			if err != nil {
+				return err
+			}
+			r.Tracker.skipped(offset)
+		}
+	}
+	file, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	buffer := make([]byte, ChunkSize)
+	remaining := header.Size - offset
+	first := true
+	for {
+		n, err := io.ReadFull(file, buffer[:chunkLen(remaining)])
+		short := err == io.ErrUnexpectedEOF || err == io.EOF
+		if err != nil && !short {
+			return err
+		}
+		remaining -= int64(n)
+		digest.Write(buffer[:n])
+		data := &Data{
+			Bytes: buffer[:n],
+			EOF:   remaining <= 0 || short,
+		}
+		if first {
+			data.Offset = offset
+			first = false
+		}
+		if data.EOF {
+			data.Digest = digest.Sum(nil)
+		}
+		r.limiter.wait(n)
+		err = c.send(&Message{Data: data})
+		if err != nil {
+			return err
+		}
+		r.Tracker.sent(int64(n))
+		if data.EOF {
+			return nil
+		}
+	}
+}
+
+// Get the length of the next chunk.
+func chunkLen(remaining int64) int {
+	if remaining < ChunkSize {
+		return int(remaining)
+	}
+	return ChunkSize
+}
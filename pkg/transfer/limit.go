@@ -0,0 +1,46 @@
+package transfer
+
+import (
+	"time"
+)
+
+// Bandwidth limiter.
+// Delays the sender so the average rate since the first
+// chunk stays within the limit.
+// rate - The limit in bytes per second, 0 for no limit.
+// started - When the first chunk was sent.
+// sent - The bytes sent.
+// now - Gets the current time.
+// sleep - Sleeps for a duration.
+type limiter struct {
+	rate    int64
+	started time.Time
+	sent    int64
+	now     func() time.Time
+	sleep   func(time.Duration)
+}
+
+// Build a limiter of a rate in KB/s (1024 bytes, as rsync --bwlimit).
+func newLimiter(kbps int) *limiter {
+	l := &limiter{now: time.Now, sleep: time.Sleep}
+	if kbps > 0 {
+		l.rate = int64(kbps) * 1024
+	}
+	return l
+}
+
+// Wait until n more bytes can be sent within the limit.
+func (r *limiter) wait(n int) {
+	if r.rate == 0 {
+		return
+	}
+	now := r.now()
+	if r.started.IsZero() {
+		r.started = now
+	}
+	r.sent += int64(n)
+	due := r.started.Add(time.Duration(r.sent * int64(time.Second) / r.rate))
+	if delay := due.Sub(now); delay > 0 {
+		r.sleep(delay)
+	}
+}
@@ -0,0 +1,128 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Port and path of the progress API served by the client.
+const (
+	ProgressPort = 8080
+	ProgressPath = "/progress"
+)
+
+// Progress of a transfer.
+// TotalBytes - The size of the regular files found on the source.
+// TransferredBytes - The size of the files transferred or found up to date.
+// SentBytes - The bytes sent to the server.
+// TotalFiles - The number of entries found on the source.
+// TransferredFiles - The number of entries transferred or found up to date.
+// BytesPerSecond - The average rate of the bytes sent.
+// Completed - The transfer has completed.
+// Error - The error of a failed transfer.
+type Progress struct {
+	TotalBytes       int64   `json:"totalBytes"`
+	TransferredBytes int64   `json:"transferredBytes"`
+	SentBytes        int64   `json:"sentBytes"`
+	TotalFiles       int64   `json:"totalFiles"`
+	TransferredFiles int64   `json:"transferredFiles"`
+	BytesPerSecond   float64 `json:"bytesPerSecond"`
+	Completed        bool    `json:"completed"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// Get the progress percentage, e.g. "42%".
+func (p Progress) Percent() string {
+	if p.TotalBytes == 0 {
+		if p.Completed {
+			return "100%"
+		}
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", p.TransferredBytes*100/p.TotalBytes)
+}
+
+// Get the transfer rate, e.g. "1.50MB/s".
+func (p Progress) Rate() string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	rate := p.BytesPerSecond
+	n := 0
+	for rate >= 1000 && n < len(units)-1 {
+		rate /= 1000
+		n++
+	}
+	return fmt.Sprintf("%.2f%s/s", rate, units[n])
+}
+
+// Tracks the progress of a transfer.
+// Safe for concurrent use.
+type Tracker struct {
+	mutex    sync.Mutex
+	progress Progress
+	started  time.Time
+}
+
+// Create a tracker.
+func NewTracker() *Tracker {
+	return &Tracker{started: time.Now()}
+}
+
+// Add an entry found on the source.
+func (t *Tracker) found(size int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.progress.TotalFiles++
+	t.progress.TotalBytes += size
+}
+
+// Add bytes sent to the server.
+func (t *Tracker) sent(n int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.progress.SentBytes += n
+	t.progress.TransferredBytes += n
+	elapsed := time.Since(t.started).Seconds()
+	if elapsed > 0 {
+		t.progress.BytesPerSecond = float64(t.progress.SentBytes) / elapsed
+	}
+}
+
+// Add bytes found up to date on the server.
+func (t *Tracker) skipped(n int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.progress.TransferredBytes += n
+}
+
+// Add a transferred entry.
+func (t *Tracker) transferred() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.progress.TransferredFiles++
+}
+
+// Mark the transfer completed.
+func (t *Tracker) complete(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.progress.Completed = err == nil
+	if err != nil {
+		t.progress.Error = err.Error()
+	}
+}
+
+// Get a snapshot of the progress.
+func (t *Tracker) Progress() Progress {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.progress
+}
+
+// Serve the progress as JSON.
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(t.Progress())
+}
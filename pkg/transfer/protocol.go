@@ -0,0 +1,174 @@
+// Package transfer provides the DVM transfer agent.
+//
+// The agent syncs the files of a volume from a client (source cluster)
+// to a server (destination cluster) over mutual TLS. Messages are gob
+// encoded. For each entry found on the source, the client sends a
+// Header. The server replies with an Offer for regular files, then the
+// client sends the file Data. Files are written to a partial file which
+// is renamed when the SHA-256 digest sent by the client is verified.
+// A transfer interrupted midway resumes from the partial file when its
+// digest matches the beginning of the source file.
+package transfer
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Protocol version.
+const Version = 1
+
+// Size of the file data chunks.
+const ChunkSize = 1024 * 1024
+
+// Prefix of the partial files written by the server.
+const PartialPrefix = ".transfer-partial."
+
+// Environment variable with the shared secret of the agent.
+const TokenEnv = "TRANSFER_TOKEN"
+
+// Entry types.
+const (
+	File    = "File"
+	Dir     = "Dir"
+	Symlink = "Symlink"
+)
+
+// Opens a transfer.
+// Version - The protocol version.
+// Token - The (optional) shared secret.
+// Module - The name of the destination directory (volume).
+// Delete - Delete the entries not found on the source.
+// Checksum - Compare files by checksum instead of size and mtime.
+type Hello struct {
+	Version  int
+	Token    string
+	Module   string
+	Delete   bool
+	Checksum bool
+}
+
+// An entry found on the source.
+// Path - The slash separated path relative to the volume.
+type Header struct {
+	Path    string
+	Type    string
+	Mode    os.FileMode
+	ModTime time.Time
+	Size    int64
+	Link    string
+	Uid     int
+	Gid     int
+}
+
+// The server offer for a regular file.
+// Skip - The file is up to date.
+// Offset - The size of the (partial or existing) file found.
+// Digest - The SHA-256 digest of the file found.
+// Existing - The file found is the destination file (not partial).
+type Offer struct {
+	Skip     bool
+	Offset   int64
+	Digest   []byte
+	Existing bool
+}
+
+// File data.
+// Offset - The offset of the first chunk, 0 or the offered offset.
+// Keep - The existing file is up to date, sent without bytes.
+// EOF - The last chunk.
+// Digest - The SHA-256 digest of the file, sent with the last chunk.
+type Data struct {
+	Offset int64
+	Bytes  []byte
+	Keep   bool
+	EOF    bool
+	Digest []byte
+}
+
+// Ends the transfer.
+type End struct {
+}
+
+// The result of an operation.
+type Result struct {
+	Error string
+}
+
+// Get the result error.
+func (r *Result) Err() error {
+	if r.Error == "" {
+		return nil
+	}
+	return fmt.Errorf("%s", r.Error)
+}
+
+// Message envelope.
+// Exactly one field is set.
+type Message struct {
+	Hello  *Hello
+	Header *Header
+	Offer  *Offer
+	Data   *Data
+	End    *End
+	Result *Result
+}
+
+// Gob codec of a connection.
+type codec struct {
+	encoder *gob.Encoder
+	decoder *gob.Decoder
+}
+
+// Create a codec.
+func newCodec(rw io.ReadWriter) *codec {
+	return &codec{
+		encoder: gob.NewEncoder(rw),
+		decoder: gob.NewDecoder(rw),
+	}
+}
+
+// Send a message.
+func (c *codec) send(m *Message) error {
+	return c.encoder.Encode(m)
+}
+
+// Receive a message.
+func (c *codec) receive() (*Message, error) {
+	m := &Message{}
+	err := c.decoder.Decode(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Receive a result.
+func (c *codec) result() error {
+	m, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if m.Result == nil {
+		return fmt.Errorf("unexpected message: result expected")
+	}
+	return m.Result.Err()
+}
+
+// Validate a relative (slash separated) path.
+func validPath(p string) bool {
+	if p == "" || path.IsAbs(p) || path.Clean(p) != p {
+		return false
+	}
+	return p != ".." && !strings.HasPrefix(p, "../")
+}
+
+// Validate a module name.
+func validModule(module string) bool {
+	return module != "" && module != "." && module != ".." && !strings.ContainsAny(module, "/\\")
+}
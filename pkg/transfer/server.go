@@ -0,0 +1,465 @@
+package transfer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// Transfer server.
+// Root - The directory containing the modules (volumes).
+// Token - The (optional) shared secret expected from clients.
+// Log - The logger.
+type Server struct {
+	Root  string
+	Token string
+	Log   logr.Logger
+}
+
+// Serve the connections accepted by the listener.
+// The listener is expected to wrap connections with TLS.
+func (r *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			err := r.Handle(conn)
+			if err != nil {
+				r.Log.Error(err, "transfer failed.", "client", conn.RemoteAddr().String())
+			}
+		}()
+	}
+}
+
+// Handle a transfer.
+func (r *Server) Handle(conn io.ReadWriter) error {
+	c := newCodec(conn)
+	m, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if m.Hello == nil {
+		return fmt.Errorf("unexpected message: hello expected")
+	}
+	hello := m.Hello
+	session, err := r.open(hello)
+	if err != nil {
+		_ = c.send(&Message{Result: &Result{Error: err.Error()}})
+		return err
+	}
+	err = c.send(&Message{Result: &Result{}})
+	if err != nil {
+		return err
+	}
+	r.Log.Info("transfer started.", "module", hello.Module)
+	for {
+		m, err := c.receive()
+		if err != nil {
+			return err
+		}
+		switch {
+		case m.Header != nil:
+			err = session.entry(c, m.Header)
+		case m.End != nil:
+			err = session.end()
+			if err == nil {
+				r.Log.Info("transfer completed.", "module", hello.Module)
+			}
+			result := &Result{}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			sendErr := c.send(&Message{Result: result})
+			if err != nil {
+				return err
+			}
+			return sendErr
+		default:
+			err = fmt.Errorf("unexpected message: header or end expected")
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Open a session.
+func (r *Server) open(hello *Hello) (*session, error) {
+	if hello.Version != Version {
+		return nil, fmt.Errorf("protocol version %d not supported", hello.Version)
+	}
+	if subtle.ConstantTimeCompare([]byte(hello.Token), []byte(r.Token)) != 1 {
+		return nil, fmt.Errorf("not authorized")
+	}
+	if !validModule(hello.Module) {
+		return nil, fmt.Errorf("module %s not valid", hello.Module)
+	}
+	root := filepath.Join(r.Root, hello.Module)
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("module %s not a directory", hello.Module)
+	}
+	return &session{
+		hello: hello,
+		root:  root,
+		seen:  map[string]bool{},
+		dirs:  map[string]*Header{},
+	}, nil
+}
+
+// A transfer session.
+// seen - The paths of the entries found on the source.
+// dirs - The directories with metadata applied on end.
+type session struct {
+	hello *Hello
+	root  string
+	seen  map[string]bool
+	dirs  map[string]*Header
+}
+
+// Get the local path of an entry.
+func (r *session) local(p string) string {
+	return filepath.Join(r.root, filepath.FromSlash(p))
+}
+
+// Get the local path of the partial file of an entry.
+func (r *session) partial(p string) string {
+	local := r.local(p)
+	return filepath.Join(filepath.Dir(local), PartialPrefix+filepath.Base(local))
+}
+
+// Sync an entry.
+func (r *session) entry(c *codec, header *Header) error {
+	if !validPath(header.Path) {
+		return fmt.Errorf("path %s not valid", header.Path)
+	}
+	err := r.checkParents(header.Path)
+	if err != nil {
+		return err
+	}
+	r.seen[header.Path] = true
+	switch header.Type {
+	case Dir:
+		err = r.dir(header)
+	case Symlink:
+		err = r.symlink(header)
+	case File:
+		return r.file(c, header)
+	default:
+		err = fmt.Errorf("type %s not supported", header.Type)
+	}
+	result := &Result{}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return c.send(&Message{Result: result})
+}
+
+// Check the parents of an entry are directories.
+// Symlinks are not followed so entries are never written outside the root.
+func (r *session) checkParents(p string) error {
+	local := r.root
+	parts := strings.Split(p, "/")
+	for _, part := range parts[:len(parts)-1] {
+		local = filepath.Join(local, part)
+		info, err := os.Lstat(local)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("path %s not valid: parent not a directory", p)
+		}
+	}
+	return nil
+}
+
+// Sync a directory.
+// The mode and times are applied on end.
+func (r *session) dir(header *Header) error {
+	local := r.local(header.Path)
+	info, err := os.Lstat(local)
+	if err == nil && !info.IsDir() {
+		err = os.RemoveAll(local)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.MkdirAll(local, 0700)
+	if err != nil {
+		return err
+	}
+	r.dirs[header.Path] = header
+	return nil
+}
+
+// Sync a symlink.
+func (r *session) symlink(header *Header) error {
+	local := r.local(header.Path)
+	if link, err := os.Readlink(local); err == nil && link == header.Link {
+		return nil
+	}
+	err := os.RemoveAll(local)
+	if err != nil {
+		return err
+	}
+	err = os.Symlink(header.Link, local)
+	if err != nil {
+		return err
+	}
+	_ = os.Lchown(local, header.Uid, header.Gid)
+	return nil
+}
+
+// Sync a regular file.
+func (r *session) file(c *codec, header *Header) error {
+	offer, err := r.offer(header)
+	if err != nil {
+		return c.send(&Message{Result: &Result{Error: err.Error()}})
+	}
+	err = c.send(&Message{Offer: offer})
+	if err != nil {
+		return err
+	}
+	if offer.Skip {
+		return nil
+	}
+	err = r.receive(c, header, offer)
+	result := &Result{}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return c.send(&Message{Result: result})
+}
+
+// Build the offer of a regular file.
+func (r *session) offer(header *Header) (*Offer, error) {
+	local := r.local(header.Path)
+	info, err := os.Lstat(local)
+	if err == nil && info.Mode().IsRegular() && info.Size() == header.Size {
+		if !r.hello.Checksum {
+			if info.ModTime().Equal(header.ModTime) {
+				return &Offer{Skip: true}, r.apply(local, header)
+			}
+		} else {
+			digest, err := fileDigest(local, header.Size)
+			if err != nil {
+				return nil, err
+			}
+			return &Offer{Offset: header.Size, Digest: digest, Existing: true}, nil
+		}
+	}
+	info, err = os.Lstat(r.partial(header.Path))
+	if err == nil && info.Mode().IsRegular() && info.Size() <= header.Size {
+		digest, err := fileDigest(r.partial(header.Path), info.Size())
+		if err != nil {
+			return nil, err
+		}
+		return &Offer{Offset: info.Size(), Digest: digest}, nil
+	}
+	return &Offer{}, nil
+}
+
+// Receive the data of a regular file.
+// The partial file is kept for resuming unless the checksum does not match.
+func (r *session) receive(c *codec, header *Header, offer *Offer) error {
+	m, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if m.Data == nil {
+		return fmt.Errorf("unexpected message: data expected")
+	}
+	local := r.local(header.Path)
+	if m.Data.Keep {
+		if !offer.Existing {
+			return fmt.Errorf("%s: no existing file to keep", header.Path)
+		}
+		return r.apply(local, header)
+	}
+	digest := sha256.New()
+	partial := r.partial(header.Path)
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if m.Data.Offset > 0 {
+		if offer.Existing || m.Data.Offset != offer.Offset {
+			return fmt.Errorf("%s: offset %d not offered", header.Path, m.Data.Offset)
+		}
+		err = hashFile(digest, partial, m.Data.Offset)
+		if err != nil {
+			return err
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	info, err := os.Lstat(partial)
+	if err == nil && !info.Mode().IsRegular() {
+		err = os.RemoveAll(partial)
+		if err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(partial, flags, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for {
+		_, err = file.Write(m.Data.Bytes)
+		if err != nil {
+			return err
+		}
+		digest.Write(m.Data.Bytes)
+		if m.Data.EOF {
+			break
+		}
+		m, err = c.receive()
+		if err != nil {
+			return err
+		}
+		if m.Data == nil {
+			return fmt.Errorf("unexpected message: data expected")
+		}
+	}
+	if !bytes.Equal(digest.Sum(nil), m.Data.Digest) {
+		_ = os.Remove(partial)
+		return fmt.Errorf("%s: checksum mismatch", header.Path)
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	info, err = os.Lstat(local)
+	if err == nil && info.IsDir() {
+		err = os.RemoveAll(local)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.Rename(partial, local)
+	if err != nil {
+		return err
+	}
+	return r.apply(local, header)
+}
+
+// Apply the owner, mode and times of an entry.
+// Changing the owner is best effort.
+func (r *session) apply(local string, header *Header) error {
+	_ = os.Lchown(local, header.Uid, header.Gid)
+	err := os.Chmod(local, header.Mode.Perm())
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(local, header.ModTime, header.ModTime)
+}
+
+// End the session.
+// Deletes the entries not found on the source when requested and
+// applies the directory metadata, deepest first.
+func (r *session) end() error {
+	if r.hello.Delete {
+		err := r.delete()
+		if err != nil {
+			return err
+		}
+	}
+	paths := []string{}
+	for p := range r.dirs {
+		paths = append(paths, p)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	for _, p := range paths {
+		// Replaced by another entry type.
+		info, err := os.Lstat(r.local(p))
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		err = r.apply(r.local(p), r.dirs[p])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete the entries not found on the source.
+func (r *session) delete() error {
+	unseen := []string{}
+	err := filepath.Walk(r.root, func(local string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if local == r.root {
+			return nil
+		}
+		rel, err := filepath.Rel(r.root, local)
+		if err != nil {
+			return err
+		}
+		if !r.seen[filepath.ToSlash(rel)] {
+			unseen = append(unseen, local)
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, local := range unseen {
+		err = os.RemoveAll(local)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get the SHA-256 digest of the first n bytes of a file.
+func fileDigest(path string, n int64) ([]byte, error) {
+	digest := sha256.New()
+	err := hashFile(digest, path, n)
+	if err != nil {
+		return nil, err
+	}
+	return digest.Sum(nil), nil
+}
+
+// Hash the first n bytes of a file.
+func hashFile(digest hash.Hash, path string, n int64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	written, err := io.CopyN(digest, file, n)
+	if err != nil {
+		return err
+	}
+	if written != n {
+		return fmt.Errorf("%s: short read", path)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package transfer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// TLS settings.
+// CAFile - The CA certificates (PEM) verifying the peer.
+// CertFile - The certificate (PEM).
+// KeyFile - The private key (PEM).
+// MinVersion - The minimum TLS version, TLSv1.2 (default) or TLSv1.3.
+// VerifyClient - The server requires and verifies client certificates.
+// ServerName - The TLS server name (SNI) sent by the client.
+type TLS struct {
+	CAFile       string
+	CertFile     string
+	KeyFile      string
+	MinVersion   string
+	VerifyClient bool
+	ServerName   string
+}
+
+// Build the base config.
+func (r *TLS) config() (*tls.Config, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	pem, err := ioutil.ReadFile(r.CAFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, nil, fmt.Errorf("%s: no certificates found", r.CAFile)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	switch r.MinVersion {
+	case "", "TLSv1.2":
+	case "TLSv1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, nil, fmt.Errorf("TLS version %s not supported", r.MinVersion)
+	}
+	return config, pool, nil
+}
+
+// Build the server config.
+func (r *TLS) ServerConfig() (*tls.Config, error) {
+	config, pool, err := r.config()
+	if err != nil {
+		return nil, err
+	}
+	if r.VerifyClient {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientCAs = pool
+	}
+	return config, nil
+}
+
+// Build the client config.
+// The server certificate is verified against the CA without matching
+// the host name since the server is reached through routes, ingresses
+// and load balancers with unrelated names.
+func (r *TLS) ClientConfig() (*tls.Config, error) {
+	config, pool, err := r.config()
+	if err != nil {
+		return nil, err
+	}
+	config.ServerName = r.ServerName
+	config.InsecureSkipVerify = true
+	config.VerifyPeerCertificate = func(raw [][]byte, _ [][]*x509.Certificate) error {
+		if len(raw) == 0 {
+			return fmt.Errorf("no server certificate")
+		}
+		certs := []*x509.Certificate{}
+		for _, der := range raw {
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         pool,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		return err
+	}
+	return config, nil
+}
@@ -0,0 +1,412 @@
+package transfer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Create a temporary directory removed by the test cleanup.
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	return dir
+}
+
+// Build a source directory.
+func source(t *testing.T, files map[string]string) string {
+	dir := tempDir(t)
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for p, content := range files {
+		local := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(local, []byte(content), 0640); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(local, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// Run a transfer over a pipe.
+func run(t *testing.T, client *Client, root string) (Progress, error) {
+	server := &Server{Root: root, Log: logr.Discard()}
+	serverConn, clientConn := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		defer serverConn.Close()
+		done <- server.Handle(serverConn)
+	}()
+	client.Log = logr.Discard()
+	client.Tracker = NewTracker()
+	err := client.Run(clientConn)
+	clientConn.Close()
+	<-done
+	return client.Tracker.Progress(), err
+}
+
+// Read a destination file.
+func read(t *testing.T, root, p string) string {
+	b, err := ioutil.ReadFile(filepath.Join(root, filepath.FromSlash(p)))
+	if err != nil {
+		t.Fatalf("read %s: %v", p, err)
+	}
+	return string(b)
+}
+
+func TestClient_Run(t *testing.T) {
+	large := string(bytes.Repeat([]byte("0123456789"), ChunkSize/5))
+	src := source(t, map[string]string{
+		"a.txt":       "hello",
+		"dir/b.txt":   large,
+		"dir/c/empty": "",
+	})
+	if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+	root := tempDir(t)
+	if err := os.Mkdir(filepath.Join(root, "pvc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(root, "pvc")
+	client := &Client{Source: src, Module: "pvc", Delete: true}
+
+	// Initial transfer.
+	progress, err := run(t, client, root)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if read(t, dest, "a.txt") != "hello" || read(t, dest, "dir/b.txt") != large || read(t, dest, "dir/c/empty") != "" {
+		t.Errorf("Run() files not transferred")
+	}
+	if link, _ := os.Readlink(filepath.Join(dest, "link")); link != "a.txt" {
+		t.Errorf("Run() link = %s", link)
+	}
+	srcInfo, _ := os.Stat(filepath.Join(src, "dir/b.txt"))
+	destInfo, _ := os.Stat(filepath.Join(dest, "dir/b.txt"))
+	if !destInfo.ModTime().Equal(srcInfo.ModTime()) || destInfo.Mode() != srcInfo.Mode() {
+		t.Errorf("Run() metadata = %v %v, want %v %v", destInfo.ModTime(), destInfo.Mode(), srcInfo.ModTime(), srcInfo.Mode())
+	}
+	if !progress.Completed || progress.Percent() != "100%" || progress.SentBytes != int64(len(large)+5) {
+		t.Errorf("Run() progress = %+v", progress)
+	}
+
+	// Up to date.
+	progress, err = run(t, client, root)
+	if err != nil || progress.SentBytes != 0 || progress.Percent() != "100%" {
+		t.Errorf("Run() progress = %+v, error = %v", progress, err)
+	}
+
+	// Resumed from a partial file, extra file deleted.
+	half := int64(len(large) / 2)
+	_ = os.Remove(filepath.Join(dest, "dir/b.txt"))
+	partial := filepath.Join(dest, "dir", PartialPrefix+"b.txt")
+	if err = ioutil.WriteFile(partial, []byte(large[:half]), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dest, "extra"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	progress, err = run(t, client, root)
+	if err != nil || progress.SentBytes != int64(len(large))-half {
+		t.Errorf("Run() progress = %+v, error = %v", progress, err)
+	}
+	if read(t, dest, "dir/b.txt") != large {
+		t.Errorf("Run() resumed file corrupted")
+	}
+	if _, err = os.Stat(filepath.Join(dest, "extra")); !os.IsNotExist(err) {
+		t.Errorf("Run() extra file not deleted")
+	}
+	if _, err = os.Stat(partial); !os.IsNotExist(err) {
+		t.Errorf("Run() partial file not renamed")
+	}
+
+	// Partial file not matching the source.
+	_ = os.Remove(filepath.Join(dest, "dir/b.txt"))
+	if err = ioutil.WriteFile(partial, []byte("not the source"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	progress, err = run(t, client, root)
+	if err != nil || progress.SentBytes != int64(len(large)) || read(t, dest, "dir/b.txt") != large {
+		t.Errorf("Run() progress = %+v, error = %v", progress, err)
+	}
+
+	// Checksum detects content changed with the same size and mtime.
+	destA := filepath.Join(dest, "a.txt")
+	if err = ioutil.WriteFile(destA, []byte("HELLO"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chtimes(destA, srcInfo.ModTime(), srcInfo.ModTime())
+	client.Checksum = true
+	progress, err = run(t, client, root)
+	if err != nil || progress.SentBytes != 5 || read(t, dest, "a.txt") != "hello" {
+		t.Errorf("Run() progress = %+v, error = %v", progress, err)
+	}
+}
+
+func TestServer_open(t *testing.T) {
+	root := tempDir(t)
+	if err := os.Mkdir(filepath.Join(root, "pvc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{Root: root, Token: "secret"}
+	tests := []struct {
+		name  string
+		hello Hello
+		valid bool
+	}{
+		{name: "valid", hello: Hello{Version: Version, Token: "secret", Module: "pvc"}, valid: true},
+		{name: "token", hello: Hello{Version: Version, Token: "wrong", Module: "pvc"}},
+		{name: "version", hello: Hello{Version: Version + 1, Token: "secret", Module: "pvc"}},
+		{name: "traversal", hello: Hello{Version: Version, Token: "secret", Module: ".."}},
+		{name: "missing", hello: Hello{Version: Version, Token: "secret", Module: "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.open(&tt.hello)
+			if (err == nil) != tt.valid {
+				t.Errorf("open() error = %v, valid %v", err, tt.valid)
+			}
+		})
+	}
+	for p, valid := range map[string]bool{"a/b": true, "../a": false, "/a": false, "a/../../b": false, "..": false} {
+		if validPath(p) != valid {
+			t.Errorf("validPath(%s) = %v, want %v", p, !valid, valid)
+		}
+	}
+}
+
+func TestServer_symlinkTraversal(t *testing.T) {
+	root := tempDir(t)
+	if err := os.Mkdir(filepath.Join(root, "pvc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	outside := tempDir(t)
+	target := filepath.Join(outside, "target")
+	if err := ioutil.WriteFile(target, []byte("outside"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		headers []*Header
+	}{
+		{
+			name: "dir through symlink",
+			headers: []*Header{
+				{Path: "a", Type: Symlink, Link: outside},
+				{Path: "a/dir", Type: Dir, Mode: os.ModeDir | 0755},
+			},
+		},
+		{
+			name: "file through symlink",
+			headers: []*Header{
+				{Path: "a", Type: Symlink, Link: outside},
+				{Path: "a/file", Type: File, Mode: 0644},
+			},
+		},
+		{
+			name: "symlink through symlink",
+			headers: []*Header{
+				{Path: "a", Type: Symlink, Link: outside},
+				{Path: "a/link", Type: Symlink, Link: "/"},
+			},
+		},
+		{
+			name: "partial file symlink",
+			headers: []*Header{
+				{Path: PartialPrefix + "file", Type: Symlink, Link: target},
+				{Path: "file", Type: File, Mode: 0644, Size: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{Root: root, Log: logr.Discard()}
+			serverConn, clientConn := net.Pipe()
+			done := make(chan error, 1)
+			go func() {
+				defer serverConn.Close()
+				done <- server.Handle(serverConn)
+			}()
+			c := newCodec(clientConn)
+			_ = c.send(&Message{Hello: &Hello{Version: Version, Module: "pvc"}})
+			_ = c.result()
+			for _, header := range tt.headers {
+				if c.send(&Message{Header: header}) != nil {
+					break
+				}
+				m, err := c.receive()
+				if err != nil {
+					break
+				}
+				if m.Offer != nil {
+					digest := sha256.Sum256([]byte("data"))
+					_ = c.send(&Message{Data: &Data{Bytes: []byte("data"), EOF: true, Digest: digest[:]}})
+					_ = c.result()
+				}
+			}
+			_ = c.send(&Message{End: &End{}})
+			clientConn.Close()
+			<-done
+			entries, err := ioutil.ReadDir(outside)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || read(t, outside, "target") != "outside" {
+				t.Errorf("Handle() wrote outside the root: %v", entries)
+			}
+			_ = os.RemoveAll(filepath.Join(root, "pvc", "a"))
+		})
+	}
+}
+
+// Generate a key and a certificate signed by the parent (self-signed when nil).
+func newCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *rsa.PrivateKey, usage x509.ExtKeyUsage) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = ioutil.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	_ = ioutil.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+	return cert, key
+}
+
+func TestTLS(t *testing.T) {
+	dir := tempDir(t)
+	ca, caKey := newCert(t, dir, "ca", nil, nil, x509.ExtKeyUsageAny)
+	newCert(t, dir, "server", ca, caKey, x509.ExtKeyUsageServerAuth)
+	newCert(t, dir, "client", ca, caKey, x509.ExtKeyUsageClientAuth)
+	caFile := filepath.Join(dir, "ca.crt")
+	serverCert, serverKey := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	clientCert, clientKey := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+
+	serverTLS := &TLS{CAFile: caFile, CertFile: serverCert, KeyFile: serverKey, VerifyClient: true, MinVersion: "TLSv1.3"}
+	serverConfig, err := serverTLS.ServerConfig()
+	if err != nil {
+		t.Fatalf("ServerConfig() error = %v", err)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				_, _ = conn.Write([]byte("ok"))
+				conn.Close()
+			}()
+		}
+	}()
+	tests := []struct {
+		name  string
+		tls   TLS
+		valid bool
+	}{
+		{
+			name:  "client certificate",
+			tls:   TLS{CAFile: caFile, CertFile: clientCert, KeyFile: clientKey, ServerName: "dvm.example.com"},
+			valid: true,
+		},
+		{
+			name: "server certificate used by client",
+			tls:  TLS{CAFile: caFile, CertFile: serverCert, KeyFile: serverKey},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.tls.ClientConfig()
+			if err != nil {
+				t.Fatalf("ClientConfig() error = %v", err)
+			}
+			conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+			if err == nil {
+				buf := make([]byte, 2)
+				_, err = conn.Read(buf)
+				conn.Close()
+			}
+			if (err == nil) != tt.valid {
+				t.Errorf("Dial() error = %v, valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	progress := Progress{TotalBytes: 200, TransferredBytes: 50, BytesPerSecond: 1500000}
+	if progress.Percent() != "25%" || progress.Rate() != "1.50MB/s" {
+		t.Errorf("Progress = %s %s", progress.Percent(), progress.Rate())
+	}
+	if (Progress{Completed: true}).Percent() != "100%" {
+		t.Errorf("Percent() empty completed transfer not 100%%")
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	clock := time.Now()
+	slept := time.Duration(0)
+	l := newLimiter(100)
+	l.now = func() time.Time { return clock }
+	l.sleep = func(d time.Duration) {
+		slept += d
+		clock = clock.Add(d)
+	}
+	l.wait(50 * 1024)
+	l.wait(50 * 1024)
+	if slept < 900*time.Millisecond || slept > time.Second {
+		t.Errorf("wait() slept %v, want ~1s", slept)
+	}
+	unlimited := newLimiter(0)
+	unlimited.sleep = func(d time.Duration) { t.Errorf("wait() unlimited slept %v", d) }
+	unlimited.wait(ChunkSize)
+}